@@ -40,7 +40,19 @@ func newAppCmd(cfg config, out io.Writer, packSvc *pack.Client, configDefaultBui
 	cmd.AddCommand(newAppInfoCmd(cfg, out))
 	cmd.AddCommand(newAppStartCmd(cfg, out, appStart))
 	cmd.AddCommand(newAppStopCmd(cfg, out, appStop))
+	cmd.AddCommand(newAppWakeCmd(cfg, out, appWake))
+	cmd.AddCommand(newAppRollbackCmd(cfg, out, appRollback))
+	cmd.AddCommand(newAppCanaryCmd(cfg, out))
+	cmd.AddCommand(newAppPromoteCmd(cfg, out, appPromote))
 	cmd.AddCommand(newAppExportCmd(cfg, exportApp, out))
+	cmd.AddCommand(newAppDiffCmd(cfg, out, appDiff))
+	cmd.AddCommand(newAppResourcesCmd(cfg, out))
+	cmd.AddCommand(newAppAutoscaleCmd(cfg, out))
+	cmd.AddCommand(newAppIdleCmd(cfg, out))
+	cmd.AddCommand(newAppRunCmd(cfg, out, appRun))
+	cmd.AddCommand(newAppExecCmd(cfg, out, appExec))
+	cmd.AddCommand(newAppPortForwardCmd(cfg, out, appPortForward))
+	cmd.AddCommand(newAppEventsCmd(cfg, out))
 	return cmd
 }
 
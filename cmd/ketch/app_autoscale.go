@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/validation"
+)
+
+const appAutoscaleHelp = `
+Manage horizontal autoscaling of an application's processes.
+`
+
+const appAutoscaleSetHelp = `
+Enable horizontal autoscaling of a process of an application.
+The number of units of the process is kept between --min and --max.
+
+  ketch app autoscale set APPNAME --process web --min 2 --max 10 --cpu-percent 70
+  ketch app autoscale set APPNAME --process worker --min 1 --max 5 --metric queue_length=30
+`
+
+const appAutoscaleUnsetHelp = `
+Disable horizontal autoscaling of a process of an application.
+The process keeps its current number of units.
+`
+
+type appAutoscaleSetFn func(context.Context, config, appAutoscaleSetOptions, io.Writer) error
+
+type appAutoscaleUnsetFn func(context.Context, config, appAutoscaleUnsetOptions, io.Writer) error
+
+func newAppAutoscaleCmd(cfg config, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "autoscale",
+		Short: "Manage horizontal autoscaling of an application's processes.",
+		Long:  appAutoscaleHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Usage()
+		},
+	}
+	cmd.AddCommand(newAppAutoscaleSetCmd(cfg, out, appAutoscaleSet))
+	cmd.AddCommand(newAppAutoscaleUnsetCmd(cfg, out, appAutoscaleUnset))
+	return cmd
+}
+
+func newAppAutoscaleSetCmd(cfg config, out io.Writer, appAutoscaleSet appAutoscaleSetFn) *cobra.Command {
+	options := appAutoscaleSetOptions{}
+	cmd := &cobra.Command{
+		Use:   "set APPNAME",
+		Short: "Enable horizontal autoscaling of a process of an application.",
+		Args:  cobra.ExactValidArgs(1),
+		Long:  appAutoscaleSetHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.appName = args[0]
+			if !validation.ValidateName(options.appName) {
+				return ErrInvalidAppName
+			}
+			return appAutoscaleSet(cmd.Context(), cfg, options, out)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return autoCompleteAppNames(cfg, toComplete)
+		},
+	}
+	cmd.Flags().StringVarP(&options.processName, "process", "p", "", "Process name.")
+	cmd.Flags().IntVarP(&options.deploymentVersion, "version", "v", 0, "Deployment version.")
+	cmd.Flags().Int32Var(&options.minUnits, "min", 1, "Minimum number of units.")
+	cmd.Flags().Int32Var(&options.maxUnits, "max", 0, "Maximum number of units.")
+	cmd.Flags().Int32Var(&options.cpuPercent, "cpu-percent", 0, "Target average CPU utilization, in percent of the requested CPU.")
+	cmd.Flags().Int32Var(&options.memoryPercent, "memory-percent", 0, "Target average memory utilization, in percent of the requested memory.")
+	cmd.Flags().StringVar(&options.metric, "metric", "", "Target average value of a custom pod metric, in the format NAME=TARGET.")
+	cmd.MarkFlagRequired("process")
+	cmd.MarkFlagRequired("max")
+	return cmd
+}
+
+func newAppAutoscaleUnsetCmd(cfg config, out io.Writer, appAutoscaleUnset appAutoscaleUnsetFn) *cobra.Command {
+	options := appAutoscaleUnsetOptions{}
+	cmd := &cobra.Command{
+		Use:   "unset APPNAME",
+		Short: "Disable horizontal autoscaling of a process of an application.",
+		Args:  cobra.ExactValidArgs(1),
+		Long:  appAutoscaleUnsetHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.appName = args[0]
+			if !validation.ValidateName(options.appName) {
+				return ErrInvalidAppName
+			}
+			return appAutoscaleUnset(cmd.Context(), cfg, options, out)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return autoCompleteAppNames(cfg, toComplete)
+		},
+	}
+	cmd.Flags().StringVarP(&options.processName, "process", "p", "", "Process name.")
+	cmd.Flags().IntVarP(&options.deploymentVersion, "version", "v", 0, "Deployment version.")
+	cmd.MarkFlagRequired("process")
+	return cmd
+}
+
+type appAutoscaleSetOptions struct {
+	appName           string
+	processName       string
+	deploymentVersion int
+	minUnits          int32
+	maxUnits          int32
+	cpuPercent        int32
+	memoryPercent     int32
+	metric            string
+}
+
+type appAutoscaleUnsetOptions struct {
+	appName           string
+	processName       string
+	deploymentVersion int
+}
+
+// autoscalingSpec returns an autoscaling spec described by the options.
+func (o appAutoscaleSetOptions) autoscalingSpec() (*ketchv1.AutoscalingSpec, error) {
+	if o.minUnits < 1 {
+		return nil, fmt.Errorf("--min must be greater than 0")
+	}
+	if o.maxUnits < o.minUnits {
+		return nil, fmt.Errorf("--max must be greater than or equal to --min")
+	}
+	spec := ketchv1.AutoscalingSpec{
+		MinUnits: o.minUnits,
+		MaxUnits: o.maxUnits,
+	}
+	if o.cpuPercent > 0 {
+		cpuPercent := o.cpuPercent
+		spec.TargetCPUUtilization = &cpuPercent
+	}
+	if o.memoryPercent > 0 {
+		memoryPercent := o.memoryPercent
+		spec.TargetMemoryUtilization = &memoryPercent
+	}
+	if len(o.metric) > 0 {
+		parts := strings.SplitN(o.metric, "=", 2)
+		if len(parts) != 2 || len(parts[0]) == 0 {
+			return nil, fmt.Errorf("invalid --metric %q, the format is NAME=TARGET", o.metric)
+		}
+		target, err := resource.ParseQuantity(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid --metric %q: %w", o.metric, err)
+		}
+		spec.CustomMetric = &ketchv1.CustomMetricSpec{Name: parts[0], TargetAverageValue: target}
+	}
+	if spec.TargetCPUUtilization == nil && spec.TargetMemoryUtilization == nil && spec.CustomMetric == nil {
+		return nil, fmt.Errorf("at least one of --cpu-percent, --memory-percent or --metric is required")
+	}
+	return &spec, nil
+}
+
+func appAutoscaleSet(ctx context.Context, cfg config, options appAutoscaleSetOptions, out io.Writer) error {
+	autoscaling, err := options.autoscalingSpec()
+	if err != nil {
+		return err
+	}
+	s := ketchv1.NewSelector(options.deploymentVersion, options.processName)
+	return updateAppAutoscaling(ctx, cfg, options.appName, s, autoscaling, out)
+}
+
+func appAutoscaleUnset(ctx context.Context, cfg config, options appAutoscaleUnsetOptions, out io.Writer) error {
+	s := ketchv1.NewSelector(options.deploymentVersion, options.processName)
+	return updateAppAutoscaling(ctx, cfg, options.appName, s, nil, out)
+}
+
+func updateAppAutoscaling(ctx context.Context, cfg config, appName string, s ketchv1.Selector, autoscaling *ketchv1.AutoscalingSpec, out io.Writer) error {
+	app := ketchv1.App{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: appName}, &app); err != nil {
+		return fmt.Errorf("failed to get app: %w", err)
+	}
+	if err := app.SetAutoscaling(s, autoscaling); err != nil {
+		return fmt.Errorf("failed to set autoscaling: %w", err)
+	}
+	if err := cfg.Client().Update(ctx, &app); err != nil {
+		return fmt.Errorf("failed to update app: %w", err)
+	}
+	fmt.Fprintln(out, "Successfully updated!")
+	return nil
+}
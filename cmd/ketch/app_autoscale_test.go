@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/mocks"
+	"github.com/theketchio/ketch/internal/utils/conversions"
+)
+
+func int32Ref(i int32) *int32 {
+	return &i
+}
+
+func TestAppAutoscaleSet(t *testing.T) {
+	dashboard := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+		Spec: ketchv1.AppSpec{
+			Deployments: []ketchv1.AppDeploymentSpec{
+				{
+					Version:   1,
+					Processes: []ketchv1.ProcessSpec{{Name: "web", Units: conversions.IntPtr(1)}, {Name: "worker"}},
+				},
+			},
+		},
+	}
+	tests := []struct {
+		name            string
+		options         appAutoscaleSetOptions
+		wantAutoscaling *ketchv1.AutoscalingSpec
+		wantUnits       *int
+		wantErr         string
+	}{
+		{
+			name:    "cpu target",
+			options: appAutoscaleSetOptions{appName: "dashboard", processName: "web", minUnits: 2, maxUnits: 10, cpuPercent: 70},
+			wantAutoscaling: &ketchv1.AutoscalingSpec{
+				MinUnits:             2,
+				MaxUnits:             10,
+				TargetCPUUtilization: int32Ref(70),
+			},
+			wantUnits: conversions.IntPtr(2),
+		},
+		{
+			name:    "custom metric",
+			options: appAutoscaleSetOptions{appName: "dashboard", processName: "web", minUnits: 1, maxUnits: 3, metric: "requests_per_second=100"},
+			wantAutoscaling: &ketchv1.AutoscalingSpec{
+				MinUnits:     1,
+				MaxUnits:     3,
+				CustomMetric: &ketchv1.CustomMetricSpec{Name: "requests_per_second", TargetAverageValue: resource.MustParse("100")},
+			},
+			wantUnits: conversions.IntPtr(1),
+		},
+		{
+			name:    "max below min",
+			options: appAutoscaleSetOptions{appName: "dashboard", processName: "web", minUnits: 3, maxUnits: 2, cpuPercent: 70},
+			wantErr: "--max must be greater than or equal to --min",
+		},
+		{
+			name:    "no targets",
+			options: appAutoscaleSetOptions{appName: "dashboard", processName: "web", minUnits: 1, maxUnits: 2},
+			wantErr: "at least one of --cpu-percent, --memory-percent or --metric is required",
+		},
+		{
+			name:    "invalid metric",
+			options: appAutoscaleSetOptions{appName: "dashboard", processName: "web", minUnits: 1, maxUnits: 2, metric: "requests_per_second"},
+			wantErr: `invalid --metric "requests_per_second", the format is NAME=TARGET`,
+		},
+		{
+			name:    "unknown process",
+			options: appAutoscaleSetOptions{appName: "dashboard", processName: "cron", minUnits: 1, maxUnits: 2, cpuPercent: 70},
+			wantErr: "failed to set autoscaling: process not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &mocks.Configuration{CtrlClientObjects: []runtime.Object{dashboard.DeepCopy()}}
+			out := &bytes.Buffer{}
+			err := appAutoscaleSet(context.Background(), cfg, tt.options, out)
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, "Successfully updated!\n", out.String())
+
+			app := ketchv1.App{}
+			require.Nil(t, cfg.Client().Get(context.Background(), types.NamespacedName{Name: "dashboard"}, &app))
+			require.Equal(t, tt.wantAutoscaling, app.Spec.Deployments[0].Processes[0].Autoscaling)
+			require.Equal(t, tt.wantUnits, app.Spec.Deployments[0].Processes[0].Units)
+			require.Nil(t, app.Spec.Deployments[0].Processes[1].Autoscaling)
+		})
+	}
+}
+
+func TestAppAutoscaleUnset(t *testing.T) {
+	dashboard := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+		Spec: ketchv1.AppSpec{
+			Deployments: []ketchv1.AppDeploymentSpec{
+				{
+					Version: 1,
+					Processes: []ketchv1.ProcessSpec{
+						{Name: "web", Units: conversions.IntPtr(4), Autoscaling: &ketchv1.AutoscalingSpec{MinUnits: 2, MaxUnits: 5, TargetCPUUtilization: int32Ref(70)}},
+					},
+				},
+			},
+		},
+	}
+	cfg := &mocks.Configuration{CtrlClientObjects: []runtime.Object{dashboard}}
+	out := &bytes.Buffer{}
+	err := appAutoscaleUnset(context.Background(), cfg, appAutoscaleUnsetOptions{appName: "dashboard", processName: "web"}, out)
+	require.Nil(t, err)
+
+	app := ketchv1.App{}
+	require.Nil(t, cfg.Client().Get(context.Background(), types.NamespacedName{Name: "dashboard"}, &app))
+	require.Nil(t, app.Spec.Deployments[0].Processes[0].Autoscaling)
+	require.Equal(t, conversions.IntPtr(4), app.Spec.Deployments[0].Processes[0].Units)
+}
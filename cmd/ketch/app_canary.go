@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/validation"
+)
+
+const appCanaryHelp = `
+Manage a canary deployment of an application.
+`
+
+const appCanaryPauseHelp = `
+Pause a canary deployment at its current step.
+Traffic weights stay the same until the canary deployment is resumed, promoted or aborted.
+`
+
+const appCanaryResumeHelp = `
+Resume a paused canary deployment.
+The next step is scheduled one step interval from now.
+`
+
+const appCanaryPromoteHelp = `
+Promote a canary deployment.
+All traffic is routed to the canary deployment, its processes are scaled to the target units
+and the previous deployment is removed.
+`
+
+const appCanaryAbortHelp = `
+Abort a canary deployment.
+All traffic is routed back to the previous deployment.
+`
+
+type appCanaryFn func(context.Context, config, appCanaryOptions, io.Writer) error
+
+func newAppCanaryCmd(cfg config, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "canary",
+		Short: "Manage a canary deployment of an application.",
+		Long:  appCanaryHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Usage()
+		},
+	}
+	cmd.AddCommand(newAppCanaryActionCmd(cfg, out, "pause", "Pause a canary deployment.", appCanaryPauseHelp, appCanaryPause))
+	cmd.AddCommand(newAppCanaryActionCmd(cfg, out, "resume", "Resume a paused canary deployment.", appCanaryResumeHelp, appCanaryResume))
+	cmd.AddCommand(newAppCanaryActionCmd(cfg, out, "promote", "Promote a canary deployment.", appCanaryPromoteHelp, appCanaryPromote))
+	cmd.AddCommand(newAppCanaryActionCmd(cfg, out, "abort", "Abort a canary deployment.", appCanaryAbortHelp, appCanaryAbort))
+	return cmd
+}
+
+func newAppCanaryActionCmd(cfg config, out io.Writer, action, short, long string, appCanary appCanaryFn) *cobra.Command {
+	options := appCanaryOptions{}
+	cmd := &cobra.Command{
+		Use:   fmt.Sprintf("%s APPNAME", action),
+		Short: short,
+		Args:  cobra.ExactValidArgs(1),
+		Long:  long,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.appName = args[0]
+			if !validation.ValidateName(options.appName) {
+				return ErrInvalidAppName
+			}
+			return appCanary(cmd.Context(), cfg, options, out)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return autoCompleteAppNames(cfg, toComplete)
+		},
+	}
+	return cmd
+}
+
+type appCanaryOptions struct {
+	appName string
+}
+
+func appCanaryPause(ctx context.Context, cfg config, options appCanaryOptions, out io.Writer) error {
+	if err := updateAppCanary(ctx, cfg, options.appName, func(app *ketchv1.App) error {
+		return app.PauseCanary()
+	}); err != nil {
+		return fmt.Errorf("failed to pause canary: %w", err)
+	}
+	fmt.Fprintln(out, "Successfully paused!")
+	return nil
+}
+
+func appCanaryResume(ctx context.Context, cfg config, options appCanaryOptions, out io.Writer) error {
+	if err := updateAppCanary(ctx, cfg, options.appName, func(app *ketchv1.App) error {
+		return app.ResumeCanary(metav1.NewTime(time.Now()))
+	}); err != nil {
+		return fmt.Errorf("failed to resume canary: %w", err)
+	}
+	fmt.Fprintln(out, "Successfully resumed!")
+	return nil
+}
+
+func appCanaryPromote(ctx context.Context, cfg config, options appCanaryOptions, out io.Writer) error {
+	if err := updateAppCanary(ctx, cfg, options.appName, func(app *ketchv1.App) error {
+		return app.PromoteCanary()
+	}); err != nil {
+		return fmt.Errorf("failed to promote canary: %w", err)
+	}
+	fmt.Fprintln(out, "Successfully promoted!")
+	return nil
+}
+
+func appCanaryAbort(ctx context.Context, cfg config, options appCanaryOptions, out io.Writer) error {
+	if err := updateAppCanary(ctx, cfg, options.appName, func(app *ketchv1.App) error {
+		return app.AbortCanary()
+	}); err != nil {
+		return fmt.Errorf("failed to abort canary: %w", err)
+	}
+	fmt.Fprintln(out, "Successfully aborted!")
+	return nil
+}
+
+func updateAppCanary(ctx context.Context, cfg config, appName string, update func(app *ketchv1.App) error) error {
+	app := ketchv1.App{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: appName}, &app); err != nil {
+		return fmt.Errorf("failed to get app: %w", err)
+	}
+	if err := update(&app); err != nil {
+		return err
+	}
+	if err := cfg.Client().Update(ctx, &app); err != nil {
+		return fmt.Errorf("failed to update app: %w", err)
+	}
+	return nil
+}
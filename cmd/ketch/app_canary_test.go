@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/mocks"
+)
+
+func TestNewAppCanaryActionCmd(t *testing.T) {
+	pflag.CommandLine = pflag.NewFlagSet("ketch", pflag.ExitOnError)
+
+	tt := []struct {
+		description string
+		args        []string
+		appCanary   appCanaryFn
+		wantErr     bool
+	}{
+		{
+			description: "happy path",
+			args:        []string{"ketch", "myapp"},
+			appCanary: func(_ context.Context, _ config, opts appCanaryOptions, _ io.Writer) error {
+				require.Equal(t, "myapp", opts.appName)
+				return nil
+			},
+		},
+		{
+			description: "missing positional",
+			args:        []string{"ketch"},
+			wantErr:     true,
+		},
+		{
+			description: "extra positional",
+			args:        []string{"ketch", "myapp", "extra"},
+			wantErr:     true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.description, func(t *testing.T) {
+			os.Args = tc.args
+			cmd := newAppCanaryActionCmd(nil, nil, "pause", "", "", tc.appCanary)
+			err := cmd.Execute()
+			if tc.wantErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+		})
+	}
+}
+
+func TestAppCanary(t *testing.T) {
+	canaryApp := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "go-app",
+		},
+		Spec: ketchv1.AppSpec{
+			Deployments: []ketchv1.AppDeploymentSpec{
+				{Version: 1, Image: "shipasoftware/go-app:v1", RoutingSettings: ketchv1.RoutingSettings{Weight: 80}},
+				{Version: 2, Image: "shipasoftware/go-app:v2", RoutingSettings: ketchv1.RoutingSettings{Weight: 20}},
+			},
+			Canary: ketchv1.CanarySpec{
+				Steps:       5,
+				StepWeight:  20,
+				CurrentStep: 2,
+				Active:      true,
+			},
+			Framework: "aws",
+		},
+	}
+	app := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "go-app",
+		},
+		Spec: ketchv1.AppSpec{
+			Deployments: []ketchv1.AppDeploymentSpec{
+				{Version: 1, Image: "shipasoftware/go-app:v1", RoutingSettings: ketchv1.RoutingSettings{Weight: 100}},
+			},
+			Framework: "aws",
+		},
+	}
+	tests := []struct {
+		name       string
+		cfg        config
+		appCanary  appCanaryFn
+		wantOutput string
+		wantApp    func(t *testing.T, app ketchv1.App)
+		wantErr    bool
+	}{
+		{
+			name:       "pause",
+			cfg:        &mocks.Configuration{CtrlClientObjects: []runtime.Object{canaryApp}},
+			appCanary:  appCanaryPause,
+			wantOutput: "Successfully paused!\n",
+			wantApp: func(t *testing.T, app ketchv1.App) {
+				require.True(t, app.Spec.Canary.Paused)
+			},
+		},
+		{
+			name:       "promote",
+			cfg:        &mocks.Configuration{CtrlClientObjects: []runtime.Object{canaryApp}},
+			appCanary:  appCanaryPromote,
+			wantOutput: "Successfully promoted!\n",
+			wantApp: func(t *testing.T, app ketchv1.App) {
+				require.True(t, app.Spec.Canary.Promoted)
+			},
+		},
+		{
+			name:       "abort",
+			cfg:        &mocks.Configuration{CtrlClientObjects: []runtime.Object{canaryApp}},
+			appCanary:  appCanaryAbort,
+			wantOutput: "Successfully aborted!\n",
+			wantApp: func(t *testing.T, app ketchv1.App) {
+				require.True(t, app.Spec.Canary.Aborted)
+			},
+		},
+		{
+			name:      "no active canary",
+			cfg:       &mocks.Configuration{CtrlClientObjects: []runtime.Object{app}},
+			appCanary: appCanaryResume,
+			wantErr:   true,
+		},
+		{
+			name:      "no app",
+			cfg:       &mocks.Configuration{CtrlClientObjects: []runtime.Object{}},
+			appCanary: appCanaryPause,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			err := tt.appCanary(context.Background(), tt.cfg, appCanaryOptions{appName: "go-app"}, out)
+			if tt.wantErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.wantOutput, out.String())
+			gotApp := ketchv1.App{}
+			err = tt.cfg.Client().Get(context.Background(), types.NamespacedName{Name: "go-app"}, &gotApp)
+			require.Nil(t, err)
+			tt.wantApp(t, gotApp)
+		})
+	}
+}
@@ -1,9 +1,22 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
 	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/chart"
 	"github.com/theketchio/ketch/internal/deploy"
+	"github.com/theketchio/ketch/internal/templates"
 	"github.com/theketchio/ketch/internal/validation"
 )
 
@@ -28,9 +41,27 @@ Users can deploy from image or source code by passing a filename such as app.yam
 	name: test
 	image: gcr.io/shipa-ci/sample-go-app:latest
 	framework: myframework
+
+Preview a deployment without changing anything in the cluster:
+  ketch app deploy <app name> -i myregistry/myimage:latest --dry-run
+  ketch app deploy <app name> -i myregistry/myimage:latest --dry-run --diff
+
+Wait until the deployment is rolled out:
+  ketch app deploy <app name> -i myregistry/myimage:latest --wait --timeout 5m
+
+  On a terminal, the progress of each process is updated in place, otherwise the timeline is printed line by line.
+  A canary deployment is followed until all its steps are finished. If its steps take longer
+  than --timeout, the command exits successfully once the timeout expires and shows the current canary step and weight.
+  The command exits with code 3 on timeout, 4 if units fail their healthcheck and 5 if a canary deployment is rolled back.
 `
 )
 
+const (
+	exitCodeDeployTimeout     = 3
+	exitCodeHealthcheckFailed = 4
+	exitCodeCanaryRolledBack  = 5
+)
+
 // NewCommand creates a command that will run the app deploy
 func newAppDeployCmd(cfg config, params *deploy.Services, configDefaultBuilder string) *cobra.Command {
 	var options deploy.Options
@@ -48,7 +79,7 @@ func newAppDeployCmd(cfg config, params *deploy.Services, configDefaultBuilder s
 			if configDefaultBuilder != "" {
 				deploy.DefaultBuilder = configDefaultBuilder
 			}
-			return appDeploy(cmd, options, params)
+			return appDeploy(cmd, cfg, options, params)
 		},
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			return autoCompleteAppNames(cfg, toComplete)
@@ -61,8 +92,14 @@ func newAppDeployCmd(cfg config, params *deploy.Services, configDefaultBuilder s
 	cmd.Flags().BoolVar(&options.StrictKetchYamlDecoding, deploy.FlagStrict, false, "Enforces strict decoding of ketch.yaml.")
 	cmd.Flags().IntVar(&options.Steps, deploy.FlagSteps, 0, "Number of steps for a canary deployment.")
 	cmd.Flags().StringVar(&options.StepTimeInterval, deploy.FlagStepInterval, "", "Time interval between canary deployment steps. Supported min: m, hour:h, second:s. ex. 1m, 60s, 1h.")
-	cmd.Flags().BoolVar(&options.Wait, deploy.FlagWait, false, "If true blocks until deploy completes or a timeout occurs.")
+	cmd.Flags().StringVar(&options.CanaryAnalysisFileName, deploy.FlagCanaryAnalysis, "", "Path to a yaml file with metrics checked before each canary deployment step.")
+	cmd.Flags().StringVar(&options.CanaryHeader, deploy.FlagCanaryHeader, "", "Route requests with this header to the canary deployment regardless of its weight, in NAME=VALUE format. Requires the istio or traefik ingress controller.")
+	cmd.Flags().StringVar(&options.Strategy, deploy.FlagStrategy, "", "Deployment strategy, either canary or blue-green. Canary is used when steps are set.")
+	cmd.Flags().StringVar(&options.GracePeriod, deploy.FlagGracePeriod, "", "Time to keep the previous deployment after a blue-green deployment is promoted. Defaults to 10m.")
+	cmd.Flags().BoolVar(&options.Wait, deploy.FlagWait, false, "If true streams the deployment timeline and blocks until deploy completes or a timeout occurs.")
 	cmd.Flags().StringVar(&options.Timeout, deploy.FlagTimeout, "20s", "Defines the length of time to block waiting for deployment completion. Supported min: m, hour:h, second:s. ex. 1m, 60s, 1h.")
+	cmd.Flags().BoolVar(&options.DryRun, deploy.FlagDryRun, false, "If true prints the manifests the deployment would produce without changing anything in the cluster.")
+	cmd.Flags().BoolVar(&options.Diff, deploy.FlagDiff, false, "Used with dry-run, prints a diff against the manifests of the running app instead.")
 
 	cmd.Flags().StringVarP(&options.Description, deploy.FlagDescription, deploy.FlagDescriptionShort, "", "App description.")
 	cmd.Flags().StringSliceVarP(&options.Envs, deploy.FlagEnvironment, deploy.FlagEnvironmentShort, []string{}, "App env variables.")
@@ -84,7 +121,10 @@ func newAppDeployCmd(cfg config, params *deploy.Services, configDefaultBuilder s
 	return cmd
 }
 
-func appDeploy(cmd *cobra.Command, options deploy.Options, params *deploy.Services) error {
+func appDeploy(cmd *cobra.Command, cfg config, options deploy.Options, params *deploy.Services) error {
+	if options.Diff && !options.DryRun {
+		return fmt.Errorf("%q flag must be used with %q flag", deploy.FlagDiff, deploy.FlagDryRun)
+	}
 	var changeSet *deploy.ChangeSet
 	var err error
 	switch {
@@ -96,5 +136,110 @@ func appDeploy(cmd *cobra.Command, options deploy.Options, params *deploy.Servic
 	default:
 		changeSet = options.GetChangeSet(cmd.Flags())
 	}
-	return deploy.New(changeSet).Run(cmd.Context(), params)
+	if options.DryRun {
+		return appDeployDryRun(cmd.Context(), cfg, changeSet, params, options.Diff, newHelmChartRenderer)
+	}
+	return deployWaitError(deploy.New(changeSet).Run(cmd.Context(), params))
+}
+
+// deployWaitError returns an exitCodeError if the deployment failed while waiting for it to be rolled out.
+func deployWaitError(err error) error {
+	switch {
+	case errors.Is(err, deploy.ErrWaitTimeout):
+		return exitCodeError{code: exitCodeDeployTimeout, message: err.Error()}
+	case errors.Is(err, deploy.ErrHealthcheckFailed):
+		return exitCodeError{code: exitCodeHealthcheckFailed, message: err.Error()}
+	case errors.Is(err, deploy.ErrCanaryRolledBack):
+		return exitCodeError{code: exitCodeCanaryRolledBack, message: err.Error()}
+	}
+	return err
+}
+
+// chartRenderer renders app charts and reads manifests of installed helm releases.
+type chartRenderer interface {
+	RenderChart(tv chart.TemplateValuer, config chart.ChartConfig) (string, error)
+	GetManifest(appName string) (string, error)
+}
+
+type newChartRendererFn func(namespace string, c client.Client) (chartRenderer, error)
+
+func newHelmChartRenderer(namespace string, c client.Client) (chartRenderer, error) {
+	return chart.NewHelmClientForConfigFlags(namespace, genericclioptions.NewConfigFlags(true), c)
+}
+
+// appDeployDryRun computes the app the deployment would produce, validates it with the rules of the app admission webhook
+// and renders it with the framework's templates. It prints either the manifests or a diff against the app's helm release.
+func appDeployDryRun(ctx context.Context, cfg config, changeSet *deploy.ChangeSet, svc *deploy.Services, diff bool, newRenderer newChartRendererFn) error {
+	app, err := deploy.New(changeSet).DryRun(ctx, svc)
+	if err != nil {
+		return err
+	}
+
+	var liveApp *ketchv1.App
+	current := ketchv1.App{}
+	err = cfg.Client().Get(ctx, types.NamespacedName{Name: app.Name}, &current)
+	switch {
+	case err == nil:
+		liveApp = &current
+	case !apierrors.IsNotFound(err):
+		return fmt.Errorf("failed to get app: %w", err)
+	}
+	app.Default()
+	if err := app.Validate(ctx, cfg.Client(), liveApp); err != nil {
+		return fmt.Errorf("app validation failed: %w", err)
+	}
+
+	framework := ketchv1.Framework{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: app.Spec.Framework}, &framework); err != nil {
+		return fmt.Errorf("failed to get framework: %w", err)
+	}
+	tpls, err := cfg.Storage().Get(templates.IngressConfigMapName(framework.Spec.IngressController.IngressType.String()))
+	if err != nil {
+		return fmt.Errorf("failed to get templates: %w", err)
+	}
+	appChart, err := chart.New(app, &framework, chart.WithExposedPorts(app.ExposedPorts()), chart.WithTemplates(*tpls))
+	if err != nil {
+		return fmt.Errorf("failed to create chart: %w", err)
+	}
+	renderer, err := newRenderer(framework.Spec.NamespaceName, cfg.Client())
+	if err != nil {
+		return err
+	}
+	manifest, err := renderer.RenderChart(*appChart, chart.NewChartConfig(*app))
+	if err != nil {
+		return fmt.Errorf("failed to render chart: %w", err)
+	}
+	if !diff {
+		fmt.Fprintln(svc.Writer, manifest)
+		return nil
+	}
+
+	liveManifest, err := renderer.GetManifest(app.Name)
+	if err != nil {
+		return fmt.Errorf("failed to get app release: %w", err)
+	}
+	manifestDiff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        manifestLines(liveManifest),
+		B:        manifestLines(manifest),
+		FromFile: fmt.Sprintf("%s (live)", app.Name),
+		ToFile:   fmt.Sprintf("%s (dry run)", app.Name),
+		Context:  3,
+	})
+	if err != nil {
+		return err
+	}
+	if manifestDiff == "" {
+		fmt.Fprintln(svc.Writer, "No changes.")
+		return nil
+	}
+	fmt.Fprint(svc.Writer, manifestDiff)
+	return nil
+}
+
+func manifestLines(manifest string) []string {
+	lines := strings.SplitAfter(manifest, "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
 }
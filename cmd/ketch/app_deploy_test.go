@@ -3,6 +3,8 @@ package main
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path"
@@ -20,8 +22,11 @@ import (
 
 	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
 	"github.com/theketchio/ketch/internal/build"
+	"github.com/theketchio/ketch/internal/chart"
 	"github.com/theketchio/ketch/internal/deploy"
+	"github.com/theketchio/ketch/internal/mocks"
 	"github.com/theketchio/ketch/internal/pack"
+	"github.com/theketchio/ketch/internal/templates"
 )
 
 type getterCreatorMockFn func(m *mockClient, obj runtime.Object) error
@@ -942,6 +947,68 @@ func TestNewCommand(t *testing.T) {
 				Writer:         &bytes.Buffer{},
 			},
 		},
+		{
+			name: "diff without dry run",
+			arguments: []string{
+				"myapp",
+				"--image", "shipa/go-sample:latest",
+				"--diff",
+			},
+			params: &deploy.Services{
+				Client:         newMockClient(),
+				KubeClient:     fake.NewSimpleClientset(),
+				GetImageConfig: getImageConfig,
+				Writer:         &bytes.Buffer{},
+			},
+			wantError: true,
+		},
+		{
+			name: "canary header with istio ingress",
+			arguments: []string{
+				"myapp",
+				"--image", "shipa/go-sample:latest",
+				"--steps", "4",
+				"--step-interval", "1h",
+				"--canary-header", "X-Canary=true",
+			},
+			validate: func(t *testing.T, mock *mockClient) {
+				require.Len(t, mock.app.Spec.Deployments, 2)
+				require.Equal(t, []ketchv1.RouteMatch{{Type: ketchv1.RouteMatchHeader, Name: "X-Canary", Value: "true"}}, mock.app.Spec.Deployments[1].RoutingSettings.Match)
+			},
+			params: &deploy.Services{
+				Client: func() *mockClient {
+					m := newMockClient()
+					m.framework.Spec.IngressController.IngressType = ketchv1.IstioIngressControllerType
+					m.app.Spec.Deployments = []ketchv1.AppDeploymentSpec{{Image: "shipa/go-sample:latest", Version: 1}}
+					return m
+				}(),
+				KubeClient:     fake.NewSimpleClientset(),
+				GetImageConfig: getImageConfig,
+				Writer:         &bytes.Buffer{},
+			},
+		},
+		{
+			name: "canary header with nginx ingress",
+			arguments: []string{
+				"myapp",
+				"--image", "shipa/go-sample:latest",
+				"--steps", "4",
+				"--step-interval", "1h",
+				"--canary-header", "X-Canary=true",
+			},
+			params: &deploy.Services{
+				Client: func() *mockClient {
+					m := newMockClient()
+					m.framework.Spec.IngressController.IngressType = ketchv1.NginxIngressControllerType
+					m.app.Spec.Deployments = []ketchv1.AppDeploymentSpec{{Image: "shipa/go-sample:latest", Version: 1}}
+					return m
+				}(),
+				KubeClient:     fake.NewSimpleClientset(),
+				GetImageConfig: getImageConfig,
+				Writer:         &bytes.Buffer{},
+			},
+			wantError: true,
+		},
 	}
 
 	for _, tc := range tt {
@@ -974,3 +1041,174 @@ func TestNewCommand(t *testing.T) {
 		})
 	}
 }
+
+type mockChartRenderer struct {
+	liveManifest string
+}
+
+func (m *mockChartRenderer) RenderChart(tv chart.TemplateValuer, config chart.ChartConfig) (string, error) {
+	var values struct {
+		App struct {
+			Deployments []struct {
+				Image string `json:"image"`
+			} `json:"deployments"`
+		} `json:"app"`
+	}
+	data, err := json.Marshal(tv.GetValues())
+	if err != nil {
+		return "", err
+	}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return "", err
+	}
+	deployments := values.App.Deployments
+	return fmt.Sprintf("image: %s\n", deployments[len(deployments)-1].Image), nil
+}
+
+func (m *mockChartRenderer) GetManifest(appName string) (string, error) {
+	return m.liveManifest, nil
+}
+
+func Test_appDeployDryRun(t *testing.T) {
+	framework := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "myframework"},
+		Spec: ketchv1.FrameworkSpec{
+			NamespaceName: "ketch-myframework",
+			IngressController: ketchv1.IngressControllerSpec{
+				IngressType: ketchv1.NginxIngressControllerType,
+			},
+		},
+		Status: ketchv1.FrameworkStatus{Apps: []string{"myapp"}},
+	}
+	myapp := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "myapp"},
+		Spec: ketchv1.AppSpec{
+			Framework:        "myframework",
+			DeploymentsCount: 1,
+			Deployments: []ketchv1.AppDeploymentSpec{
+				{
+					Image:   "shipa/go-sample:v1",
+					Version: 1,
+					Processes: []ketchv1.ProcessSpec{
+						{Name: "web", Cmd: []string{"/bin/eatme"}},
+					},
+					RoutingSettings: ketchv1.RoutingSettings{Weight: 100},
+				},
+			},
+		},
+	}
+	tests := []struct {
+		name         string
+		options      deploy.Options
+		liveManifest string
+		wantOut      string
+		wantErr      string
+	}{
+		{
+			name:    "print manifests",
+			options: deploy.Options{AppName: "myapp", Image: "shipa/go-sample:v2"},
+			wantOut: "image: shipa/go-sample:v2\n\n",
+		},
+		{
+			name:         "print diff",
+			options:      deploy.Options{AppName: "myapp", Image: "shipa/go-sample:v2", Diff: true},
+			liveManifest: "image: shipa/go-sample:v1\n",
+			wantOut: `--- myapp (live)
++++ myapp (dry run)
+@@ -1 +1 @@
+-image: shipa/go-sample:v1
++image: shipa/go-sample:v2
+`,
+		},
+		{
+			name:         "no changes",
+			options:      deploy.Options{AppName: "myapp", Image: "shipa/go-sample:v2", Diff: true},
+			liveManifest: "image: shipa/go-sample:v2\n",
+			wantOut:      "No changes.\n",
+		},
+		{
+			name:    "invalid env name",
+			options: deploy.Options{AppName: "myapp", Image: "shipa/go-sample:v2", Envs: []string{"1FOO=bar"}},
+			wantErr: "app validation failed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{framework, myapp},
+				StorageInstance: &mockStorage{
+					OnGet: func(name string) (*templates.Templates, error) {
+						return &templates.NginxDefaultTemplates, nil
+					},
+				},
+			}
+			out := &bytes.Buffer{}
+			svc := &deploy.Services{
+				Client:         cfg.Client(),
+				GetImageConfig: getImageConfig,
+				Writer:         out,
+			}
+			flags := newAppDeployCmd(cfg, svc, "").Flags()
+			require.Nil(t, flags.Set(deploy.FlagImage, tt.options.Image))
+			for _, env := range tt.options.Envs {
+				require.Nil(t, flags.Set(deploy.FlagEnvironment, env))
+			}
+			changeSet := tt.options.GetChangeSet(flags)
+			renderer := &mockChartRenderer{liveManifest: tt.liveManifest}
+			newRenderer := func(namespace string, c client.Client) (chartRenderer, error) {
+				require.Equal(t, "ketch-myframework", namespace)
+				return renderer, nil
+			}
+
+			err := appDeployDryRun(context.Background(), cfg, changeSet, svc, tt.options.Diff, newRenderer)
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.wantOut, out.String())
+
+			live := ketchv1.App{}
+			require.Nil(t, cfg.Client().Get(context.Background(), client.ObjectKey{Name: "myapp"}, &live))
+			require.Equal(t, "shipa/go-sample:v1", live.Spec.Deployments[0].Image)
+		})
+	}
+}
+
+func Test_deployWaitError(t *testing.T) {
+	tests := []struct {
+		description string
+		err         error
+		wantErr     error
+	}{
+		{
+			description: "timeout",
+			err:         fmt.Errorf("%w: deployment exceeded its progress deadline", deploy.ErrWaitTimeout),
+			wantErr:     exitCodeError{code: exitCodeDeployTimeout, message: "deployment timed out: deployment exceeded its progress deadline"},
+		},
+		{
+			description: "healthcheck failed",
+			err:         fmt.Errorf("%w: error waiting for healthcheck", deploy.ErrHealthcheckFailed),
+			wantErr:     exitCodeError{code: exitCodeHealthcheckFailed, message: "healthcheck failed: error waiting for healthcheck"},
+		},
+		{
+			description: "canary rolled back",
+			err:         deploy.ErrCanaryRolledBack,
+			wantErr:     exitCodeError{code: exitCodeCanaryRolledBack, message: "canary deployment rolled back"},
+		},
+		{
+			description: "other error",
+			err:         fmt.Errorf("no framework"),
+			wantErr:     fmt.Errorf("no framework"),
+		},
+		{
+			description: "no error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			require.Equal(t, tt.wantErr, deployWaitError(tt.err))
+		})
+	}
+}
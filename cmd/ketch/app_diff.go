@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/theketchio/ketch/cmd/ketch/output"
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/deploy"
+)
+
+const appDiffHelp = `
+Compare an application.yaml file with the app running in the cluster.
+Image, framework, builder, cname, environment variables and units, environment variables and resources
+of processes are compared. Fields that are not set in the file are skipped.
+
+The command exits with a non-zero code when the app has drifted from the file:
+  ketch app diff -f application.yaml
+`
+
+type appDiffFn func(context.Context, config, appDiffOptions, io.Writer) error
+
+func newAppDiffCmd(cfg config, out io.Writer, appDiff appDiffFn) *cobra.Command {
+	options := appDiffOptions{}
+	cmd := &cobra.Command{
+		Use:   "diff -f FILENAME",
+		Short: "Compare an application.yaml with the app running in the cluster.",
+		Args:  cobra.NoArgs,
+		Long:  appDiffHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return appDiff(cmd.Context(), cfg, options, out)
+		},
+	}
+	cmd.Flags().StringVarP(&options.filename, "file", "f", "", "Path to application.yaml.")
+	cmd.MarkFlagRequired("file")
+	return cmd
+}
+
+type appDiffOptions struct {
+	filename string
+}
+
+func appDiff(ctx context.Context, cfg config, options appDiffOptions, out io.Writer) error {
+	changeSet, err := deploy.GetDriftChangeSetFromYaml(options.filename)
+	if err != nil {
+		return fmt.Errorf("failed to read application file: %w", err)
+	}
+	app := ketchv1.App{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: changeSet.AppName()}, &app); err != nil {
+		return fmt.Errorf("failed to get app: %w", err)
+	}
+	drift, err := changeSet.GetDrift(app)
+	if err != nil {
+		return fmt.Errorf("failed to compare app: %w", err)
+	}
+	if len(drift) == 0 {
+		fmt.Fprintln(out, "No differences found.")
+		return nil
+	}
+	if err := output.Write(drift, out, "column"); err != nil {
+		return err
+	}
+	return ErrAppDrifted
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/mocks"
+)
+
+func TestNewAppDiffCmd(t *testing.T) {
+	pflag.CommandLine = pflag.NewFlagSet("ketch", pflag.ExitOnError)
+
+	tt := []struct {
+		description string
+		args        []string
+		appDiff     appDiffFn
+		wantErr     bool
+	}{
+		{
+			description: "happy path",
+			args:        []string{"ketch", "-f", "application.yaml"},
+			appDiff: func(_ context.Context, _ config, opts appDiffOptions, _ io.Writer) error {
+				require.Equal(t, "application.yaml", opts.filename)
+				return nil
+			},
+		},
+		{
+			description: "unexpected source directory",
+			args:        []string{"ketch", "-f", "application.yaml", "src"},
+			wantErr:     true,
+		},
+		{
+			description: "missing file flag",
+			args:        []string{"ketch"},
+			wantErr:     true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.description, func(t *testing.T) {
+			os.Args = tc.args
+			cmd := newAppDiffCmd(nil, nil, tc.appDiff)
+			err := cmd.Execute()
+			if tc.wantErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+		})
+	}
+}
+
+func TestAppDiff(t *testing.T) {
+	goApp := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "go-app",
+		},
+		Spec: ketchv1.AppSpec{
+			Framework: "aws",
+			Deployments: []ketchv1.AppDeploymentSpec{
+				{Version: 1, Image: "shipasoftware/go-app:v1"},
+			},
+		},
+	}
+	tests := []struct {
+		description string
+		yaml        string
+		wantOut     string
+		wantErr     error
+	}{
+		{
+			description: "no drift",
+			yaml: `name: go-app
+image: shipasoftware/go-app:v1
+framework: aws
+`,
+			wantOut: "No differences found.\n",
+		},
+		{
+			description: "drift",
+			yaml: `name: go-app
+image: shipasoftware/go-app:v2
+framework: aws
+`,
+			wantOut: "FIELD    LOCAL                      LIVE\nimage    shipasoftware/go-app:v2    shipasoftware/go-app:v1\n",
+			wantErr: ErrAppDrifted,
+		},
+		{
+			description: "processes without a source directory",
+			yaml: `name: go-app
+image: shipasoftware/go-app:v1
+framework: aws
+processes:
+  - name: web
+`,
+			wantOut: "FIELD                LOCAL    LIVE\nprocess web units    1        <none>\n",
+			wantErr: ErrAppDrifted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "application.yaml")
+			require.Nil(t, os.WriteFile(filename, []byte(tt.yaml), 0600))
+			cfg := &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{goApp},
+			}
+			out := &bytes.Buffer{}
+			err := appDiff(context.Background(), cfg, appDiffOptions{filename: filename}, out)
+			require.Equal(t, tt.wantErr, err)
+			require.Equal(t, tt.wantOut, out.String())
+		})
+	}
+}
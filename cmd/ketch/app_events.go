@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/deploy"
+	"github.com/theketchio/ketch/internal/validation"
+)
+
+const appEventsHelp = `
+Show the deployment timeline of an application: units created and ready, healthcheck waits,
+canary steps with their weights and errors. Each line shows the deployment version and the process
+the event is about.
+
+  ketch app events APPNAME
+  ketch app events APPNAME --version 3
+  ketch app events APPNAME --watch
+`
+
+func newAppEventsCmd(cfg config, out io.Writer) *cobra.Command {
+	options := appEventsOptions{}
+	cmd := &cobra.Command{
+		Use:   "events APPNAME",
+		Short: "Show the deployment timeline of an application.",
+		Args:  cobra.ExactValidArgs(1),
+		Long:  appEventsHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.appName = args[0]
+			if !validation.ValidateName(options.appName) {
+				return ErrInvalidAppName
+			}
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			return appEvents(ctx, cfg, options, out)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return autoCompleteAppNames(cfg, toComplete)
+		},
+	}
+	cmd.Flags().BoolVarP(&options.watch, "watch", "w", false, "Keep watching for new events.")
+	cmd.Flags().IntVarP(&options.deploymentVersion, "version", "v", 0, "Show events of the deployment version only.")
+	return cmd
+}
+
+type appEventsOptions struct {
+	appName           string
+	watch             bool
+	deploymentVersion int
+}
+
+func appEvents(ctx context.Context, cfg config, options appEventsOptions, out io.Writer) error {
+	app := ketchv1.App{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.appName}, &app); err != nil {
+		return fmt.Errorf("failed to get app: %w", err)
+	}
+	events, watcher, err := deploy.WatchAppEvents(ctx, cfg.KubernetesClient(), app.Name)
+	if err != nil {
+		return fmt.Errorf("failed to get events: %w", err)
+	}
+	defer watcher.Stop()
+
+	shown := 0
+	for _, evt := range events {
+		if printTimelineEntry(evt, options.deploymentVersion, out) {
+			shown++
+		}
+	}
+	if !options.watch {
+		if shown == 0 {
+			fmt.Fprintln(out, "No events.")
+		}
+		return nil
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-watcher.ResultChan():
+			if !ok {
+				return nil
+			}
+			evt, ok := msg.Object.(*corev1.Event)
+			if !ok || (msg.Type != watch.Added && msg.Type != watch.Modified) {
+				continue
+			}
+			printTimelineEntry(*evt, options.deploymentVersion, out)
+		}
+	}
+}
+
+// printTimelineEntry prints the event if it's about the deployment version or the version is zero.
+func printTimelineEntry(evt corev1.Event, deploymentVersion int, out io.Writer) bool {
+	entry := deploy.NewTimelineEntry(evt)
+	if deploymentVersion > 0 && entry.Version != deploymentVersion {
+		return false
+	}
+	fmt.Fprintln(out, entry.String())
+	return true
+}
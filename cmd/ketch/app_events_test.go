@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/mocks"
+)
+
+func TestAppEvents(t *testing.T) {
+	now := time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC)
+	dashboard := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+		Spec:       ketchv1.AppSpec{Framework: "gke"},
+	}
+	deploymentEvent := func(name string, version string, process string, desc string, seconds int) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Annotations: map[string]string{
+					ketchv1.DeploymentAnnotationAppName:            "dashboard",
+					ketchv1.DeploymentAnnotationDevelopmentVersion: version,
+					ketchv1.DeploymentAnnotationEventName:          ketchv1.AppReconcileUpdate,
+					ketchv1.DeploymentAnnotationDescription:        desc,
+					ketchv1.DeploymentAnnotationProcessName:        process,
+				},
+			},
+			Type:          corev1.EventTypeNormal,
+			Reason:        ketchv1.AppReconcileUpdate,
+			LastTimestamp: metav1.NewTime(now.Add(time.Duration(seconds) * time.Second)),
+		}
+	}
+	canaryEvent := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "canary",
+			Namespace: "default",
+			Annotations: map[string]string{
+				ketchv1.CanaryAnnotationDevelopmentVersion: "2",
+				ketchv1.CanaryAnnotationEventName:          ketchv1.CanaryNextStep,
+				ketchv1.CanaryAnnotationStep:               "1",
+				ketchv1.CanaryAnnotationVersionSource:      "1",
+				ketchv1.CanaryAnnotationVersionDest:        "2",
+				ketchv1.CanaryAnnotationWeightSource:       "90",
+				ketchv1.CanaryAnnotationWeightDest:         "10",
+			},
+		},
+		Type:          corev1.EventTypeNormal,
+		Reason:        ketchv1.CanaryNextStep,
+		LastTimestamp: metav1.NewTime(now.Add(time.Minute)),
+	}
+	events := []runtime.Object{
+		canaryEvent,
+		deploymentEvent("web-2", "2", "web", "1 of 1 new units ready", 30),
+		deploymentEvent("web-1", "1", "web", "1 of 1 new units ready", 0),
+	}
+	tests := []struct {
+		description string
+		cfg         config
+		options     appEventsOptions
+		wantOut     string
+		wantErr     string
+	}{
+		{
+			description: "timeline",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{dashboard},
+				KubeClientObjects: events,
+			},
+			options: appEventsOptions{appName: "dashboard"},
+			wantOut: `2021-10-01T12:00:00Z  v1   web          1 of 1 new units ready
+2021-10-01T12:00:30Z  v2   web          1 of 1 new units ready
+2021-10-01T12:01:00Z  v2   -            canary step 1: weight 90% on version 1, 10% on version 2
+`,
+		},
+		{
+			description: "version",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{dashboard},
+				KubeClientObjects: events,
+			},
+			options: appEventsOptions{appName: "dashboard", deploymentVersion: 1},
+			wantOut: "2021-10-01T12:00:00Z  v1   web          1 of 1 new units ready\n",
+		},
+		{
+			description: "watch",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{dashboard},
+				KubeClientObjects: events,
+			},
+			options: appEventsOptions{appName: "dashboard", deploymentVersion: 1, watch: true},
+			wantOut: "2021-10-01T12:00:00Z  v1   web          1 of 1 new units ready\n",
+		},
+		{
+			description: "no events",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{dashboard},
+			},
+			options: appEventsOptions{appName: "dashboard"},
+			wantOut: "No events.\n",
+		},
+		{
+			description: "no app",
+			cfg:         &mocks.Configuration{},
+			options:     appEventsOptions{appName: "dashboard"},
+			wantErr:     `failed to get app: apps.theketch.io "dashboard" not found`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+			out := &bytes.Buffer{}
+			err := appEvents(ctx, tt.cfg, tt.options, out)
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.wantOut, out.String())
+		})
+	}
+}
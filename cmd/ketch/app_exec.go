@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
+	"k8s.io/kubectl/pkg/util/term"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/utils"
+	"github.com/theketchio/ketch/internal/validation"
+)
+
+const appExecHelp = `
+Execute a command in a running unit of an application.
+By default, the command runs in a unit of the first process of the latest deployment.
+
+  ketch app exec APPNAME -- env
+  ketch app exec APPNAME --process worker -it -- sh
+  ketch app exec APPNAME --unit dashboard-web-2-7d9c8b7f6-x7k2p -- cat /etc/hosts
+`
+
+type appExecFn func(context.Context, config, appExecOptions, execStreams, execFn) error
+
+// execFn executes a command in a container.
+type execFn func(restConfig *rest.Config, cli kubernetes.Interface, req execRequest, streams execStreams) error
+
+func newAppExecCmd(cfg config, out io.Writer, appExec appExecFn) *cobra.Command {
+	options := appExecOptions{}
+	cmd := &cobra.Command{
+		Use:   "exec APPNAME -- COMMAND [ARGS...]",
+		Short: "Execute a command in a running unit of an application.",
+		Args:  cobra.MinimumNArgs(2),
+		Long:  appExecHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.appName = args[0]
+			if !validation.ValidateName(options.appName) {
+				return ErrInvalidAppName
+			}
+			options.command = args[1:]
+			streams := execStreams{in: os.Stdin, out: out, errOut: os.Stderr}
+			return appExec(cmd.Context(), cfg, options, streams, execInContainer)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return autoCompleteAppNames(cfg, toComplete)
+		},
+	}
+	cmd.Flags().StringVarP(&options.processName, "process", "p", "", "Process name, the first process by default.")
+	cmd.Flags().IntVarP(&options.deploymentVersion, "version", "v", 0, "Deployment version, the latest deployment by default.")
+	cmd.Flags().StringVar(&options.unit, "unit", "", "Name of the pod to execute the command in.")
+	cmd.Flags().BoolVarP(&options.stdin, "stdin", "i", false, "Pass stdin to the command.")
+	cmd.Flags().BoolVarP(&options.tty, "tty", "t", false, "Allocate a TTY for the command, it requires --stdin.")
+	return cmd
+}
+
+type appExecOptions struct {
+	appName           string
+	processName       string
+	deploymentVersion int
+	unit              string
+	command           []string
+	stdin             bool
+	tty               bool
+}
+
+type execStreams struct {
+	in     io.Reader
+	out    io.Writer
+	errOut io.Writer
+}
+
+type execRequest struct {
+	namespace     string
+	podName       string
+	containerName string
+	command       []string
+	stdin         bool
+	tty           bool
+}
+
+func appExec(ctx context.Context, cfg config, options appExecOptions, streams execStreams, exec execFn) error {
+	if options.tty && !options.stdin {
+		return fmt.Errorf("--tty requires --stdin")
+	}
+	app := ketchv1.App{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.appName}, &app); err != nil {
+		return fmt.Errorf("failed to get app: %w", err)
+	}
+	namespace, err := appFrameworkNamespace(ctx, cfg, app)
+	if err != nil {
+		return err
+	}
+	deployment, process, err := appProcess(app, options.processName, options.deploymentVersion)
+	if err != nil {
+		return err
+	}
+	cli := cfg.KubernetesClient()
+	appPods, err := cli.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf(`%s=%s`, utils.KetchAppNameLabel, app.Name),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get list of pods: %w", err)
+	}
+	pod, err := execPod(filterProcessDeploymentPods(appPods.Items, deployment.Version.String(), process.Name), options.unit)
+	if err != nil {
+		return err
+	}
+	containerName, err := ketchContainerName(*pod)
+	if err != nil {
+		return err
+	}
+	req := execRequest{
+		namespace:     namespace,
+		podName:       pod.Name,
+		containerName: *containerName,
+		command:       options.command,
+		stdin:         options.stdin,
+		tty:           options.tty,
+	}
+	err = exec(cfg.RestConfig(), cli, req, streams)
+	var exitErr utilexec.ExitError
+	if errors.As(err, &exitErr) && exitErr.Exited() {
+		return exitCodeError{code: exitErr.ExitStatus(), message: fmt.Sprintf("command exited with code %d", exitErr.ExitStatus())}
+	}
+	return err
+}
+
+// execPod returns the unit with the given name or the first running unit if the name is empty.
+func execPod(pods []corev1.Pod, unit string) (*corev1.Pod, error) {
+	for i, pod := range pods {
+		if len(unit) > 0 && pod.Name != unit {
+			continue
+		}
+		if podState(pod) != ketchv1.PodRunning {
+			if len(unit) > 0 {
+				return nil, fmt.Errorf("unit %s is not running", unit)
+			}
+			continue
+		}
+		return &pods[i], nil
+	}
+	if len(unit) > 0 {
+		return nil, fmt.Errorf("unit %s is not found", unit)
+	}
+	return nil, fmt.Errorf("no running units found")
+}
+
+// execInContainer executes the command in the container using the SPDY protocol.
+func execInContainer(restConfig *rest.Config, cli kubernetes.Interface, req execRequest, streams execStreams) error {
+	t := term.TTY{In: streams.in, Out: streams.out, Raw: req.tty}
+	tty := req.tty && t.IsTerminalIn()
+	request := cli.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(req.namespace).
+		Name(req.podName).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: req.containerName,
+			Command:   req.command,
+			Stdin:     req.stdin,
+			Stdout:    true,
+			Stderr:    !tty,
+			TTY:       tty,
+		}, scheme.ParameterCodec)
+	executor, err := remotecommand.NewSPDYExecutor(restConfig, "POST", request.URL())
+	if err != nil {
+		return fmt.Errorf("failed to create executor: %w", err)
+	}
+	options := remotecommand.StreamOptions{
+		Stdout: streams.out,
+		Tty:    tty,
+	}
+	if req.stdin {
+		options.Stdin = streams.in
+	}
+	if !tty {
+		options.Stderr = streams.errOut
+		return executor.Stream(options)
+	}
+	options.TerminalSizeQueue = t.MonitorSize(t.GetSize())
+	return t.Safe(func() error {
+		return executor.Stream(options)
+	})
+}
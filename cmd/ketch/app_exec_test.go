@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	utilexec "k8s.io/client-go/util/exec"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/mocks"
+	"github.com/theketchio/ketch/internal/utils"
+)
+
+func TestAppExec(t *testing.T) {
+	dashboard := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+		Spec: ketchv1.AppSpec{
+			Framework: "gke",
+			Deployments: []ketchv1.AppDeploymentSpec{
+				{Version: 1, Processes: []ketchv1.ProcessSpec{{Name: "web"}}},
+				{Version: 2, Processes: []ketchv1.ProcessSpec{{Name: "web"}, {Name: "worker"}}},
+			},
+		},
+	}
+	gke := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "gke"},
+		Spec:       ketchv1.FrameworkSpec{NamespaceName: "ketch-gke"},
+	}
+	pod := func(name, process, version string, phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "ketch-gke",
+				Labels: map[string]string{
+					utils.KetchAppNameLabel:           "dashboard",
+					utils.KetchProcessNameLabel:       process,
+					utils.KetchDeploymentVersionLabel: version,
+				},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "istio-proxy"}, {Name: "dashboard-" + process + "-" + version}},
+			},
+			Status: corev1.PodStatus{Phase: phase},
+		}
+	}
+	pods := []runtime.Object{
+		pod("dashboard-web-1-abc-1", "web", "1", corev1.PodRunning),
+		pod("dashboard-web-2-abc-1", "web", "2", corev1.PodPending),
+		pod("dashboard-web-2-abc-2", "web", "2", corev1.PodRunning),
+		pod("dashboard-worker-2-abc-1", "worker", "2", corev1.PodRunning),
+	}
+	tests := []struct {
+		name          string
+		options       appExecOptions
+		execErr       error
+		wantRequest   execRequest
+		wantErr       string
+		wantErrorCode int
+	}{
+		{
+			name:    "first running unit of the latest deployment",
+			options: appExecOptions{appName: "dashboard", command: []string{"env"}},
+			wantRequest: execRequest{
+				namespace:     "ketch-gke",
+				podName:       "dashboard-web-2-abc-2",
+				containerName: "dashboard-web-2",
+				command:       []string{"env"},
+			},
+		},
+		{
+			name:    "interactive shell in a process of a deployment version",
+			options: appExecOptions{appName: "dashboard", processName: "web", deploymentVersion: 1, command: []string{"sh"}, stdin: true, tty: true},
+			wantRequest: execRequest{
+				namespace:     "ketch-gke",
+				podName:       "dashboard-web-1-abc-1",
+				containerName: "dashboard-web-1",
+				command:       []string{"sh"},
+				stdin:         true,
+				tty:           true,
+			},
+		},
+		{
+			name:    "unit",
+			options: appExecOptions{appName: "dashboard", processName: "worker", unit: "dashboard-worker-2-abc-1", command: []string{"env"}},
+			wantRequest: execRequest{
+				namespace:     "ketch-gke",
+				podName:       "dashboard-worker-2-abc-1",
+				containerName: "dashboard-worker-2",
+				command:       []string{"env"},
+			},
+		},
+		{
+			name:    "unit is not running",
+			options: appExecOptions{appName: "dashboard", unit: "dashboard-web-2-abc-1", command: []string{"env"}},
+			wantErr: "unit dashboard-web-2-abc-1 is not running",
+		},
+		{
+			name:    "unit of another process",
+			options: appExecOptions{appName: "dashboard", unit: "dashboard-worker-2-abc-1", command: []string{"env"}},
+			wantErr: "unit dashboard-worker-2-abc-1 is not found",
+		},
+		{
+			name:    "tty without stdin",
+			options: appExecOptions{appName: "dashboard", command: []string{"sh"}, tty: true},
+			wantErr: "--tty requires --stdin",
+		},
+		{
+			name:    "command fails",
+			options: appExecOptions{appName: "dashboard", command: []string{"false"}},
+			execErr: utilexec.CodeExitError{Err: errors.New("command terminated with exit code 1"), Code: 1},
+			wantRequest: execRequest{
+				namespace:     "ketch-gke",
+				podName:       "dashboard-web-2-abc-2",
+				containerName: "dashboard-web-2",
+				command:       []string{"false"},
+			},
+			wantErr:       "command exited with code 1",
+			wantErrorCode: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{dashboard, gke},
+				KubeClientObjects: pods,
+			}
+			var got *execRequest
+			exec := func(restConfig *rest.Config, cli kubernetes.Interface, req execRequest, streams execStreams) error {
+				got = &req
+				return tt.execErr
+			}
+			err := appExec(context.Background(), cfg, tt.options, execStreams{out: &bytes.Buffer{}}, exec)
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				if tt.wantErrorCode > 0 {
+					var exitErr exitCodeError
+					require.True(t, errors.As(err, &exitErr))
+					require.Equal(t, tt.wantErrorCode, exitErr.code)
+					require.Equal(t, tt.wantRequest, *got)
+				}
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.wantRequest, *got)
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/validation"
+)
+
+const appIdleHelp = `
+Manage the idle policy of an application.
+An application with an idle policy is put to sleep when it receives no ingress traffic,
+all its processes are scaled to 0 units until "ketch app wake" is run.
+`
+
+const appIdleSetHelp = `
+Put an application to sleep after it receives no ingress traffic for the given number of minutes.
+The traffic is checked with a Prometheus query, by default the number of istio requests.
+A query is required when the framework's ingress controller isn't istio.
+
+  ketch app idle set APPNAME --minutes 30 --prometheus-url http://prometheus.istio-system:9090
+`
+
+const appIdleUnsetHelp = `
+Remove the idle policy of an application.
+A sleeping application is woken up.
+`
+
+type appIdleSetFn func(context.Context, config, appIdleSetOptions, io.Writer) error
+
+type appIdleUnsetFn func(context.Context, config, appIdleUnsetOptions, io.Writer) error
+
+func newAppIdleCmd(cfg config, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "idle",
+		Short: "Manage the idle policy of an application.",
+		Long:  appIdleHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Usage()
+		},
+	}
+	cmd.AddCommand(newAppIdleSetCmd(cfg, out, appIdleSet))
+	cmd.AddCommand(newAppIdleUnsetCmd(cfg, out, appIdleUnset))
+	return cmd
+}
+
+func newAppIdleSetCmd(cfg config, out io.Writer, appIdleSet appIdleSetFn) *cobra.Command {
+	options := appIdleSetOptions{}
+	cmd := &cobra.Command{
+		Use:   "set APPNAME",
+		Short: "Put an application to sleep when it receives no ingress traffic.",
+		Args:  cobra.ExactValidArgs(1),
+		Long:  appIdleSetHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.appName = args[0]
+			if !validation.ValidateName(options.appName) {
+				return ErrInvalidAppName
+			}
+			return appIdleSet(cmd.Context(), cfg, options, out)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return autoCompleteAppNames(cfg, toComplete)
+		},
+	}
+	cmd.Flags().IntVar(&options.minutes, "minutes", 0, "Number of minutes without ingress traffic before the application is put to sleep.")
+	cmd.Flags().StringVar(&options.prometheusURL, "prometheus-url", "", "Address of a Prometheus compatible HTTP API.")
+	cmd.Flags().StringVar(&options.query, "query", "", "PromQL query template returning the number of requests, it can use {{ .App }} and {{ .Window }}.")
+	cmd.MarkFlagRequired("minutes")
+	cmd.MarkFlagRequired("prometheus-url")
+	return cmd
+}
+
+func newAppIdleUnsetCmd(cfg config, out io.Writer, appIdleUnset appIdleUnsetFn) *cobra.Command {
+	options := appIdleUnsetOptions{}
+	cmd := &cobra.Command{
+		Use:   "unset APPNAME",
+		Short: "Remove the idle policy of an application.",
+		Args:  cobra.ExactValidArgs(1),
+		Long:  appIdleUnsetHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.appName = args[0]
+			if !validation.ValidateName(options.appName) {
+				return ErrInvalidAppName
+			}
+			return appIdleUnset(cmd.Context(), cfg, options, out)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return autoCompleteAppNames(cfg, toComplete)
+		},
+	}
+	return cmd
+}
+
+type appIdleSetOptions struct {
+	appName       string
+	minutes       int
+	prometheusURL string
+	query         string
+}
+
+type appIdleUnsetOptions struct {
+	appName string
+}
+
+func appIdleSet(ctx context.Context, cfg config, options appIdleSetOptions, out io.Writer) error {
+	if options.minutes < 1 {
+		return fmt.Errorf("--minutes must be greater than 0")
+	}
+	app := ketchv1.App{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.appName}, &app); err != nil {
+		return fmt.Errorf("failed to get app: %w", err)
+	}
+	if options.query == "" {
+		framework := ketchv1.Framework{}
+		if err := cfg.Client().Get(ctx, types.NamespacedName{Name: app.Spec.Framework}, &framework); err != nil {
+			return fmt.Errorf("failed to get framework: %w", err)
+		}
+		if ingressType := framework.Spec.IngressController.IngressType; ingressType != ketchv1.IstioIngressControllerType {
+			return fmt.Errorf("--query is required for the %s ingress controller, the default query relies on istio metrics", ingressType)
+		}
+	}
+	if app.Spec.IdlePolicy == nil {
+		app.Spec.IdlePolicy = &ketchv1.IdlePolicySpec{}
+	}
+	// the state of a sleeping application is kept.
+	app.Spec.IdlePolicy.IdleMinutes = options.minutes
+	app.Spec.IdlePolicy.PrometheusURL = options.prometheusURL
+	app.Spec.IdlePolicy.Query = options.query
+	if err := cfg.Client().Update(ctx, &app); err != nil {
+		return fmt.Errorf("failed to update app: %w", err)
+	}
+	fmt.Fprintln(out, "Successfully updated!")
+	return nil
+}
+
+func appIdleUnset(ctx context.Context, cfg config, options appIdleUnsetOptions, out io.Writer) error {
+	app := ketchv1.App{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.appName}, &app); err != nil {
+		return fmt.Errorf("failed to get app: %w", err)
+	}
+	if app.IsAsleep() {
+		if err := app.Wake(metav1.NewTime(time.Now())); err != nil {
+			return fmt.Errorf("failed to wake app: %w", err)
+		}
+	}
+	app.Spec.IdlePolicy = nil
+	if err := cfg.Client().Update(ctx, &app); err != nil {
+		return fmt.Errorf("failed to update app: %w", err)
+	}
+	fmt.Fprintln(out, "Successfully updated!")
+	return nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/mocks"
+	"github.com/theketchio/ketch/internal/utils/conversions"
+)
+
+func TestAppIdleSet(t *testing.T) {
+	tests := []struct {
+		name       string
+		options    appIdleSetOptions
+		ingress    ketchv1.IngressControllerType
+		wantPolicy *ketchv1.IdlePolicySpec
+		wantErr    string
+	}{
+		{
+			name:    "new policy",
+			options: appIdleSetOptions{appName: "dashboard", minutes: 15, prometheusURL: "http://prometheus:9090"},
+			wantPolicy: &ketchv1.IdlePolicySpec{
+				IdleMinutes:   15,
+				PrometheusURL: "http://prometheus:9090",
+				Asleep:        true,
+				Units:         []ketchv1.ProcessUnits{{Version: 1, Process: "web", Units: conversions.IntPtr(3)}},
+			},
+		},
+		{
+			name:    "custom query",
+			options: appIdleSetOptions{appName: "dashboard", minutes: 15, prometheusURL: "http://prometheus:9090", query: `requests{app="{{ .App }}"}`},
+			ingress: ketchv1.NginxIngressControllerType,
+			wantPolicy: &ketchv1.IdlePolicySpec{
+				IdleMinutes:   15,
+				PrometheusURL: "http://prometheus:9090",
+				Query:         `requests{app="{{ .App }}"}`,
+				Asleep:        true,
+				Units:         []ketchv1.ProcessUnits{{Version: 1, Process: "web", Units: conversions.IntPtr(3)}},
+			},
+		},
+		{
+			name:    "default query without istio",
+			options: appIdleSetOptions{appName: "dashboard", minutes: 15, prometheusURL: "http://prometheus:9090"},
+			ingress: ketchv1.NginxIngressControllerType,
+			wantErr: "--query is required for the nginx ingress controller, the default query relies on istio metrics",
+		},
+		{
+			name:    "invalid minutes",
+			options: appIdleSetOptions{appName: "dashboard", minutes: 0, prometheusURL: "http://prometheus:9090"},
+			wantErr: "--minutes must be greater than 0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ingress := tt.ingress
+			if ingress == "" {
+				ingress = ketchv1.IstioIngressControllerType
+			}
+			framework := &ketchv1.Framework{
+				ObjectMeta: metav1.ObjectMeta{Name: "myframework"},
+				Spec:       ketchv1.FrameworkSpec{IngressController: ketchv1.IngressControllerSpec{IngressType: ingress}},
+			}
+			cfg := &mocks.Configuration{CtrlClientObjects: []runtime.Object{sleepingApp(), framework}}
+			out := &bytes.Buffer{}
+			err := appIdleSet(context.Background(), cfg, tt.options, out)
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, "Successfully updated!\n", out.String())
+
+			app := ketchv1.App{}
+			require.Nil(t, cfg.Client().Get(context.Background(), types.NamespacedName{Name: "dashboard"}, &app))
+			require.Equal(t, tt.wantPolicy, app.Spec.IdlePolicy)
+		})
+	}
+}
+
+func TestAppIdleUnset(t *testing.T) {
+	cfg := &mocks.Configuration{CtrlClientObjects: []runtime.Object{sleepingApp()}}
+	out := &bytes.Buffer{}
+	err := appIdleUnset(context.Background(), cfg, appIdleUnsetOptions{appName: "dashboard"}, out)
+	require.Nil(t, err)
+	require.Equal(t, "Successfully updated!\n", out.String())
+
+	app := ketchv1.App{}
+	require.Nil(t, cfg.Client().Get(context.Background(), types.NamespacedName{Name: "dashboard"}, &app))
+	require.Nil(t, app.Spec.IdlePolicy)
+	require.Equal(t, conversions.IntPtr(3), app.Spec.Deployments[0].Processes[0].Units)
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"html/template"
 	"io"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -20,47 +21,75 @@ import (
 )
 
 var (
-	appInfoTemplate = `Application: {{ .App.Name }}
-Framework: {{ .App.Spec.Framework }}
-{{- if .App.Spec.Version }}
-Version: {{ .App.Spec.Version }}
+	appInfoTemplate = `Application: {{ .Name }}
+Framework: {{ .Framework }}
+{{- if .Version }}
+Version: {{ .Version }}
 {{- end }}
-{{- if .App.Spec.Builder }}
-Builder: {{ .App.Spec.Builder }}
+{{- if .Builder }}
+Builder: {{ .Builder }}
 {{- end }}
-{{- if .App.Spec.Description }}
-Description: {{ .App.Spec.Description }}
+{{- if .Description }}
+Description: {{ .Description }}
 {{- end }}
-{{- if .Cnames }}
-{{- range $address := .Cnames }}
+{{- if .Addresses }}
+{{- range $address := .Addresses }}
 Address: {{ $address }}
 {{- end }}
 {{- else }}
-The default cname hasn't assigned yet because "{{ .App.Spec.Framework }}" framework doesn't have ingress service endpoint.
+The default cname hasn't assigned yet because "{{ .Framework }}" framework doesn't have ingress service endpoint.
 {{- end }}
-{{- if .App.Spec.DockerRegistry.SecretName }}
-Secret name to pull application's images: {{ .App.Spec.DockerRegistry.SecretName }}
+{{- if .PreviewAddress }}
+Preview address: {{ .PreviewAddress }}
 {{- end }}
-{{ if .App.Spec.Env }}
+{{- if .RegistrySecret }}
+Secret name to pull application's images: {{ .RegistrySecret }}
+{{- end }}
+{{ if .Env }}
 Environment variables:
-{{- range .App.Spec.Env }}
+{{- range .Env }}
+{{- if .Secret }}
+{{ .Name }} (from secret {{ .Secret }})
+{{- else }}
 {{ .Name }}={{ .Value }}
 {{- end }}
+{{- end }}
 {{- else }}
 No environment variables.
 {{- end }}
+{{- if .History }}
+
+Deployment history:
+{{- range .History }}
+{{ .Version }}: {{ .Image }}
+{{- end }}
+{{- end }}
 `
 )
 
-type appInfoContext struct {
-	App         ketchv1.App `json:"app" yaml:"app"`
-	Cnames      []string    `json:"cnames" yaml:"cnames"`
-	NoProcesses bool        `json:"noProcesses" yaml:"noProcesses"`
-}
-
+// appInfoOutput is the schema of the json and yaml output formats of "app info".
 type appInfoOutput struct {
-	AppInfoContext appInfoContext     `json:"appInfoContext" yaml:"appInfoContext"`
+	Name        string `json:"name" yaml:"name"`
+	Framework   string `json:"framework" yaml:"framework"`
+	Version     string `json:"version,omitempty" yaml:"version,omitempty"`
+	Builder     string `json:"builder,omitempty" yaml:"builder,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// Addresses are the URLs of the app's cnames.
+	Addresses []string `json:"addresses" yaml:"addresses"`
+	// PreviewAddress is the URL of the preview deployment of an active blue-green deployment.
+	PreviewAddress string `json:"previewAddress,omitempty" yaml:"previewAddress,omitempty"`
+	// RegistrySecret is the name of the secret used to pull the app's images.
+	RegistrySecret string             `json:"registrySecret,omitempty" yaml:"registrySecret,omitempty"`
+	Env            []appEnvOutput     `json:"env,omitempty" yaml:"env,omitempty"`
 	Deployments    []deploymentOutput `json:"deployments" yaml:"deployments"`
+	History        []historyOutput    `json:"history,omitempty" yaml:"history,omitempty"`
+}
+
+type appEnvOutput struct {
+	Name  string `json:"name" yaml:"name"`
+	Value string `json:"value,omitempty" yaml:"value,omitempty"`
+	// Secret is the "secret/key" reference of a value stored in a secret.
+	Secret string `json:"secret,omitempty" yaml:"secret,omitempty"`
 }
 
 type deploymentOutput struct {
@@ -70,6 +99,12 @@ type deploymentOutput struct {
 	Weight            string `json:"weight" yaml:"weight"`
 	State             string `json:"state" yaml:"state"`
 	Cmd               string `json:"cmd" yaml:"cmd"`
+	Units             string `json:"units" yaml:"units" output:"wide"`
+}
+
+type historyOutput struct {
+	Version int    `json:"version" yaml:"version"`
+	Image   string `json:"image" yaml:"image"`
 }
 
 const appInfoHelp = `
@@ -91,11 +126,13 @@ func newAppInfoCmd(cfg config, out io.Writer) *cobra.Command {
 			return autoCompleteAppNames(cfg, toComplete)
 		},
 	}
+	cmd.Flags().StringVarP(&options.output, "output", "o", "", output.Usage)
 	return cmd
 }
 
 type appInfoOptions struct {
-	name string
+	name   string
+	output string
 }
 
 func appInfo(ctx context.Context, cfg config, options appInfoOptions, out io.Writer) error {
@@ -116,43 +153,67 @@ func appInfo(ctx context.Context, cfg config, options appInfoOptions, out io.Wri
 	}
 
 	data := generateAppInfoOutput(app, appPods, framework)
+	switch options.output {
+	case output.JSON, output.YAML:
+		return output.Write(data, out, options.output)
+	case output.Name:
+		_, err := fmt.Fprintln(out, app.Name)
+		return err
+	}
+	if output.IsTemplateFormat(options.output) {
+		return output.WriteWithObjects(data, app, out, options.output)
+	}
 
 	buf := bytes.Buffer{}
 	t := template.Must(template.New("app-info").Parse(appInfoTemplate))
-	if err := t.Execute(&buf, data.AppInfoContext); err != nil {
+	if err := t.Execute(&buf, data); err != nil {
 		return err
 	}
 	fmt.Fprintf(out, "%v", buf.String())
-	return output.Write(data.Deployments, out, "column")
+	return output.Write(data.Deployments, out, options.output)
 
 }
 
 func generateAppInfoOutput(app ketchv1.App, appPods *v1.PodList, framework *ketchv1.Framework) appInfoOutput {
-	noProcesses := true
-	var deployments []deploymentOutput
+	info := appInfoOutput{
+		Name:           app.Name,
+		Framework:      app.Spec.Framework,
+		Builder:        app.Spec.Builder,
+		Description:    app.Spec.Description,
+		Addresses:      app.CNames(framework),
+		RegistrySecret: app.Spec.DockerRegistry.SecretName,
+	}
+	if app.Spec.Version != nil {
+		info.Version = *app.Spec.Version
+	}
+	if previewCname := app.PreviewCname(framework); previewCname != nil && app.PreviewDeployment() != nil {
+		info.PreviewAddress = fmt.Sprintf("http://%s", *previewCname)
+	}
+	for _, env := range app.Spec.Env {
+		envOutput := appEnvOutput{Name: env.Name, Value: env.Value}
+		if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil {
+			envOutput.Secret = fmt.Sprintf("%s/%s", env.ValueFrom.SecretKeyRef.Name, env.ValueFrom.SecretKeyRef.Key)
+		}
+		info.Env = append(info.Env, envOutput)
+	}
 	for _, deployment := range app.Spec.Deployments {
 		for _, process := range deployment.Processes {
-			noProcesses = false
 			state := appState(filterProcessDeploymentPods(appPods.Items, deployment.Version.String(), process.Name))
-			deployments = append(deployments, deploymentOutput{
+			info.Deployments = append(info.Deployments, deploymentOutput{
 				DeploymentVersion: deployment.Version.String(),
 				Image:             deployment.Image,
 				ProcessName:       process.Name,
 				Weight:            fmt.Sprintf("%v%%", deployment.RoutingSettings.Weight),
 				State:             state,
 				Cmd:               strings.Join(process.Cmd, " "),
+				Units:             strconv.Itoa(process.DesiredUnits()),
 			})
 		}
 	}
-	infoContext := appInfoContext{
-		App:         app,
-		Cnames:      app.CNames(framework),
-		NoProcesses: noProcesses,
-	}
-
-	return appInfoOutput{
-		infoContext, deployments,
+	for _, deployment := range app.Status.History {
+		info.History = append(info.History, historyOutput{Version: int(deployment.Version), Image: deployment.Image})
 	}
+	return info
 }
 
 func filterProcessDeploymentPods(appPods []corev1.Pod, version, process string) []corev1.Pod {
@@ -96,6 +96,10 @@ func Test_appInfo(t *testing.T) {
 			},
 		},
 	}
+	goAppWithHistory := goApp.DeepCopy()
+	goAppWithHistory.Status.History = []ketchv1.AppDeploymentSpec{
+		{Version: 1, Image: "shipasoftware/go-app:v1"},
+	}
 	gke := &ketchv1.Framework{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: "gke",
@@ -156,6 +160,16 @@ func Test_appInfo(t *testing.T) {
 			},
 			wantOutputFilename: "./testdata/app-info/go-app-secret-name.output",
 		},
+		{
+			name: "deployment history",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{aws, goAppWithHistory},
+			},
+			options: appInfoOptions{
+				name: "go-app",
+			},
+			wantOutputFilename: "./testdata/app-info/go-app-history.output",
+		},
 		{
 			name: "app with builder",
 			cfg: &mocks.Configuration{
@@ -166,6 +180,28 @@ func Test_appInfo(t *testing.T) {
 			},
 			wantOutputFilename: "./testdata/app-info/app-python.output",
 		},
+		{
+			name: "wide output",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{aws, goApp},
+			},
+			options: appInfoOptions{
+				name:   "go-app",
+				output: "wide",
+			},
+			wantOutputFilename: "./testdata/app-info/go-app-wide.output",
+		},
+		{
+			name: "json output",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{aws, goApp},
+			},
+			options: appInfoOptions{
+				name:   "go-app",
+				output: "json",
+			},
+			wantOutputFilename: "./testdata/app-info/go-app-json.output",
+		},
 		{
 			name: "no framework",
 			cfg: &mocks.Configuration{
@@ -22,26 +22,34 @@ type appListOutput struct {
 	Addresses   string `json:"addresses" yaml:"addresses"`
 	Builder     string `json:"builder" yaml:"builder"`
 	Description string `json:"description" yaml:"description"`
+	Images      string `json:"images" yaml:"images" output:"wide"`
+	Units       int    `json:"units" yaml:"units" output:"wide"`
 }
 
 const appListHelp = `
 List all apps running on a kubernetes cluster.
 `
 
+type appListOptions struct {
+	output string
+}
+
 func newAppListCmd(cfg config, out io.Writer) *cobra.Command {
+	options := appListOptions{}
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all apps.",
 		Long:  appListHelp,
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return appList(cmd.Context(), cfg, out)
+			return appList(cmd.Context(), cfg, options, out)
 		},
 	}
+	cmd.Flags().StringVarP(&options.output, "output", "o", "", output.Usage)
 	return cmd
 }
 
-func appList(ctx context.Context, cfg config, out io.Writer) error {
+func appList(ctx context.Context, cfg config, options appListOptions, out io.Writer) error {
 	apps := ketchv1.AppList{}
 	if err := cfg.Client().List(ctx, &apps); err != nil {
 		return fmt.Errorf("failed to list apps: %w", err)
@@ -58,7 +66,7 @@ func appList(ctx context.Context, cfg config, out io.Writer) error {
 	if err != nil {
 		return fmt.Errorf("failed to list apps pods: %w", err)
 	}
-	return output.Write(generateAppListOutput(apps, allPods, frameworksByName), out, "column")
+	return output.WriteWithObjects(generateAppListOutput(apps, allPods, frameworksByName), apps, out, options.output)
 }
 
 func generateAppListOutput(apps ketchv1.AppList, allPods *corev1.PodList, frameworksByName map[string]ketchv1.Framework) []appListOutput {
@@ -67,6 +75,14 @@ func generateAppListOutput(apps ketchv1.AppList, allPods *corev1.PodList, framew
 		pods := filterAppPods(item.Name, allPods.Items)
 		framework := frameworksByName[item.Spec.Framework]
 		urls := strings.Join(item.CNames(&framework), " ")
+		var images []string
+		units := 0
+		for _, deployment := range item.Spec.Deployments {
+			images = append(images, deployment.Image)
+			for _, process := range deployment.Processes {
+				units += process.DesiredUnits()
+			}
+		}
 		outputs = append(outputs, appListOutput{
 			Name:        item.Name,
 			Framework:   item.Spec.Framework,
@@ -74,6 +90,8 @@ func generateAppListOutput(apps ketchv1.AppList, allPods *corev1.PodList, framew
 			Addresses:   urls,
 			Builder:     item.Spec.Builder,
 			Description: item.Spec.Description,
+			Images:      strings.Join(images, " "),
+			Units:       units,
 		})
 	}
 	return outputs
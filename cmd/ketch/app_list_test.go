@@ -45,8 +45,9 @@ func Test_appList(t *testing.T) {
 	}
 
 	tests := []struct {
-		name string
-		cfg  config
+		name    string
+		cfg     config
+		options appListOptions
 
 		wantOut string
 		wantErr bool
@@ -61,11 +62,68 @@ app-a    fw1          created    http://app-a-cname1               my app-a
 app-b    fw1          created    http://app-b-cname1               my app-b
 `,
 		},
+		{
+			name: "wide output",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{appA},
+			},
+			options: appListOptions{output: "wide"},
+			wantOut: `NAME     FRAMEWORK    STATE      ADDRESSES              BUILDER    DESCRIPTION    IMAGES    UNITS
+app-a    fw1          created    http://app-a-cname1               my app-a                 0
+`,
+		},
+		{
+			name: "json output",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{appA},
+			},
+			options: appListOptions{output: "json"},
+			wantOut: `[
+  {
+    "name": "app-a",
+    "framework": "fw1",
+    "state": "created",
+    "addresses": "http://app-a-cname1",
+    "builder": "",
+    "description": "my app-a",
+    "images": "",
+    "units": 0
+  }
+]
+`,
+		},
+		{
+			name: "name output",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{appA, appB},
+			},
+			options: appListOptions{output: "name"},
+			wantOut: "app-a\napp-b\n",
+		},
+		{
+			name: "custom columns output",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{appA, appB},
+			},
+			options: appListOptions{output: "custom-columns=NAME:.metadata.name,CNAME:.spec.ingress.cnames[0].name"},
+			wantOut: `NAME    CNAME
+app-a   app-a-cname1
+app-b   app-b-cname1
+`,
+		},
+		{
+			name: "unsupported output",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{appA, appB},
+			},
+			options: appListOptions{output: "xml"},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			out := &bytes.Buffer{}
-			err := appList(context.Background(), tt.cfg, out)
+			err := appList(context.Background(), tt.cfg, tt.options, out)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("frameworkList() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -3,8 +3,11 @@ package main
 import (
 	"bufio"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"math"
+	"regexp"
 	"strings"
 	"time"
 
@@ -17,6 +20,7 @@ import (
 	"k8s.io/client-go/kubernetes"
 	restclient "k8s.io/client-go/rest"
 
+	"github.com/theketchio/ketch/cmd/ketch/output"
 	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
 	"github.com/theketchio/ketch/internal/utils"
 	"github.com/theketchio/ketch/internal/validation"
@@ -24,7 +28,12 @@ import (
 
 const (
 	appLogHelp = `
-Show logs of an application
+Show logs of an application.
+Logs of all units are merged and ordered by timestamp.
+
+  ketch app log APPNAME --since 10m --grep "status=5[0-9]{2}"
+  ketch app log APPNAME --process worker --tail 100 --previous
+  ketch app log APPNAME -f -o json
 `
 	streamLogReconnectDelay = 500 * time.Millisecond
 )
@@ -55,6 +64,11 @@ func newAppLogCmd(cfg config, out io.Writer, appLog appLogFn) *cobra.Command {
 	cmd.Flags().BoolVar(&options.ignoreErrors, "ignore-errors", false, "If watching / following pod logs, allow for any errors that occur to be non-fatal")
 	cmd.Flags().BoolVar(&options.prefix, "prefix", false, "Prefix each log line with the log source (pod name and container name)")
 	cmd.Flags().BoolVar(&options.timestamps, "timestamps", false, "Include timestamps on each line in the log output")
+	cmd.Flags().DurationVar(&options.since, "since", 0, "Only show logs newer than a relative duration like 10m or 2h")
+	cmd.Flags().Int64Var(&options.tail, "tail", 0, "Number of the most recent lines to show from each unit, all lines by default")
+	cmd.Flags().StringVar(&options.grep, "grep", "", "Only show lines matching the regular expression")
+	cmd.Flags().BoolVar(&options.previous, "previous", false, "Show logs of the previous instance of crashed containers")
+	cmd.Flags().StringVarP(&options.output, "output", "o", "", "Output format. One of: json")
 
 	return cmd
 }
@@ -67,11 +81,35 @@ type appLogOptions struct {
 	ignoreErrors      bool
 	timestamps        bool
 	prefix            bool
+	since             time.Duration
+	tail              int64
+	grep              string
+	previous          bool
+	output            string
 }
 
 type watchLogsFn func(client kubernetes.Interface, options watchOptions, readLogs readLogsFn, streamLogs streamLogsFn) error
 
 func appLog(ctx context.Context, cfg config, options appLogOptions, out io.Writer, watchLogs watchLogsFn) error {
+	if options.since < 0 {
+		return fmt.Errorf("--since must be positive")
+	}
+	if options.tail < 0 {
+		return fmt.Errorf("--tail must be positive")
+	}
+	if options.previous && options.follow {
+		return fmt.Errorf("--previous can't be used with --follow")
+	}
+	if len(options.output) > 0 && options.output != output.JSON {
+		return fmt.Errorf("unsupported output format %q, only %q is supported", options.output, output.JSON)
+	}
+	var grep *regexp.Regexp
+	if len(options.grep) > 0 {
+		var err error
+		if grep, err = regexp.Compile(options.grep); err != nil {
+			return fmt.Errorf("invalid --grep expression: %w", err)
+		}
+	}
 	app := ketchv1.App{}
 	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.appName}, &app); err != nil {
 		return fmt.Errorf("failed to get app instance: %w", err)
@@ -98,6 +136,16 @@ func appLog(ctx context.Context, cfg config, options appLogOptions, out io.Write
 		timestamps:   options.timestamps,
 		prefix:       options.prefix,
 		out:          out,
+		previous:     options.previous,
+		grep:         grep,
+		json:         options.output == output.JSON,
+	}
+	if options.since > 0 {
+		sinceSeconds := int64(math.Ceil(options.since.Seconds()))
+		opts.sinceSeconds = &sinceSeconds
+	}
+	if options.tail > 0 {
+		opts.tailLines = &options.tail
 	}
 	return watchLogs(cfg.KubernetesClient(), opts, readLogs, streamLogs)
 }
@@ -110,6 +158,54 @@ type watchOptions struct {
 	timestamps   bool
 	prefix       bool
 	out          io.Writer
+	// containerName is the container to read logs from.
+	// If it's empty, logs are read from the application container of each pod.
+	containerName string
+	// sinceSeconds and tailLines limit the logs read from each pod, nil means no limit.
+	sinceSeconds *int64
+	tailLines    *int64
+	// previous is set to read logs of the previous instance of the container.
+	previous bool
+	// grep filters log messages, nil means all messages are shown.
+	grep *regexp.Regexp
+	// json is set to print messages as json records, one per line.
+	json bool
+}
+
+// podLogOptions returns options to read logs of the given container.
+func (o watchOptions) podLogOptions(containerName string) corev1.PodLogOptions {
+	return corev1.PodLogOptions{
+		Container:    containerName,
+		Timestamps:   true,
+		SinceSeconds: o.sinceSeconds,
+		TailLines:    o.tailLines,
+		Previous:     o.previous,
+	}
+}
+
+// write prints the message if it matches the grep expression.
+func (o watchOptions) write(m logMessage) {
+	if o.grep != nil && !o.grep.MatchString(m.msg) {
+		return
+	}
+	if o.json {
+		json.NewEncoder(o.out).Encode(m.record())
+		return
+	}
+	fmt.Fprintf(o.out, "%s", m.Format(o.prefix, o.timestamps))
+}
+
+// podContainerName returns a name of the container of the given pod to read logs from.
+func (o watchOptions) podContainerName(pod corev1.Pod) (*string, error) {
+	if len(o.containerName) == 0 {
+		return ketchContainerName(pod)
+	}
+	for _, c := range pod.Spec.Containers {
+		if c.Name == o.containerName {
+			return &c.Name, nil
+		}
+	}
+	return nil, fmt.Errorf("pod %s doesn't have container %s", pod.Name, o.containerName)
 }
 
 // ketchContainerName returns a name of an application container.
@@ -135,8 +231,17 @@ func isContainerRunning(pod corev1.Pod, containerName string) bool {
 	return false
 }
 
-type readLogsFn func(getLogs getLogsFn, pod corev1.Pod, containerName string, out io.Writer) chan logMessage
-type streamLogsFn func(getLogs getLogsFn, pod corev1.Pod, containerName string, out io.Writer, lastTime time.Time, msgCh chan logMessage) chan struct{}
+func isContainerRestarted(pod corev1.Pod, containerName string) bool {
+	for _, container := range pod.Status.ContainerStatuses {
+		if container.Name == containerName {
+			return container.RestartCount > 0
+		}
+	}
+	return false
+}
+
+type readLogsFn func(getLogs getLogsFn, pod corev1.Pod, logOptions corev1.PodLogOptions, out io.Writer) chan logMessage
+type streamLogsFn func(getLogs getLogsFn, pod corev1.Pod, logOptions corev1.PodLogOptions, out io.Writer, lastTime time.Time, msgCh chan logMessage) chan struct{}
 
 func watchLogs(cli kubernetes.Interface, options watchOptions, readLogs readLogsFn, streamLogs streamLogsFn) error {
 	pods, err := cli.CoreV1().Pods(options.namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: options.selector.String()})
@@ -146,11 +251,15 @@ func watchLogs(cli kubernetes.Interface, options watchOptions, readLogs readLogs
 	// we are going to read logs from all running pods, just read without streaming.
 	msgChs := make(map[types.UID]chan logMessage, len(pods.Items))
 	for _, pod := range pods.Items {
-		containerName, err := ketchContainerName(pod)
+		containerName, err := options.podContainerName(pod)
 		if err != nil {
 			return err
 		}
-		msgChs[pod.UID] = readLogs(cli.CoreV1().Pods(pod.Namespace).GetLogs, pod, *containerName, options.out)
+		if options.previous && !isContainerRestarted(pod, *containerName) {
+			// there is no previous instance of the container.
+			continue
+		}
+		msgChs[pod.UID] = readLogs(cli.CoreV1().Pods(pod.Namespace).GetLogs, pod, options.podLogOptions(*containerName), options.out)
 	}
 
 	// we want to show the logs sorted by timestamp.
@@ -182,7 +291,7 @@ func watchLogs(cli kubernetes.Interface, options watchOptions, readLogs readLogs
 		m := messages[target]
 		timeOfLastMessage[target] = m.time
 
-		fmt.Fprintf(options.out, "%s", m.Format(options.prefix, options.timestamps))
+		options.write(m)
 
 		m, ok := <-msgChs[target]
 		if !ok {
@@ -222,7 +331,7 @@ func watchLogs(cli kubernetes.Interface, options watchOptions, readLogs readLogs
 				if _, ok := doneChannels[pod.UID]; ok {
 					continue
 				}
-				containerName, err := ketchContainerName(*pod)
+				containerName, err := options.podContainerName(*pod)
 				if err != nil {
 					if !options.ignoreErrors {
 						return err
@@ -233,7 +342,7 @@ func watchLogs(cli kubernetes.Interface, options watchOptions, readLogs readLogs
 					continue
 				}
 				logs := cli.CoreV1().Pods(pod.Namespace).GetLogs
-				doneChannels[pod.UID] = streamLogs(logs, *pod, *containerName, options.out, timeOfLastMessage[pod.UID], msgCh)
+				doneChannels[pod.UID] = streamLogs(logs, *pod, options.podLogOptions(*containerName), options.out, timeOfLastMessage[pod.UID], msgCh)
 
 			case watch.Deleted:
 				if doneCh, ok := doneChannels[pod.UID]; ok {
@@ -242,7 +351,7 @@ func watchLogs(cli kubernetes.Interface, options watchOptions, readLogs readLogs
 				}
 			}
 		case m := <-msgCh:
-			fmt.Fprintf(options.out, "%s", m.Format(options.prefix, options.timestamps))
+			options.write(m)
 		}
 	}
 }
@@ -267,6 +376,27 @@ func (m logMessage) Format(prefix bool, timestamps bool) string {
 	return strings.Join(parts, " ")
 }
 
+// logRecord is a json representation of a logMessage.
+type logRecord struct {
+	Time      time.Time `json:"time"`
+	Pod       string    `json:"pod"`
+	Container string    `json:"container"`
+	Process   string    `json:"process,omitempty"`
+	Version   string    `json:"version,omitempty"`
+	Message   string    `json:"message"`
+}
+
+func (m logMessage) record() logRecord {
+	return logRecord{
+		Time:      m.time,
+		Pod:       m.pod.Name,
+		Container: m.containerName,
+		Process:   m.pod.Labels[utils.KetchProcessNameLabel],
+		Version:   m.pod.Labels[utils.KetchDeploymentVersionLabel],
+		Message:   strings.TrimSuffix(m.msg, "\n"),
+	}
+}
+
 func readString(reader *bufio.Reader, pod *corev1.Pod, containerName string) (*logMessage, error) {
 	line, err := reader.ReadString('\n')
 	if len(line) > 0 {
@@ -286,13 +416,14 @@ type getLogsFn func(name string, opts *corev1.PodLogOptions) *restclient.Request
 
 // readLogs runs a goroutine that reads logs of the given pod. readLogs returns a message channel to receive logs.
 // Once there are no more logs, readLogs closes the message channel.
-func readLogs(getLogs getLogsFn, pod corev1.Pod, containerName string, out io.Writer) chan logMessage {
+func readLogs(getLogs getLogsFn, pod corev1.Pod, logOptions corev1.PodLogOptions, out io.Writer) chan logMessage {
 	msgCh := make(chan logMessage)
+	containerName := logOptions.Container
 	go func() {
 		defer func() {
 			close(msgCh)
 		}()
-		req := getLogs(pod.Name, &corev1.PodLogOptions{Timestamps: true, Container: containerName})
+		req := getLogs(pod.Name, &logOptions)
 		stream, err := req.Stream(context.TODO())
 		if err != nil {
 			fmt.Fprintf(out, "failed to read logs from pod %v: %v\n", pod.Name, unwrappedError(err).Error())
@@ -318,20 +449,23 @@ func readLogs(getLogs getLogsFn, pod corev1.Pod, containerName string, out io.Wr
 
 // streamLogs runs a goroutine that streams logs of the desired container of the given pod and to the given message channel.
 // streamLogs returns a channel used to stop the goroutine.
-func streamLogs(getLogs getLogsFn, pod corev1.Pod, containerName string, out io.Writer, lastTime time.Time, msgCh chan logMessage) chan struct{} {
+func streamLogs(getLogs getLogsFn, pod corev1.Pod, logOptions corev1.PodLogOptions, out io.Writer, lastTime time.Time, msgCh chan logMessage) chan struct{} {
 	doneCh := make(chan struct{})
+	containerName := logOptions.Container
 	go func() {
 		for {
 			errCh := make(chan error)
 			go func() {
-				sinceTime := metav1.NewTime(lastTime)
-				options := &corev1.PodLogOptions{
-					Follow:     true,
-					Timestamps: true,
-					SinceTime:  &sinceTime,
-					Container:  containerName,
+				options := logOptions
+				options.Follow = true
+				if !lastTime.IsZero() {
+					// we have already shown logs up to this time, so the limits are applied only once.
+					sinceTime := metav1.NewTime(lastTime)
+					options.SinceTime = &sinceTime
+					options.SinceSeconds = nil
+					options.TailLines = nil
 				}
-				req := getLogs(pod.Name, options)
+				req := getLogs(pod.Name, &options)
 				stream, err := req.Stream(context.TODO())
 				if err != nil {
 					time.Sleep(streamLogReconnectDelay)
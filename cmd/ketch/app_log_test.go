@@ -9,6 +9,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"os"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -34,9 +35,14 @@ import (
 	"github.com/theketchio/ketch/internal/utils"
 )
 
+func int64Ref(i int64) *int64 {
+	return &i
+}
+
 func Test_watchLogs(t *testing.T) {
 	startDate := time.Date(2021, 1, 13, 16, 49, 0, 1, time.UTC)
-	readLogsLocal := func(_ getLogsFn, pod corev1.Pod, contName string, _ io.Writer) chan logMessage {
+	readLogsLocal := func(_ getLogsFn, pod corev1.Pod, logOptions corev1.PodLogOptions, _ io.Writer) chan logMessage {
+		contName := logOptions.Container
 		ch := make(chan logMessage)
 		startDate, err := time.Parse(time.RFC3339Nano, pod.Labels["TIME"])
 		require.Nil(t, err)
@@ -60,7 +66,8 @@ func Test_watchLogs(t *testing.T) {
 		return ch
 	}
 
-	streamLogsLocal := func(_ getLogsFn, pod corev1.Pod, contName string, out io.Writer, lastTime time.Time, msgCh chan logMessage) chan struct{} {
+	streamLogsLocal := func(_ getLogsFn, pod corev1.Pod, logOptions corev1.PodLogOptions, out io.Writer, lastTime time.Time, msgCh chan logMessage) chan struct{} {
+		contName := logOptions.Container
 		doneCh := make(chan struct{})
 		go func() {
 			msgs := []logMessage{
@@ -102,6 +109,12 @@ func Test_watchLogs(t *testing.T) {
 		}
 		return pod
 	}
+	restarted := func(pod *corev1.Pod) *corev1.Pod {
+		for i := range pod.Status.ContainerStatuses {
+			pod.Status.ContainerStatuses[i].RestartCount = 1
+		}
+		return pod
+	}
 	tests := []struct {
 		description        string
 		options            watchOptions
@@ -173,6 +186,47 @@ func Test_watchLogs(t *testing.T) {
 			},
 			wantOutputFilename: "./testdata/app-log/4.output",
 		},
+		{
+			description: "happy path - grep, + prefix",
+			options: watchOptions{
+				namespace: "default",
+				selector:  labels.Everything(),
+				prefix:    true,
+				grep:      regexp.MustCompile(`web-\d [13]`),
+			},
+			pods: []*corev1.Pod{
+				createPod("default", "dashboard-worker-2-random", map[string]bool{"dashboard-worker-2": true}, startDate.Add(2*time.Second)),
+				createPod("default", "hello-web-1-random", map[string]bool{"hello-web-1": true}, startDate),
+				createPod("default", "hello-web-2-random", map[string]bool{"hello-web-2": true}, startDate.Add(time.Second)),
+			},
+			wantOutputFilename: "./testdata/app-log/grep.output",
+		},
+		{
+			description: "happy path - json",
+			options: watchOptions{
+				namespace: "default",
+				selector:  labels.Everything(),
+				json:      true,
+			},
+			pods: []*corev1.Pod{
+				createPod("default", "dashboard-worker-2-random", map[string]bool{"dashboard-worker-2": true}, startDate.Add(time.Second)),
+				createPod("default", "hello-web-1-random", map[string]bool{"hello-web-1": true}, startDate),
+			},
+			wantOutputFilename: "./testdata/app-log/json.output",
+		},
+		{
+			description: "happy path - previous, only restarted containers",
+			options: watchOptions{
+				namespace: "default",
+				selector:  labels.Everything(),
+				previous:  true,
+			},
+			pods: []*corev1.Pod{
+				restarted(createPod("default", "dashboard-worker-2-random", map[string]bool{"dashboard-worker-2": true}, startDate.Add(time.Second))),
+				createPod("default", "hello-web-1-random", map[string]bool{"hello-web-1": true}, startDate),
+			},
+			wantOutputFilename: "./testdata/app-log/previous.output",
+		},
 		{
 			description: "happy path - logs from dashboard containers, + prefix, + timestamps",
 			options: watchOptions{
@@ -356,6 +410,45 @@ func Test_appLog(t *testing.T) {
 				ignoreErrors: true,
 			},
 		},
+		{
+			description: "happy path: since + tail + grep + previous + json",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{dashboard, gke},
+			},
+			options:    appLogOptions{appName: "dashboard", since: 90*time.Second + time.Millisecond, tail: 20, grep: "error|warn", previous: true, output: "json"},
+			wantCalled: true,
+			wantWatchOptions: watchOptions{
+				namespace: "ketch-gke",
+				selector: labels.SelectorFromSet(map[string]string{
+					utils.KetchAppNameLabel: "dashboard",
+				}),
+				sinceSeconds: int64Ref(91),
+				tailLines:    int64Ref(20),
+				grep:         regexp.MustCompile("error|warn"),
+				previous:     true,
+				json:         true,
+			},
+		},
+		{
+			description: "invalid grep expression",
+			options:     appLogOptions{appName: "dashboard", grep: "error("},
+			wantErr:     "invalid --grep expression: error parsing regexp: missing closing ): `error(`",
+		},
+		{
+			description: "unsupported output",
+			options:     appLogOptions{appName: "dashboard", output: "yaml"},
+			wantErr:     `unsupported output format "yaml", only "json" is supported`,
+		},
+		{
+			description: "negative tail",
+			options:     appLogOptions{appName: "dashboard", tail: -1},
+			wantErr:     "--tail must be positive",
+		},
+		{
+			description: "previous with follow",
+			options:     appLogOptions{appName: "dashboard", previous: true, follow: true},
+			wantErr:     "--previous can't be used with --follow",
+		},
 		{
 			description: "no app",
 			cfg: &mocks.Configuration{
@@ -470,6 +563,14 @@ func Test_newAppLogCmd(t *testing.T) {
 				return nil
 			},
 		},
+		{
+			description: "happy path: since, tail, grep, previous and output",
+			args:        []string{"ketch", "dashboard", "--since=10m", "--tail=50", "--grep=timeout", "--previous", "-o", "json"},
+			appLog: func(ctx context.Context, c config, options appLogOptions, writer io.Writer, fn watchLogsFn) error {
+				require.Equal(t, appLogOptions{appName: "dashboard", since: 10 * time.Minute, tail: 50, grep: "timeout", previous: true, output: "json"}, options)
+				return nil
+			},
+		},
 		{
 			description: "bad app name",
 			args:        []string{"ketch", "_._"},
@@ -569,7 +670,7 @@ func Test_readLogs(t *testing.T) {
 				return fakeClient.Request()
 			}
 			out := &bytes.Buffer{}
-			got := readLogs(getLogs, tt.pod, tt.containerName, out)
+			got := readLogs(getLogs, tt.pod, corev1.PodLogOptions{Container: tt.containerName, Timestamps: true}, out)
 			var msgs []string
 			for msg := range got {
 				require.Equal(t, tt.pod, *msg.pod)
@@ -673,7 +774,7 @@ func Test_streamLogs(t *testing.T) {
 			}
 			out := &bytes.Buffer{}
 			msgCh := make(chan logMessage)
-			doneCh := streamLogs(getLogs, tt.pod, tt.containerName, out, time.Time{}, msgCh)
+			doneCh := streamLogs(getLogs, tt.pod, corev1.PodLogOptions{Container: tt.containerName, Timestamps: true}, out, time.Time{}, msgCh)
 			require.NotNil(t, doneCh)
 			var msgs []string
 			ch := time.After(1 * time.Second)
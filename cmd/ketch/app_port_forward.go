@@ -0,0 +1,321 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/utils"
+	"github.com/theketchio/ketch/internal/validation"
+)
+
+const appPortForwardHelp = `
+Forward a local port to a process of an application, including processes without public routes.
+The connection goes over the kubernetes API to a ready unit of the process.
+If the unit is replaced, for example during a deployment, ketch reconnects to another ready unit.
+The process's port is the first port of its container unless it's specified.
+
+  ketch app port-forward APPNAME 8080:web
+  ketch app port-forward APPNAME 9090:worker:8081
+`
+
+// portForwardReconnectDelay is how long to wait before looking for another ready unit.
+const portForwardReconnectDelay = time.Second
+
+type appPortForwardFn func(context.Context, config, appPortForwardOptions, io.Writer, portForwardFn) error
+
+// portForwardFn forwards the ports to the pod until the stop channel is closed or the connection is lost.
+type portForwardFn func(restConfig *rest.Config, cli kubernetes.Interface, pod corev1.Pod, ports []string, stopCh <-chan struct{}, out io.Writer) error
+
+func newAppPortForwardCmd(cfg config, out io.Writer, appPortForward appPortForwardFn) *cobra.Command {
+	options := appPortForwardOptions{}
+	cmd := &cobra.Command{
+		Use:   "port-forward APPNAME LOCAL_PORT:PROCESS[:PORT]",
+		Short: "Forward a local port to a process of an application.",
+		Args:  cobra.ExactArgs(2),
+		Long:  appPortForwardHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.appName = args[0]
+			if !validation.ValidateName(options.appName) {
+				return ErrInvalidAppName
+			}
+			if err := options.parsePorts(args[1]); err != nil {
+				return err
+			}
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			return appPortForward(ctx, cfg, options, out, forwardPorts)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return autoCompleteAppNames(cfg, toComplete)
+		},
+	}
+	return cmd
+}
+
+type appPortForwardOptions struct {
+	appName     string
+	processName string
+	localPort   int
+	// remotePort is a port of the process's container, zero means the first port of the container.
+	remotePort int
+}
+
+// parsePorts parses a LOCAL_PORT:PROCESS[:PORT] argument.
+func (o *appPortForwardOptions) parsePorts(arg string) error {
+	parts := strings.Split(arg, ":")
+	if len(parts) < 2 || len(parts) > 3 || len(parts[1]) == 0 {
+		return fmt.Errorf("invalid port mapping %q, the format is LOCAL_PORT:PROCESS[:PORT]", arg)
+	}
+	localPort, err := strconv.Atoi(parts[0])
+	if err != nil || localPort < 0 || localPort > 65535 {
+		return fmt.Errorf("invalid local port %q", parts[0])
+	}
+	o.localPort = localPort
+	o.processName = parts[1]
+	if len(parts) == 3 {
+		remotePort, err := strconv.Atoi(parts[2])
+		if err != nil || remotePort < 1 || remotePort > 65535 {
+			return fmt.Errorf("invalid port %q", parts[2])
+		}
+		o.remotePort = remotePort
+	}
+	return nil
+}
+
+func appPortForward(ctx context.Context, cfg config, options appPortForwardOptions, out io.Writer, forward portForwardFn) error {
+	app := ketchv1.App{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.appName}, &app); err != nil {
+		return fmt.Errorf("failed to get app: %w", err)
+	}
+	if !hasProcess(app, options.processName) {
+		return fmt.Errorf("process %s is not found", options.processName)
+	}
+	namespace, err := appFrameworkNamespace(ctx, cfg, app)
+	if err != nil {
+		return err
+	}
+	cli := cfg.KubernetesClient()
+	selector := labels.SelectorFromSet(map[string]string{
+		utils.KetchAppNameLabel:     app.Name,
+		utils.KetchProcessNameLabel: options.processName,
+	})
+	connected := false
+	for {
+		pod, err := readyProcessPod(ctx, cli, namespace, selector)
+		if err != nil {
+			return err
+		}
+		if pod == nil {
+			if !connected {
+				return fmt.Errorf("no ready units of process %s found", options.processName)
+			}
+			if !sleepContext(ctx, portForwardReconnectDelay) {
+				return nil
+			}
+			continue
+		}
+		remotePort := options.remotePort
+		if remotePort == 0 {
+			if remotePort, err = firstContainerPort(*pod); err != nil {
+				return err
+			}
+		}
+		fmt.Fprintf(out, "Forwarding to unit %s\n", pod.Name)
+		err = forwardUntilReplaced(ctx, cfg.RestConfig(), cli, *pod, []string{fmt.Sprintf("%d:%d", options.localPort, remotePort)}, out, forward)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			if !connected {
+				return err
+			}
+			// the unit may have gone away before we connected to it, let's look for another one.
+			fmt.Fprintf(out, "failed to forward to unit %s: %v\n", pod.Name, err)
+			if !sleepContext(ctx, portForwardReconnectDelay) {
+				return nil
+			}
+		}
+		connected = true
+	}
+}
+
+// forwardUntilReplaced forwards the ports to the pod until the pod is deleted or becomes not ready.
+func forwardUntilReplaced(ctx context.Context, restConfig *rest.Config, cli kubernetes.Interface, pod corev1.Pod, ports []string, out io.Writer, forward portForwardFn) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stopCh := make(chan struct{})
+	watchErr := make(chan error, 1)
+	go func() {
+		defer close(stopCh)
+		watchErr <- waitForPodReplacement(ctx, cli.CoreV1().Pods(pod.Namespace), pod)
+	}()
+	err := forward(restConfig, cli, pod, ports, stopCh, out)
+	cancel()
+	if e := <-watchErr; e != nil && !errors.Is(e, context.Canceled) && err == nil {
+		return e
+	}
+	return err
+}
+
+// waitForPodReplacement returns once the pod is deleted or becomes not ready.
+// The watch is re-established when the API server closes it, e.g. on its timeout.
+func waitForPodReplacement(ctx context.Context, pods corev1client.PodInterface, pod corev1.Pod) error {
+	resourceVersion := pod.ResourceVersion
+	for {
+		watcher, err := pods.Watch(ctx, metav1.ListOptions{
+			FieldSelector:   fields.OneTermEqualSelector("metadata.name", pod.Name).String(),
+			ResourceVersion: resourceVersion,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to watch pod %s: %w", pod.Name, err)
+		}
+		replaced, err := watchPodReplacement(ctx, watcher, &resourceVersion)
+		watcher.Stop()
+		if err != nil || replaced {
+			return err
+		}
+	}
+}
+
+// watchPodReplacement reads events of the watcher until the pod is deleted or becomes not ready, or the watch is closed.
+// It keeps track of the pod's resource version to re-establish the watch from.
+func watchPodReplacement(ctx context.Context, watcher watch.Interface, resourceVersion *string) (bool, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case e, ok := <-watcher.ResultChan():
+			if !ok {
+				return false, nil
+			}
+			switch e.Type {
+			case watch.Deleted:
+				return true, nil
+			case watch.Error:
+				status := apierrors.FromObject(e.Object)
+				if apierrors.IsResourceExpired(status) || apierrors.IsGone(status) {
+					// the resource version is too old, let's watch the current state of the pod.
+					*resourceVersion = ""
+					return false, nil
+				}
+				return false, status
+			}
+			pod, ok := e.Object.(*corev1.Pod)
+			if !ok {
+				continue
+			}
+			*resourceVersion = pod.ResourceVersion
+			if pod.DeletionTimestamp != nil || !isPodReady(*pod) {
+				return true, nil
+			}
+		}
+	}
+}
+
+func hasProcess(app ketchv1.App, processName string) bool {
+	for _, deployment := range app.Spec.Deployments {
+		for _, process := range deployment.Processes {
+			if process.Name == processName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// readyProcessPod returns the most recently created ready pod or nil if there is no ready pod.
+func readyProcessPod(ctx context.Context, cli kubernetes.Interface, namespace string, selector labels.Selector) (*corev1.Pod, error) {
+	pods, err := cli.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get list of pods: %w", err)
+	}
+	sort.Slice(pods.Items, func(i, j int) bool {
+		return pods.Items[j].CreationTimestamp.Before(&pods.Items[i].CreationTimestamp)
+	})
+	for i, pod := range pods.Items {
+		if pod.DeletionTimestamp == nil && isPodReady(pod) {
+			return &pods.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func isPodReady(pod corev1.Pod) bool {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func firstContainerPort(pod corev1.Pod) (int, error) {
+	containerName, err := ketchContainerName(pod)
+	if err != nil {
+		return 0, err
+	}
+	for _, c := range pod.Spec.Containers {
+		if c.Name == *containerName && len(c.Ports) > 0 {
+			return int(c.Ports[0].ContainerPort), nil
+		}
+	}
+	return 0, fmt.Errorf("container %s doesn't expose ports, specify the port as LOCAL_PORT:PROCESS:PORT", *containerName)
+}
+
+// sleepContext waits for the duration and returns false if the context is done earlier.
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
+
+// forwardPorts forwards the ports to the pod over the kubernetes API using the SPDY protocol.
+func forwardPorts(restConfig *rest.Config, cli kubernetes.Interface, pod corev1.Pod, ports []string, stopCh <-chan struct{}, out io.Writer) error {
+	transport, upgrader, err := spdy.RoundTripperFor(restConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create round tripper: %w", err)
+	}
+	url := cli.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("portforward").
+		URL()
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, url)
+	forwarder, err := portforward.New(dialer, ports, stopCh, make(chan struct{}), out, os.Stderr)
+	if err != nil {
+		return fmt.Errorf("failed to forward ports: %w", err)
+	}
+	return forwarder.ForwardPorts()
+}
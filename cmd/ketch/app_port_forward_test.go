@@ -0,0 +1,225 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/mocks"
+	"github.com/theketchio/ketch/internal/utils"
+)
+
+func TestAppPortForwardOptions_parsePorts(t *testing.T) {
+	tests := []struct {
+		arg     string
+		want    appPortForwardOptions
+		wantErr string
+	}{
+		{arg: "8080:web", want: appPortForwardOptions{localPort: 8080, processName: "web"}},
+		{arg: "9090:worker:8081", want: appPortForwardOptions{localPort: 9090, processName: "worker", remotePort: 8081}},
+		{arg: "8080", wantErr: `invalid port mapping "8080", the format is LOCAL_PORT:PROCESS[:PORT]`},
+		{arg: "8080::9090", wantErr: `invalid port mapping "8080::9090", the format is LOCAL_PORT:PROCESS[:PORT]`},
+		{arg: "web:8080", wantErr: `invalid local port "web"`},
+		{arg: "8080:web:http", wantErr: `invalid port "http"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			options := appPortForwardOptions{}
+			err := options.parsePorts(tt.arg)
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.want, options)
+		})
+	}
+}
+
+func TestAppPortForward(t *testing.T) {
+	dashboard := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+		Spec: ketchv1.AppSpec{
+			Framework: "gke",
+			Deployments: []ketchv1.AppDeploymentSpec{
+				{Version: 1, Processes: []ketchv1.ProcessSpec{{Name: "web"}, {Name: "worker"}}},
+			},
+		},
+	}
+	gke := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "gke"},
+		Spec:       ketchv1.FrameworkSpec{NamespaceName: "ketch-gke"},
+	}
+	pod := func(name, process string, created time.Time, ready corev1.ConditionStatus) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "ketch-gke",
+				CreationTimestamp: metav1.NewTime(created),
+				Labels: map[string]string{
+					utils.KetchAppNameLabel:     "dashboard",
+					utils.KetchProcessNameLabel: process,
+				},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{Name: "dashboard-" + process + "-1", Ports: []corev1.ContainerPort{{ContainerPort: 9090}}},
+				},
+			},
+			Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}},
+			},
+		}
+	}
+	now := time.Now()
+	pods := []runtime.Object{
+		pod("dashboard-web-1-abc-1", "web", now.Add(-time.Hour), corev1.ConditionTrue),
+		pod("dashboard-web-1-abc-2", "web", now, corev1.ConditionTrue),
+		pod("dashboard-web-1-abc-3", "web", now.Add(time.Minute), corev1.ConditionFalse),
+		pod("dashboard-worker-1-abc-1", "worker", now, corev1.ConditionFalse),
+	}
+
+	t.Run("reconnect when the unit is replaced", func(t *testing.T) {
+		cfg := &mocks.Configuration{CtrlClientObjects: []runtime.Object{dashboard, gke}, KubeClientObjects: pods}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var forwarded []string
+		var forwardedPorts [][]string
+		forward := func(restConfig *rest.Config, cli kubernetes.Interface, pod corev1.Pod, ports []string, stopCh <-chan struct{}, out io.Writer) error {
+			forwarded = append(forwarded, pod.Name)
+			forwardedPorts = append(forwardedPorts, ports)
+			if len(forwarded) == 1 {
+				// the unit is removed by a rollout.
+				return cli.CoreV1().Pods(pod.Namespace).Delete(context.Background(), pod.Name, metav1.DeleteOptions{})
+			}
+			cancel()
+			return nil
+		}
+		out := &bytes.Buffer{}
+		options := appPortForwardOptions{appName: "dashboard", processName: "web", localPort: 8080}
+		err := appPortForward(ctx, cfg, options, out, forward)
+		require.Nil(t, err)
+		require.Equal(t, []string{"dashboard-web-1-abc-2", "dashboard-web-1-abc-1"}, forwarded)
+		require.Equal(t, [][]string{{"8080:9090"}, {"8080:9090"}}, forwardedPorts)
+		require.Equal(t, "Forwarding to unit dashboard-web-1-abc-2\nForwarding to unit dashboard-web-1-abc-1\n", out.String())
+	})
+
+	tests := []struct {
+		name    string
+		options appPortForwardOptions
+		wantErr string
+	}{
+		{
+			name:    "no ready units",
+			options: appPortForwardOptions{appName: "dashboard", processName: "worker", localPort: 8080},
+			wantErr: "no ready units of process worker found",
+		},
+		{
+			name:    "unknown process",
+			options: appPortForwardOptions{appName: "dashboard", processName: "cron", localPort: 8080},
+			wantErr: "process cron is not found",
+		},
+		{
+			name:    "no app",
+			options: appPortForwardOptions{appName: "hello", processName: "web", localPort: 8080},
+			wantErr: `failed to get app: apps.theketch.io "hello" not found`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &mocks.Configuration{CtrlClientObjects: []runtime.Object{dashboard, gke}, KubeClientObjects: pods}
+			forward := func(restConfig *rest.Config, cli kubernetes.Interface, pod corev1.Pod, ports []string, stopCh <-chan struct{}, out io.Writer) error {
+				t.Fatal("unexpected port forwarding")
+				return nil
+			}
+			err := appPortForward(context.Background(), cfg, tt.options, &bytes.Buffer{}, forward)
+			require.NotNil(t, err)
+			require.Equal(t, tt.wantErr, err.Error())
+		})
+	}
+}
+
+func TestWaitForPodReplacement(t *testing.T) {
+	pod := func(ready corev1.ConditionStatus) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "dashboard-web-1-abc-1", Namespace: "ketch-gke"},
+			Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}},
+			},
+		}
+	}
+	tests := []struct {
+		name    string
+		events  [][]watch.Event
+		wantErr string
+	}{
+		{
+			name: "pod deleted after the watch is closed",
+			events: [][]watch.Event{
+				{{Type: watch.Modified, Object: pod(corev1.ConditionTrue)}},
+				{{Type: watch.Deleted, Object: pod(corev1.ConditionTrue)}},
+			},
+		},
+		{
+			name: "pod becomes not ready",
+			events: [][]watch.Event{
+				{{Type: watch.Modified, Object: pod(corev1.ConditionTrue)}, {Type: watch.Modified, Object: pod(corev1.ConditionFalse)}},
+			},
+		},
+		{
+			name: "expired resource version",
+			events: [][]watch.Event{
+				{{Type: watch.Error, Object: &metav1.Status{Status: metav1.StatusFailure, Reason: metav1.StatusReasonExpired, Code: 410}}},
+				{{Type: watch.Deleted, Object: pod(corev1.ConditionTrue)}},
+			},
+		},
+		{
+			name: "watch error",
+			events: [][]watch.Event{
+				{{Type: watch.Error, Object: &metav1.Status{Status: metav1.StatusFailure, Message: "internal error", Reason: metav1.StatusReasonInternalError, Code: 500}}},
+			},
+			wantErr: "internal error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := fake.NewSimpleClientset()
+			watches := 0
+			cli.PrependWatchReactor("pods", func(action k8stesting.Action) (bool, watch.Interface, error) {
+				require.Less(t, watches, len(tt.events), "unexpected watch")
+				watcher := watch.NewFakeWithChanSize(len(tt.events[watches]), false)
+				for _, e := range tt.events[watches] {
+					watcher.Action(e.Type, e.Object)
+				}
+				// every watch but the last one is closed by the API server.
+				if watches < len(tt.events)-1 {
+					watcher.Stop()
+				}
+				watches++
+				return true, watcher, nil
+			})
+			err := waitForPodReplacement(context.Background(), cli.CoreV1().Pods("ketch-gke"), *pod(corev1.ConditionTrue))
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, len(tt.events), watches)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/validation"
+)
+
+const appPromoteHelp = `
+Promote a blue-green deployment of an application.
+All traffic is switched to the new deployment at once.
+The previous deployment is kept for the grace period of the blue-green deployment,
+promoting again during the grace period switches the traffic back to it.
+`
+
+type appPromoteFn func(context.Context, config, appPromoteOptions, io.Writer) error
+
+func newAppPromoteCmd(cfg config, out io.Writer, appPromote appPromoteFn) *cobra.Command {
+	options := appPromoteOptions{}
+	cmd := &cobra.Command{
+		Use:   "promote APPNAME",
+		Short: "Promote a blue-green deployment of an application.",
+		Args:  cobra.ExactValidArgs(1),
+		Long:  appPromoteHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.appName = args[0]
+			if !validation.ValidateName(options.appName) {
+				return ErrInvalidAppName
+			}
+			return appPromote(cmd.Context(), cfg, options, out)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return autoCompleteAppNames(cfg, toComplete)
+		},
+	}
+	return cmd
+}
+
+type appPromoteOptions struct {
+	appName string
+}
+
+func appPromote(ctx context.Context, cfg config, options appPromoteOptions, out io.Writer) error {
+	app := ketchv1.App{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.appName}, &app); err != nil {
+		return fmt.Errorf("failed to get app: %w", err)
+	}
+	if err := app.PromoteBlueGreen(metav1.NewTime(time.Now())); err != nil {
+		return fmt.Errorf("failed to promote app: %w", err)
+	}
+	if err := cfg.Client().Update(ctx, &app); err != nil {
+		return fmt.Errorf("failed to update app: %w", err)
+	}
+	fmt.Fprintf(out, "Successfully promoted! The previous deployment is kept for %s.\n", app.Spec.BlueGreen.GracePeriod)
+	return nil
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/mocks"
+)
+
+func TestNewAppPromoteCmd(t *testing.T) {
+	pflag.CommandLine = pflag.NewFlagSet("ketch", pflag.ExitOnError)
+
+	tt := []struct {
+		description string
+		args        []string
+		appPromote  appPromoteFn
+		wantErr     bool
+	}{
+		{
+			description: "happy path",
+			args:        []string{"ketch", "myapp"},
+			appPromote: func(_ context.Context, _ config, opts appPromoteOptions, _ io.Writer) error {
+				require.Equal(t, "myapp", opts.appName)
+				return nil
+			},
+		},
+		{
+			description: "missing positional",
+			args:        []string{"ketch"},
+			wantErr:     true,
+		},
+		{
+			description: "invalid app name",
+			args:        []string{"ketch", "MyApp"},
+			wantErr:     true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.description, func(t *testing.T) {
+			os.Args = tc.args
+			cmd := newAppPromoteCmd(nil, nil, tc.appPromote)
+			err := cmd.Execute()
+			if tc.wantErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+		})
+	}
+}
+
+func TestAppPromote(t *testing.T) {
+	blueGreenApp := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "go-app",
+		},
+		Spec: ketchv1.AppSpec{
+			BlueGreen: ketchv1.BlueGreenSpec{Active: true, GracePeriod: 10 * time.Minute},
+			Deployments: []ketchv1.AppDeploymentSpec{
+				{Version: 1, RoutingSettings: ketchv1.RoutingSettings{Weight: 100}},
+				{Version: 2, RoutingSettings: ketchv1.RoutingSettings{Weight: 0}},
+			},
+			Framework: "aws",
+		},
+	}
+	goApp := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "go-app",
+		},
+		Spec: ketchv1.AppSpec{
+			Deployments: []ketchv1.AppDeploymentSpec{
+				{Version: 1, RoutingSettings: ketchv1.RoutingSettings{Weight: 100}},
+			},
+			Framework: "aws",
+		},
+	}
+	tests := []struct {
+		name       string
+		cfg        config
+		options    appPromoteOptions
+		wantOutput string
+		wantErr    string
+	}{
+		{
+			name: "promote blue-green deployment",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{blueGreenApp},
+			},
+			options:    appPromoteOptions{appName: "go-app"},
+			wantOutput: "Successfully promoted! The previous deployment is kept for 10m0s.\n",
+		},
+		{
+			name: "blue-green is not active",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{goApp},
+			},
+			options: appPromoteOptions{appName: "go-app"},
+			wantErr: "failed to promote app: blue-green deployment is not active",
+		},
+		{
+			name: "no app",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{},
+			},
+			options: appPromoteOptions{appName: "go-app"},
+			wantErr: `failed to get app: apps.theketch.io "go-app" not found`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			err := appPromote(context.Background(), tt.cfg, tt.options, out)
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.wantOutput, out.String())
+			gotApp := ketchv1.App{}
+			err = tt.cfg.Client().Get(context.Background(), types.NamespacedName{Name: tt.options.appName}, &gotApp)
+			require.Nil(t, err)
+			require.Equal(t, uint8(0), gotApp.Spec.Deployments[0].RoutingSettings.Weight)
+			require.Equal(t, uint8(100), gotApp.Spec.Deployments[1].RoutingSettings.Weight)
+			require.NotNil(t, gotApp.Spec.BlueGreen.PromotedAt)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/theketchio/ketch/internal/deploy"
+	"github.com/theketchio/ketch/internal/validation"
+)
+
+const appResourcesHelp = `
+Manage compute resources of an application's processes.
+`
+
+const appResourcesSetHelp = `
+Set compute resources of a process of an application.
+Resources that are not specified are kept unchanged.
+
+  ketch app resources set APPNAME --process web --cpu 500m --memory 512Mi
+  ketch app resources set APPNAME --process worker --cpu-limit 1 --memory-limit 1Gi
+`
+
+type appResourcesSetFn func(context.Context, config, appResourcesSetOptions, io.Writer) error
+
+func newAppResourcesCmd(cfg config, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "resources",
+		Short: "Manage compute resources of an application's processes.",
+		Long:  appResourcesHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Usage()
+		},
+	}
+	cmd.AddCommand(newAppResourcesSetCmd(cfg, out, appResourcesSet))
+	return cmd
+}
+
+func newAppResourcesSetCmd(cfg config, out io.Writer, appResourcesSet appResourcesSetFn) *cobra.Command {
+	options := appResourcesSetOptions{}
+	cmd := &cobra.Command{
+		Use:   "set APPNAME",
+		Short: "Set compute resources of a process of an application.",
+		Args:  cobra.ExactValidArgs(1),
+		Long:  appResourcesSetHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.appName = args[0]
+			if !validation.ValidateName(options.appName) {
+				return ErrInvalidAppName
+			}
+			return appResourcesSet(cmd.Context(), cfg, options, out)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return autoCompleteAppNames(cfg, toComplete)
+		},
+	}
+	cmd.Flags().StringVarP(&options.processName, "process", "p", "", "Process name.")
+	cmd.Flags().IntVarP(&options.deploymentVersion, "version", "v", 0, "Deployment version.")
+	cmd.Flags().StringVar(&options.cpu, "cpu", "", "CPU request of the process, e.g. 500m.")
+	cmd.Flags().StringVar(&options.memory, "memory", "", "Memory request of the process, e.g. 512Mi.")
+	cmd.Flags().StringVar(&options.cpuLimit, "cpu-limit", "", "CPU limit of the process.")
+	cmd.Flags().StringVar(&options.memoryLimit, "memory-limit", "", "Memory limit of the process.")
+	cmd.MarkFlagRequired("process")
+	return cmd
+}
+
+type appResourcesSetOptions struct {
+	appName           string
+	processName       string
+	deploymentVersion int
+	cpu               string
+	memory            string
+	cpuLimit          string
+	memoryLimit       string
+}
+
+// resourceLists returns requests and limits specified by the options.
+func (o appResourcesSetOptions) resourceLists() (corev1.ResourceList, corev1.ResourceList, error) {
+	requests := corev1.ResourceList{}
+	limits := corev1.ResourceList{}
+	for _, r := range []struct {
+		list  corev1.ResourceList
+		name  corev1.ResourceName
+		flag  string
+		value string
+	}{
+		{requests, corev1.ResourceCPU, "cpu", o.cpu},
+		{requests, corev1.ResourceMemory, "memory", o.memory},
+		{limits, corev1.ResourceCPU, "cpu-limit", o.cpuLimit},
+		{limits, corev1.ResourceMemory, "memory-limit", o.memoryLimit},
+	} {
+		if len(r.value) == 0 {
+			continue
+		}
+		quantity, err := resource.ParseQuantity(r.value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid --%s %q: %w", r.flag, r.value, err)
+		}
+		r.list[r.name] = quantity
+	}
+	if len(requests) == 0 && len(limits) == 0 {
+		return nil, nil, fmt.Errorf("at least one of --cpu, --memory, --cpu-limit or --memory-limit is required")
+	}
+	return requests, limits, nil
+}
+
+func appResourcesSet(ctx context.Context, cfg config, options appResourcesSetOptions, out io.Writer) error {
+	requests, limits, err := options.resourceLists()
+	if err != nil {
+		return err
+	}
+	changeSet := deploy.ProcessOptions{
+		AppName:  options.appName,
+		Version:  options.deploymentVersion,
+		Process:  options.processName,
+		Requests: requests,
+		Limits:   limits,
+	}.GetChangeSet()
+	svc := &deploy.Services{Client: cfg.Client(), Writer: out}
+	if err := deploy.New(changeSet).UpdateProcesses(ctx, svc); err != nil {
+		return fmt.Errorf("failed to set resources: %w", err)
+	}
+	fmt.Fprintln(out, "Successfully updated!")
+	return nil
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/mocks"
+)
+
+func TestAppResourcesSet(t *testing.T) {
+	dashboard := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+		Spec: ketchv1.AppSpec{
+			Deployments: []ketchv1.AppDeploymentSpec{
+				{
+					Version: 1,
+					Processes: []ketchv1.ProcessSpec{
+						{
+							Name: "web",
+							Resources: &corev1.ResourceRequirements{
+								Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+							},
+						},
+						{Name: "worker"},
+					},
+				},
+			},
+		},
+	}
+	tests := []struct {
+		name          string
+		options       appResourcesSetOptions
+		wantResources *corev1.ResourceRequirements
+		wantOut       string
+		wantErr       string
+	}{
+		{
+			name:    "requests",
+			options: appResourcesSetOptions{appName: "dashboard", processName: "web", cpu: "500m", memory: "512Mi"},
+			wantResources: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("500m"),
+					corev1.ResourceMemory: resource.MustParse("512Mi"),
+				},
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+			},
+			wantOut: "Successfully updated!\n",
+		},
+		{
+			name:    "limits",
+			options: appResourcesSetOptions{appName: "dashboard", processName: "web", cpuLimit: "1", memoryLimit: "2Gi"},
+			wantResources: &corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("1"),
+					corev1.ResourceMemory: resource.MustParse("2Gi"),
+				},
+			},
+			wantOut: "Successfully updated!\n",
+		},
+		{
+			name:    "no resources",
+			options: appResourcesSetOptions{appName: "dashboard", processName: "web"},
+			wantErr: "at least one of --cpu, --memory, --cpu-limit or --memory-limit is required",
+		},
+		{
+			name:    "invalid quantity",
+			options: appResourcesSetOptions{appName: "dashboard", processName: "web", cpu: "lots"},
+			wantErr: `invalid --cpu "lots": quantities must match the regular expression '^([+-]?[0-9.]+)([eEinumkKMGTP]*[-+]?[0-9]*)$'`,
+		},
+		{
+			name:    "unknown process",
+			options: appResourcesSetOptions{appName: "dashboard", processName: "cron", cpu: "1"},
+			wantErr: "failed to set resources: process not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &mocks.Configuration{CtrlClientObjects: []runtime.Object{dashboard.DeepCopy()}}
+			out := &bytes.Buffer{}
+			err := appResourcesSet(context.Background(), cfg, tt.options, out)
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.wantOut, out.String())
+
+			app := ketchv1.App{}
+			require.Nil(t, cfg.Client().Get(context.Background(), types.NamespacedName{Name: "dashboard"}, &app))
+			require.Equal(t, tt.wantResources, app.Spec.Deployments[0].Processes[0].Resources)
+			require.Nil(t, app.Spec.Deployments[0].Processes[1].Resources)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/validation"
+)
+
+const appRollbackHelp = `
+Roll back an application to one of its previous deployments.
+By default, the application is rolled back to the deployment preceding the current one.
+The deployment is redeployed under a new version.
+`
+
+type appRollbackFn func(context.Context, config, appRollbackOptions, io.Writer) error
+
+func newAppRollbackCmd(cfg config, out io.Writer, appRollback appRollbackFn) *cobra.Command {
+	options := appRollbackOptions{}
+	cmd := &cobra.Command{
+		Use:   "rollback APPNAME",
+		Short: "Roll back an application to one of its previous deployments.",
+		Args:  cobra.ExactValidArgs(1),
+		Long:  appRollbackHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.appName = args[0]
+			if !validation.ValidateName(options.appName) {
+				return ErrInvalidAppName
+			}
+			return appRollback(cmd.Context(), cfg, options, out)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return autoCompleteAppNames(cfg, toComplete)
+		},
+	}
+
+	cmd.Flags().IntVar(&options.toVersion, "to-version", 0, "Deployment version to roll back to.")
+
+	return cmd
+}
+
+type appRollbackOptions struct {
+	appName   string
+	toVersion int
+}
+
+func appRollback(ctx context.Context, cfg config, options appRollbackOptions, out io.Writer) error {
+	app := ketchv1.App{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.appName}, &app); err != nil {
+		return fmt.Errorf("failed to get app: %w", err)
+	}
+	if err := app.Rollback(ketchv1.DeploymentVersion(options.toVersion)); err != nil {
+		return fmt.Errorf("failed to roll back app: %w", err)
+	}
+	if err := cfg.Client().Update(ctx, &app); err != nil {
+		return fmt.Errorf("failed to update app: %w", err)
+	}
+	fmt.Fprintf(out, "Successfully rolled back! New deployment version is %d.\n", app.Spec.DeploymentsCount)
+	return nil
+}
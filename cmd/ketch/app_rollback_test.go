@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/mocks"
+)
+
+func TestNewAppRollbackCmd(t *testing.T) {
+	pflag.CommandLine = pflag.NewFlagSet("ketch", pflag.ExitOnError)
+
+	tt := []struct {
+		description string
+		args        []string
+		appRollback appRollbackFn
+		wantErr     bool
+	}{
+		{
+			description: "happy path",
+			args:        []string{"ketch", "myapp", "--to-version", "2"},
+			appRollback: func(_ context.Context, _ config, opts appRollbackOptions, _ io.Writer) error {
+				require.Equal(t, "myapp", opts.appName)
+				require.Equal(t, 2, opts.toVersion)
+				return nil
+			},
+		},
+		{
+			description: "missing positional",
+			args:        []string{"ketch", "--to-version", "2"},
+			wantErr:     true,
+		},
+		{
+			description: "extra positional",
+			args:        []string{"ketch", "myapp", "extra"},
+			wantErr:     true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.description, func(t *testing.T) {
+			os.Args = tc.args
+			cmd := newAppRollbackCmd(nil, nil, tc.appRollback)
+			err := cmd.Execute()
+			if tc.wantErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+		})
+	}
+}
+
+func TestAppRollback(t *testing.T) {
+	v1 := ketchv1.AppDeploymentSpec{Version: 1, Image: "shipasoftware/go-app:v1", RoutingSettings: ketchv1.RoutingSettings{Weight: 100}}
+	v2 := ketchv1.AppDeploymentSpec{Version: 2, Image: "shipasoftware/go-app:v2", RoutingSettings: ketchv1.RoutingSettings{Weight: 100}}
+	goApp := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "go-app",
+		},
+		Spec: ketchv1.AppSpec{
+			Deployments:      []ketchv1.AppDeploymentSpec{v2},
+			DeploymentsCount: 2,
+			Framework:        "aws",
+		},
+		Status: ketchv1.AppStatus{
+			History: []ketchv1.AppDeploymentSpec{v1, v2},
+		},
+	}
+	tests := []struct {
+		name      string
+		cfg       config
+		options   appRollbackOptions
+		wantImage string
+		wantErr   bool
+	}{
+		{
+			name: "previous deployment",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{goApp},
+			},
+			options:   appRollbackOptions{appName: "go-app"},
+			wantImage: "shipasoftware/go-app:v1",
+		},
+		{
+			name: "unknown version",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{goApp},
+			},
+			options: appRollbackOptions{appName: "go-app", toVersion: 5},
+			wantErr: true,
+		},
+		{
+			name: "no app",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{},
+			},
+			options: appRollbackOptions{appName: "go-app"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			err := appRollback(context.Background(), tt.cfg, tt.options, out)
+			if tt.wantErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			gotApp := ketchv1.App{}
+			err = tt.cfg.Client().Get(context.Background(), types.NamespacedName{Name: tt.options.appName}, &gotApp)
+			require.Nil(t, err)
+			require.Len(t, gotApp.Spec.Deployments, 1)
+			require.Equal(t, tt.wantImage, gotApp.Spec.Deployments[0].Image)
+			require.Equal(t, ketchv1.DeploymentVersion(3), gotApp.Spec.Deployments[0].Version)
+			require.Equal(t, 3, gotApp.Spec.DeploymentsCount)
+		})
+	}
+}
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/utils"
+	"github.com/theketchio/ketch/internal/validation"
+)
+
+const appRunHelp = `
+Run a one-off command in a new pod of an application.
+The pod uses the image, environment variables, service account and image pull secrets
+of a process of the application's latest deployment. It doesn't receive any traffic.
+The output of the command is streamed back, ketch exits with the exit code of the command
+and removes the pod afterwards.
+
+  ketch app run APPNAME -- rake db:migrate
+  ketch app run APPNAME --process worker -- ./manage.py clearsessions
+`
+
+// isolatedRunContainerSuffix is appended to the app name to get the name of the isolated run container.
+// The name of the pod starts with the container name, so "ketch app log" finds the container.
+const isolatedRunContainerSuffix = "-run"
+
+type appRunFn func(context.Context, config, appRunOptions, io.Writer, runPodFn) error
+
+type runPodFn func(ctx context.Context, cli kubernetes.Interface, pod *corev1.Pod, startTimeout time.Duration, logOptions watchOptions) (int, error)
+
+func newAppRunCmd(cfg config, out io.Writer, appRun appRunFn) *cobra.Command {
+	options := appRunOptions{}
+	cmd := &cobra.Command{
+		Use:   "run APPNAME -- COMMAND [ARGS...]",
+		Short: "Run a one-off command in a new pod of an application.",
+		Args:  cobra.MinimumNArgs(2),
+		Long:  appRunHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.appName = args[0]
+			if !validation.ValidateName(options.appName) {
+				return ErrInvalidAppName
+			}
+			options.command = args[1:]
+			return appRun(cmd.Context(), cfg, options, out, runPod)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return autoCompleteAppNames(cfg, toComplete)
+		},
+	}
+	cmd.Flags().StringVarP(&options.processName, "process", "p", "", "Process whose pod spec is used, the first process by default.")
+	cmd.Flags().IntVarP(&options.deploymentVersion, "version", "v", 0, "Deployment version, the latest deployment by default.")
+	cmd.Flags().DurationVar(&options.startTimeout, "timeout", 5*time.Minute, "How long to wait for the pod to start.")
+	cmd.Flags().BoolVar(&options.prefix, "prefix", false, "Prefix each log line with the log source (pod name and container name)")
+	cmd.Flags().BoolVar(&options.timestamps, "timestamps", false, "Include timestamps on each line in the log output")
+	return cmd
+}
+
+type appRunOptions struct {
+	appName           string
+	processName       string
+	deploymentVersion int
+	command           []string
+	startTimeout      time.Duration
+	prefix            bool
+	timestamps        bool
+}
+
+func appRun(ctx context.Context, cfg config, options appRunOptions, out io.Writer, runPod runPodFn) error {
+	app := ketchv1.App{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.appName}, &app); err != nil {
+		return fmt.Errorf("failed to get app: %w", err)
+	}
+	namespace, err := appFrameworkNamespace(ctx, cfg, app)
+	if err != nil {
+		return err
+	}
+	deploymentSpec, process, err := appProcess(app, options.processName, options.deploymentVersion)
+	if err != nil {
+		return err
+	}
+	deployment := appsv1.Deployment{}
+	deploymentName := fmt.Sprintf("%s-%s-%d", app.Name, process.Name, deploymentSpec.Version)
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Namespace: namespace, Name: deploymentName}, &deployment); err != nil {
+		return fmt.Errorf("failed to get deployment of process %s: %w", process.Name, err)
+	}
+	podName := fmt.Sprintf("%s%s-%s", app.Name, isolatedRunContainerSuffix, utilrand.String(5))
+	pod, err := newIsolatedRunPod(app, deployment, podName, options.command)
+	if err != nil {
+		return err
+	}
+
+	// the pod has to be removed if the command is interrupted.
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	logOptions := watchOptions{
+		namespace:     namespace,
+		prefix:        options.prefix,
+		timestamps:    options.timestamps,
+		out:           out,
+		containerName: pod.Spec.Containers[0].Name,
+	}
+	exitCode, err := runPod(ctx, cfg.KubernetesClient(), pod, options.startTimeout, logOptions)
+	if err != nil {
+		return err
+	}
+	if exitCode != 0 {
+		return exitCodeError{code: exitCode, message: fmt.Sprintf("command exited with code %d", exitCode)}
+	}
+	return nil
+}
+
+// appProcess returns a deployment and a process of the app.
+// By default, it's the first process of the latest deployment.
+func appProcess(app ketchv1.App, processName string, deploymentVersion int) (*ketchv1.AppDeploymentSpec, *ketchv1.ProcessSpec, error) {
+	if len(app.Spec.Deployments) == 0 {
+		return nil, nil, fmt.Errorf("app %s has no deployments", app.Name)
+	}
+	deployment := &app.Spec.Deployments[len(app.Spec.Deployments)-1]
+	if deploymentVersion > 0 {
+		deployment = nil
+		for i := range app.Spec.Deployments {
+			if int(app.Spec.Deployments[i].Version) == deploymentVersion {
+				deployment = &app.Spec.Deployments[i]
+			}
+		}
+		if deployment == nil {
+			return nil, nil, fmt.Errorf("deployment version %d is not found", deploymentVersion)
+		}
+	}
+	if len(deployment.Processes) == 0 {
+		return nil, nil, fmt.Errorf("deployment version %d has no processes", deployment.Version)
+	}
+	if len(processName) == 0 {
+		return deployment, &deployment.Processes[0], nil
+	}
+	for i := range deployment.Processes {
+		if deployment.Processes[i].Name == processName {
+			return deployment, &deployment.Processes[i], nil
+		}
+	}
+	return nil, nil, fmt.Errorf("process %s is not found", processName)
+}
+
+// newIsolatedRunPod returns a pod running the command with the pod spec of the deployment.
+// The pod isn't selected by the app's services and it isn't counted as a unit of the process.
+func newIsolatedRunPod(app ketchv1.App, deployment appsv1.Deployment, name string, command []string) (*corev1.Pod, error) {
+	template := deployment.Spec.Template.DeepCopy()
+	var container *corev1.Container
+	for i := range template.Spec.Containers {
+		if template.Spec.Containers[i].Name == deployment.Name {
+			container = &template.Spec.Containers[i]
+		}
+	}
+	if container == nil {
+		return nil, fmt.Errorf("deployment %s doesn't have an app container", deployment.Name)
+	}
+	container.Name = app.Name + isolatedRunContainerSuffix
+	container.Command = command
+	container.Args = nil
+	container.Ports = nil
+	container.LivenessProbe = nil
+	container.ReadinessProbe = nil
+	container.StartupProbe = nil
+	container.Lifecycle = nil
+	template.Spec.Containers = []corev1.Container{*container}
+	template.Spec.RestartPolicy = corev1.RestartPolicyNever
+
+	labels := template.Labels
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	delete(labels, utils.KetchProcessNameLabel)
+	labels[utils.KetchIsolatedRunLabel] = "true"
+	annotations := template.Annotations
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	// a sidecar keeps running after the command exits, so the pod would never complete.
+	annotations["sidecar.istio.io/inject"] = "false"
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   deployment.Namespace,
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		Spec: template.Spec,
+	}, nil
+}
+
+// runPod creates the pod, streams the output of its container and returns the exit code of the container.
+// The output is formatted the same way as "ketch app log" does. The pod is removed once the container exits.
+func runPod(ctx context.Context, cli kubernetes.Interface, pod *corev1.Pod, startTimeout time.Duration, logOptions watchOptions) (int, error) {
+	pods := cli.CoreV1().Pods(pod.Namespace)
+	if _, err := pods.Create(ctx, pod, metav1.CreateOptions{}); err != nil {
+		return 0, fmt.Errorf("failed to create pod: %w", err)
+	}
+	defer pods.Delete(context.Background(), pod.Name, metav1.DeleteOptions{})
+
+	containerName := logOptions.containerName
+	startCtx, cancel := context.WithTimeout(ctx, startTimeout)
+	defer cancel()
+	started, err := waitForPod(startCtx, pods, pod.Name, func(pod *corev1.Pod) (bool, error) {
+		state := containerState(pod, containerName)
+		if state.Waiting != nil && isContainerStartFailure(state.Waiting.Reason) {
+			return false, fmt.Errorf("failed to start container: %s: %s", state.Waiting.Reason, state.Waiting.Message)
+		}
+		return state.Running != nil || state.Terminated != nil, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	podLogOptions := logOptions.podLogOptions(containerName)
+	// following the logs of a single container, readLogs closes the channel once the container exits.
+	podLogOptions.Follow = true
+	msgCh := readLogs(pods.GetLogs, *started, podLogOptions, logOptions.out)
+	for reading := true; reading; {
+		select {
+		case <-ctx.Done():
+			return 0, fmt.Errorf("failed to read logs from pod %v: %w", pod.Name, ctx.Err())
+		case m, ok := <-msgCh:
+			if !ok {
+				reading = false
+				continue
+			}
+			logOptions.write(m)
+		}
+	}
+
+	finished, err := waitForPod(ctx, pods, pod.Name, func(pod *corev1.Pod) (bool, error) {
+		return containerState(pod, containerName).Terminated != nil, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int(containerState(finished, containerName).Terminated.ExitCode), nil
+}
+
+// waitForPod watches the pod until the condition is met.
+func waitForPod(ctx context.Context, pods corev1client.PodInterface, name string, condition func(pod *corev1.Pod) (bool, error)) (*corev1.Pod, error) {
+	watcher, err := pods.Watch(ctx, metav1.ListOptions{FieldSelector: fields.OneTermEqualSelector("metadata.name", name).String()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch pod %s: %w", name, err)
+	}
+	defer watcher.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to wait for pod %s: %w", name, ctx.Err())
+		case e, ok := <-watcher.ResultChan():
+			if !ok {
+				return nil, fmt.Errorf("failed to wait for pod %s: watch closed", name)
+			}
+			pod, ok := e.Object.(*corev1.Pod)
+			if !ok {
+				continue
+			}
+			done, err := condition(pod)
+			if err != nil {
+				return nil, err
+			}
+			if done {
+				return pod, nil
+			}
+		}
+	}
+}
+
+func containerState(pod *corev1.Pod, containerName string) corev1.ContainerState {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == containerName {
+			return status.State
+		}
+	}
+	return corev1.ContainerState{}
+}
+
+// isContainerStartFailure returns true if a container waiting for the reason won't start without user's actions.
+func isContainerStartFailure(reason string) bool {
+	switch reason {
+	case "ErrImagePull", "ImagePullBackOff", "InvalidImageName", "CreateContainerConfigError", "CreateContainerError":
+		return true
+	}
+	return false
+}
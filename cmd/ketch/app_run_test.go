@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/mocks"
+	"github.com/theketchio/ketch/internal/utils"
+)
+
+func TestAppRun(t *testing.T) {
+	dashboard := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+		Spec: ketchv1.AppSpec{
+			Framework: "gke",
+			Deployments: []ketchv1.AppDeploymentSpec{
+				{Version: 1, Image: "shipasoftware/go-app:v1", Processes: []ketchv1.ProcessSpec{{Name: "web"}}},
+				{Version: 2, Image: "shipasoftware/go-app:v2", Processes: []ketchv1.ProcessSpec{{Name: "web"}, {Name: "worker"}}},
+			},
+		},
+	}
+	gke := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "gke"},
+		Spec:       ketchv1.FrameworkSpec{NamespaceName: "ketch-gke"},
+	}
+	deployment := func(process string, version string) *appsv1.Deployment {
+		name := "dashboard-" + process + "-" + version
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ketch-gke"},
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{
+							utils.KetchAppNameLabel:     "dashboard",
+							utils.KetchProcessNameLabel: process,
+							utils.KetchIsolatedRunLabel: "false",
+						},
+					},
+					Spec: corev1.PodSpec{
+						ServiceAccountName: "dashboard-sa",
+						ImagePullSecrets:   []corev1.LocalObjectReference{{Name: "registry"}},
+						Containers: []corev1.Container{
+							{
+								Name:           name,
+								Image:          "shipasoftware/go-app:v" + version,
+								Command:        []string{"./app"},
+								Env:            []corev1.EnvVar{{Name: "DATABASE_URL", Value: "postgres://db"}},
+								Ports:          []corev1.ContainerPort{{ContainerPort: 8080}},
+								ReadinessProbe: &corev1.Probe{},
+							},
+							{Name: "vault-agent", Image: "vault"},
+						},
+					},
+				},
+			},
+		}
+	}
+	tests := []struct {
+		name          string
+		options       appRunOptions
+		exitCode      int
+		runErr        error
+		wantImage     string
+		wantErr       string
+		wantErrorCode int
+	}{
+		{
+			name:      "latest deployment",
+			options:   appRunOptions{appName: "dashboard", command: []string{"rake", "db:migrate"}},
+			wantImage: "shipasoftware/go-app:v2",
+		},
+		{
+			name:      "prefix + timestamps",
+			options:   appRunOptions{appName: "dashboard", command: []string{"rake", "db:migrate"}, prefix: true, timestamps: true},
+			wantImage: "shipasoftware/go-app:v2",
+		},
+		{
+			name:      "process of a deployment version",
+			options:   appRunOptions{appName: "dashboard", processName: "web", deploymentVersion: 1, command: []string{"rake", "db:migrate"}},
+			wantImage: "shipasoftware/go-app:v1",
+		},
+		{
+			name:          "command fails",
+			options:       appRunOptions{appName: "dashboard", command: []string{"rake", "db:migrate"}},
+			exitCode:      3,
+			wantErr:       "command exited with code 3",
+			wantErrorCode: 3,
+		},
+		{
+			name:    "pod fails to start",
+			options: appRunOptions{appName: "dashboard", command: []string{"rake", "db:migrate"}},
+			runErr:  errors.New("failed to start container: ErrImagePull: not found"),
+			wantErr: "failed to start container: ErrImagePull: not found",
+		},
+		{
+			name:    "unknown process",
+			options: appRunOptions{appName: "dashboard", processName: "cron", command: []string{"date"}},
+			wantErr: "process cron is not found",
+		},
+		{
+			name:    "deployment not installed",
+			options: appRunOptions{appName: "dashboard", processName: "worker", command: []string{"date"}},
+			wantErr: `failed to get deployment of process worker: deployments.apps "dashboard-worker-2" not found`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{dashboard, gke, deployment("web", "1"), deployment("web", "2")},
+			}
+			var got *corev1.Pod
+			var gotLogOptions watchOptions
+			runPod := func(ctx context.Context, cli kubernetes.Interface, pod *corev1.Pod, startTimeout time.Duration, logOptions watchOptions) (int, error) {
+				got = pod
+				gotLogOptions = logOptions
+				return tt.exitCode, tt.runErr
+			}
+			err := appRun(context.Background(), cfg, tt.options, &bytes.Buffer{}, runPod)
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				if tt.wantErrorCode > 0 {
+					var exitErr exitCodeError
+					require.True(t, errors.As(err, &exitErr))
+					require.Equal(t, tt.wantErrorCode, exitErr.code)
+				}
+				return
+			}
+			require.Nil(t, err)
+			require.NotNil(t, got)
+			require.Equal(t, "ketch-gke", got.Namespace)
+			require.Regexp(t, "^dashboard-run-[a-z0-9]{5}$", got.Name)
+			require.Equal(t, map[string]string{
+				utils.KetchAppNameLabel:     "dashboard",
+				utils.KetchIsolatedRunLabel: "true",
+			}, got.Labels)
+			require.Equal(t, "false", got.Annotations["sidecar.istio.io/inject"])
+			require.Equal(t, corev1.RestartPolicyNever, got.Spec.RestartPolicy)
+			require.Equal(t, "dashboard-sa", got.Spec.ServiceAccountName)
+			require.Equal(t, []corev1.LocalObjectReference{{Name: "registry"}}, got.Spec.ImagePullSecrets)
+			require.Equal(t, []corev1.Container{
+				{
+					Name:    "dashboard-run",
+					Image:   tt.wantImage,
+					Command: tt.options.command,
+					Env:     []corev1.EnvVar{{Name: "DATABASE_URL", Value: "postgres://db"}},
+				},
+			}, got.Spec.Containers)
+			gotLogOptions.out = nil
+			require.Equal(t, watchOptions{
+				namespace:     "ketch-gke",
+				prefix:        tt.options.prefix,
+				timestamps:    tt.options.timestamps,
+				containerName: "dashboard-run",
+			}, gotLogOptions)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/validation"
+)
+
+const appWakeHelp = `
+Wake up an application which was put to sleep because of inactivity.
+The processes of the application get the units they had before it was put to sleep.
+`
+
+type appWakeFn func(context.Context, config, appWakeOptions, io.Writer) error
+
+func newAppWakeCmd(cfg config, out io.Writer, appWake appWakeFn) *cobra.Command {
+	options := appWakeOptions{}
+	cmd := &cobra.Command{
+		Use:   "wake APPNAME",
+		Short: "Wake up an application which was put to sleep because of inactivity.",
+		Args:  cobra.ExactValidArgs(1),
+		Long:  appWakeHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.appName = args[0]
+			if !validation.ValidateName(options.appName) {
+				return ErrInvalidAppName
+			}
+			return appWake(cmd.Context(), cfg, options, out)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return autoCompleteAppNames(cfg, toComplete)
+		},
+	}
+	return cmd
+}
+
+type appWakeOptions struct {
+	appName string
+}
+
+func appWake(ctx context.Context, cfg config, options appWakeOptions, out io.Writer) error {
+	app := ketchv1.App{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.appName}, &app); err != nil {
+		return fmt.Errorf("failed to get app: %w", err)
+	}
+	if err := app.Wake(metav1.NewTime(time.Now())); err != nil {
+		return fmt.Errorf("failed to wake app: %w", err)
+	}
+	if err := cfg.Client().Update(ctx, &app); err != nil {
+		return fmt.Errorf("failed to update app: %w", err)
+	}
+	fmt.Fprintln(out, "Successfully woken up!")
+	return nil
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/mocks"
+	"github.com/theketchio/ketch/internal/utils/conversions"
+)
+
+func sleepingApp() *ketchv1.App {
+	return &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+		Spec: ketchv1.AppSpec{
+			Framework: "myframework",
+			IdlePolicy: &ketchv1.IdlePolicySpec{
+				IdleMinutes:   30,
+				PrometheusURL: "http://prometheus",
+				Asleep:        true,
+				Units:         []ketchv1.ProcessUnits{{Version: 1, Process: "web", Units: conversions.IntPtr(3)}},
+			},
+			Deployments: []ketchv1.AppDeploymentSpec{
+				{Version: 1, Processes: []ketchv1.ProcessSpec{{Name: "web", Units: conversions.IntPtr(0)}}},
+			},
+		},
+	}
+}
+
+func TestAppWake(t *testing.T) {
+	tests := []struct {
+		name    string
+		app     *ketchv1.App
+		wantErr string
+	}{
+		{
+			name: "sleeping app",
+			app:  sleepingApp(),
+		},
+		{
+			name: "awake app",
+			app: func() *ketchv1.App {
+				app := sleepingApp()
+				app.Spec.IdlePolicy.Asleep = false
+				return app
+			}(),
+			wantErr: "failed to wake app: app is not asleep",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &mocks.Configuration{CtrlClientObjects: []runtime.Object{tt.app}}
+			out := &bytes.Buffer{}
+			err := appWake(context.Background(), cfg, appWakeOptions{appName: "dashboard"}, out)
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, "Successfully woken up!\n", out.String())
+
+			app := ketchv1.App{}
+			require.Nil(t, cfg.Client().Get(context.Background(), types.NamespacedName{Name: "dashboard"}, &app))
+			require.False(t, app.Spec.IdlePolicy.Asleep)
+			require.Equal(t, conversions.IntPtr(3), app.Spec.Deployments[0].Processes[0].Units)
+		})
+	}
+}
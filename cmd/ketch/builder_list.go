@@ -31,6 +31,11 @@ func (b BuilderList) Names(filter ...string) []string {
 	return builderNames
 }
 
+// builderListObjects is a list of builders for the custom-columns and go-template output formats.
+type builderListObjects struct {
+	Items BuilderList `json:"items"`
+}
+
 var builderList = BuilderList{
 	{
 		Vendor:      "Google",
@@ -65,14 +70,17 @@ var builderList = BuilderList{
 }
 
 func newBuilderListCmd(ketchConfig configuration.KetchConfig, out io.Writer) *cobra.Command {
+	var outputFlag string
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "list builders",
 		Long:  builderListHelp,
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return output.Write(append(builderList, ketchConfig.AdditionalBuilders...), out, "column")
+			builders := append(builderList, ketchConfig.AdditionalBuilders...)
+			return output.WriteWithObjects(builders, builderListObjects{Items: builders}, out, outputFlag)
 		},
 	}
+	cmd.Flags().StringVarP(&outputFlag, "output", "o", "", output.Usage)
 	return cmd
 }
@@ -35,6 +35,7 @@ func TestBuilderList(t *testing.T) {
 	tests := []struct {
 		name        string
 		ketchConfig configuration.KetchConfig
+		args        []string
 		expected    string
 	}{
 		{
@@ -57,12 +58,20 @@ func TestBuilderList(t *testing.T) {
 			},
 			expected: userBuilders,
 		},
+		{
+			name: "name output",
+			ketchConfig: configuration.KetchConfig{
+				AdditionalBuilders: nil,
+			},
+			args:     []string{"-o", "name"},
+			expected: "gcr.io/buildpacks/builder:v1\nheroku/buildpacks:18\nheroku/buildpacks:20\npaketobuildpacks/builder:base\npaketobuildpacks/builder:full\npaketobuildpacks/builder:tiny\n",
+		},
 	}
 
 	for _, tt := range tests {
 		var buff bytes.Buffer
 		cmd := newBuilderListCmd(tt.ketchConfig, &buff)
-		cmd.SetArgs([]string{})
+		cmd.SetArgs(append([]string{}, tt.args...))
 		err := cmd.Execute()
 		require.Nil(t, err)
 		require.Equal(t, tt.expected, buff.String())
@@ -11,6 +11,7 @@ import (
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -43,7 +44,7 @@ type KetchConfig struct {
 // AdditionalBuilder contains the information of any user added builders
 type AdditionalBuilder struct {
 	Vendor      string `toml:"vendor" json:"vendor" yaml:"vendor"`
-	Image       string `toml:"image" json:"image" yaml:"image"`
+	Image       string `toml:"image" json:"image" yaml:"image" output:"name"`
 	Description string `toml:"description" json:"description" yaml:"description"`
 }
 
@@ -80,6 +81,17 @@ func (cfg *Configuration) KubernetesClient() kubernetes.Interface {
 	return clientset
 }
 
+// RestConfig returns a config of kubernetes clients. It's used to execute commands in pods.
+func (cfg *Configuration) RestConfig() *rest.Config {
+	configFlags := genericclioptions.NewConfigFlags(true)
+	factory := cmdutil.NewFactory(configFlags)
+	kubeCfg, err := factory.ToRESTConfig()
+	if err != nil {
+		log.Fatalf("failed to create kubernetes client: %v", err)
+	}
+	return kubeCfg
+}
+
 // Client returns initialized templates.Client to perform CRUD operations on templates.
 func (cfg *Configuration) Storage() templates.Client {
 	if cfg.storage != nil {
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/utils"
+)
+
+const envExportHelp = `
+Export environment variables of an application.
+Variables stored in secrets and config map references are not exported.
+
+  ketch env export APPNAME > .env
+  ketch env export APPNAME --format json
+`
+
+const (
+	envFormatDotenv = "dotenv"
+	envFormatJSON   = "json"
+)
+
+type envExportFn func(ctx context.Context, cfg config, options envExportOptions, out io.Writer) error
+
+func newEnvExportCmd(cfg config, out io.Writer, envExport envExportFn) *cobra.Command {
+	options := envExportOptions{}
+	cmd := &cobra.Command{
+		Use:   "export APPNAME",
+		Short: "Export environment variables of an application.",
+		Long:  envExportHelp,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.appName = args[0]
+			if options.format != envFormatDotenv && options.format != envFormatJSON {
+				return fmt.Errorf("unsupported format %q, use one of: %s|%s", options.format, envFormatDotenv, envFormatJSON)
+			}
+			return envExport(cmd.Context(), cfg, options, out)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return autoCompleteAppNames(cfg, toComplete)
+		},
+	}
+	cmd.Flags().StringVar(&options.format, "format", envFormatDotenv, "Output format. One of: dotenv|json")
+	return cmd
+}
+
+type envExportOptions struct {
+	appName string
+	format  string
+}
+
+func envExport(ctx context.Context, cfg config, options envExportOptions, out io.Writer) error {
+	app := ketchv1.App{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.appName}, &app); err != nil {
+		return fmt.Errorf("failed to get the app: %w", err)
+	}
+	if options.format == envFormatDotenv {
+		return utils.FormatDotenv(out, app.Spec.Env)
+	}
+	envs := make(map[string]string, len(app.Spec.Env))
+	for _, env := range app.Spec.Env {
+		if env.ValueFrom == nil {
+			envs[env.Name] = env.Value
+		}
+	}
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(envs)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/mocks"
+)
+
+func TestEnvExport(t *testing.T) {
+	objects := envTestObjects()
+	app := objects[0].(*ketchv1.App)
+	app.Spec.Env = append(app.Spec.Env,
+		ketchv1.Env{Name: "GREETING", Value: "hello world"},
+		ketchv1.Env{
+			Name:      "DB_PASSWORD",
+			ValueFrom: &ketchv1.EnvVarSource{SecretKeyRef: &ketchv1.SecretKeySelector{Name: "db", Key: "password"}},
+		},
+	)
+
+	tests := []struct {
+		name    string
+		options envExportOptions
+		want    string
+	}{
+		{
+			name:    "dotenv",
+			options: envExportOptions{appName: "dashboard", format: envFormatDotenv},
+			want:    "GREETING=\"hello world\"\nPORT=8080\n",
+		},
+		{
+			name:    "json",
+			options: envExportOptions{appName: "dashboard", format: envFormatJSON},
+			want:    "{\n  \"GREETING\": \"hello world\",\n  \"PORT\": \"8080\"\n}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &mocks.Configuration{CtrlClientObjects: objects}
+			out := &bytes.Buffer{}
+			err := envExport(context.Background(), cfg, tt.options, out)
+			require.Nil(t, err)
+			require.Equal(t, tt.want, out.String())
+		})
+	}
+}
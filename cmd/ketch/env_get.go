@@ -6,6 +6,7 @@ import (
 	"io"
 
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/theketchio/ketch/cmd/ketch/output"
@@ -14,10 +15,13 @@ import (
 
 const envGetHelp = `
 Retrieve environment variables for an application.
+Values stored in secrets are masked unless --reveal is given.
 
-ketch env-get [-a/--app appname] [ENVIRONMENT_VARIABLE1] [ENVIRONMENT_VARIABLE2] ...
+ketch env-get [-a/--app appname] [--reveal] [ENVIRONMENT_VARIABLE1] [ENVIRONMENT_VARIABLE2] ...
 `
 
+const maskedEnvValue = "*****"
+
 func newEnvGetCmd(cfg config, out io.Writer) *cobra.Command {
 	options := envGetOptions{}
 	cmd := &cobra.Command{
@@ -31,6 +35,7 @@ func newEnvGetCmd(cfg config, out io.Writer) *cobra.Command {
 		},
 	}
 	cmd.Flags().StringVarP(&options.appName, "app", "a", "", "The name of the app.")
+	cmd.Flags().BoolVar(&options.reveal, "reveal", false, "Show values of environment variables stored in secrets.")
 	cmd.MarkFlagRequired("app")
 	return cmd
 }
@@ -38,6 +43,7 @@ func newEnvGetCmd(cfg config, out io.Writer) *cobra.Command {
 type envGetOptions struct {
 	appName string
 	envs    []string
+	reveal  bool
 }
 
 func envGet(ctx context.Context, cfg config, options envGetOptions, out io.Writer) error {
@@ -45,5 +51,28 @@ func envGet(ctx context.Context, cfg config, options envGetOptions, out io.Write
 	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.appName}, &app); err != nil {
 		return fmt.Errorf("failed to get the app: %w", err)
 	}
-	return output.Write(app.Envs(options.envs), out, "column")
+	envs := app.Envs(options.envs)
+	var namespace string
+	for _, env := range app.Spec.Env {
+		if _, ok := envs[env.Name]; !ok || env.ValueFrom == nil || env.ValueFrom.SecretKeyRef == nil {
+			continue
+		}
+		if !options.reveal {
+			envs[env.Name] = maskedEnvValue
+			continue
+		}
+		if len(namespace) == 0 {
+			var err error
+			if namespace, err = appFrameworkNamespace(ctx, cfg, app); err != nil {
+				return err
+			}
+		}
+		ref := env.ValueFrom.SecretKeyRef
+		secret := corev1.Secret{}
+		if err := cfg.Client().Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, &secret); err != nil {
+			return fmt.Errorf("failed to get the secret: %w", err)
+		}
+		envs[env.Name] = string(secret.Data[ref.Key])
+	}
+	return output.Write(envs, out, "column")
 }
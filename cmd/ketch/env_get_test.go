@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/mocks"
+)
+
+func TestEnvGet(t *testing.T) {
+	objects := envTestObjects()
+	app := objects[0].(*ketchv1.App)
+	app.Spec.Env = append(app.Spec.Env, ketchv1.Env{
+		Name:      "DB_PASSWORD",
+		ValueFrom: &ketchv1.EnvVarSource{SecretKeyRef: &ketchv1.SecretKeySelector{Name: "db", Key: "password"}},
+	})
+
+	tests := []struct {
+		name    string
+		options envGetOptions
+		want    string
+	}{
+		{
+			name:    "secret values are masked",
+			options: envGetOptions{appName: "dashboard"},
+			want:    "DB_PASSWORD    PORT\n*****          8080\n",
+		},
+		{
+			name:    "secret values are revealed",
+			options: envGetOptions{appName: "dashboard", reveal: true},
+			want:    "DB_PASSWORD    PORT\nhunter2        8080\n",
+		},
+		{
+			name:    "selected variables",
+			options: envGetOptions{appName: "dashboard", envs: []string{"PORT"}, reveal: true},
+			want:    "PORT\n8080\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &mocks.Configuration{CtrlClientObjects: objects}
+			out := &bytes.Buffer{}
+			err := envGet(context.Background(), cfg, tt.options, out)
+			require.Nil(t, err)
+			require.Equal(t, tt.want, out.String())
+		})
+	}
+}
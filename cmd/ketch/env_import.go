@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/utils"
+)
+
+const envImportHelp = `
+Import environment variables for an application.
+
+Variables can be read from a dotenv file, quoted values can span multiple lines:
+  ketch env import APPNAME --file .env
+
+A config map in the framework's namespace can be referenced, every key of the config map
+becomes an environment variable of the app's containers:
+  ketch env import APPNAME --from-configmap CONFIGMAP
+`
+
+type envImportFn func(ctx context.Context, cfg config, options envImportOptions, out io.Writer) error
+
+func newEnvImportCmd(cfg config, out io.Writer, envImport envImportFn) *cobra.Command {
+	options := envImportOptions{}
+	cmd := &cobra.Command{
+		Use:   "import APPNAME",
+		Short: "Import environment variables for an application.",
+		Long:  envImportHelp,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.appName = args[0]
+			if len(options.filename) == 0 && len(options.configMap) == 0 {
+				return fmt.Errorf("either --file or --from-configmap is required")
+			}
+			return envImport(cmd.Context(), cfg, options, out)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return autoCompleteAppNames(cfg, toComplete)
+		},
+	}
+	cmd.Flags().StringVarP(&options.filename, "file", "f", "", "Path to a dotenv file.")
+	cmd.Flags().StringVar(&options.configMap, "from-configmap", "", "Name of a config map in the framework's namespace.")
+	return cmd
+}
+
+type envImportOptions struct {
+	appName   string
+	filename  string
+	configMap string
+}
+
+func envImport(ctx context.Context, cfg config, options envImportOptions, out io.Writer) error {
+	var envs []ketchv1.Env
+	if len(options.filename) > 0 {
+		f, err := os.Open(options.filename)
+		if err != nil {
+			return fmt.Errorf("failed to open the file: %w", err)
+		}
+		defer f.Close()
+		if envs, err = utils.ParseDotenv(f); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", options.filename, err)
+		}
+	}
+	app := ketchv1.App{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.appName}, &app); err != nil {
+		return fmt.Errorf("failed to get the app: %w", err)
+	}
+	if len(options.configMap) > 0 {
+		namespace, err := appFrameworkNamespace(ctx, cfg, app)
+		if err != nil {
+			return err
+		}
+		configMap := corev1.ConfigMap{}
+		if err := cfg.Client().Get(ctx, types.NamespacedName{Namespace: namespace, Name: options.configMap}, &configMap); err != nil {
+			return fmt.Errorf("failed to get the config map: %w", err)
+		}
+		app.AddEnvFromConfigMap(options.configMap)
+	}
+	app.SetEnvs(envs)
+	if err := cfg.Client().Update(ctx, &app); err != nil {
+		return fmt.Errorf("failed to update the app: %w", err)
+	}
+	if len(envs) > 0 {
+		fmt.Fprintf(out, "Imported %d environment variables.\n", len(envs))
+	}
+	if len(options.configMap) > 0 {
+		fmt.Fprintf(out, "Environment variables are populated from the %q config map.\n", options.configMap)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/mocks"
+)
+
+func TestEnvImport(t *testing.T) {
+	dotenv := filepath.Join(t.TempDir(), ".env")
+	require.Nil(t, ioutil.WriteFile(dotenv, []byte("PORT=9090\nCERT=\"line 1\nline 2\"\n"), 0600))
+
+	tests := []struct {
+		name        string
+		options     envImportOptions
+		wantEnvs    []ketchv1.Env
+		wantEnvFrom []ketchv1.EnvFromSource
+		wantOut     string
+		wantErr     string
+	}{
+		{
+			name:    "dotenv file",
+			options: envImportOptions{appName: "dashboard", filename: dotenv},
+			wantEnvs: []ketchv1.Env{
+				{Name: "PORT", Value: "9090"},
+				{Name: "CERT", Value: "line 1\nline 2"},
+			},
+			wantOut: "Imported 2 environment variables.\n",
+		},
+		{
+			name:        "config map",
+			options:     envImportOptions{appName: "dashboard", configMap: "dashboard-config"},
+			wantEnvs:    []ketchv1.Env{{Name: "PORT", Value: "8080"}},
+			wantEnvFrom: []ketchv1.EnvFromSource{{ConfigMapRef: &ketchv1.ConfigMapEnvSource{Name: "dashboard-config"}}},
+			wantOut:     "Environment variables are populated from the \"dashboard-config\" config map.\n",
+		},
+		{
+			name:    "missing config map",
+			options: envImportOptions{appName: "dashboard", configMap: "other"},
+			wantErr: `failed to get the config map: configmaps "other" not found`,
+		},
+		{
+			name:    "missing file",
+			options: envImportOptions{appName: "dashboard", filename: filepath.Join(t.TempDir(), "missing")},
+			wantErr: "failed to open the file",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects := append(envTestObjects(), &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "dashboard-config", Namespace: "ketch-myframework"},
+				Data:       map[string]string{"LOG_LEVEL": "info"},
+			})
+			cfg := &mocks.Configuration{CtrlClientObjects: objects}
+			out := &bytes.Buffer{}
+			err := envImport(context.Background(), cfg, tt.options, out)
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.wantOut, out.String())
+
+			app := ketchv1.App{}
+			require.Nil(t, cfg.Client().Get(context.Background(), types.NamespacedName{Name: "dashboard"}, &app))
+			require.Equal(t, tt.wantEnvs, app.Spec.Env)
+			require.Equal(t, tt.wantEnvFrom, app.Spec.EnvFrom)
+		})
+	}
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"log"
+	"strings"
 
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 
 	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
@@ -16,6 +18,16 @@ import (
 
 const envSetHelp = `
 Set environment variables for an application.
+
+ketch env set -a APPNAME KEY=VALUE ...
+ketch env set -a APPNAME --secret KEY=VALUE ...
+ketch env set -a APPNAME --from-secret SECRET/KEY NAME
+ketch env set -a APPNAME --process worker KEY=VALUE ...
+
+With --secret, values are stored in the "<APPNAME>-env" secret in the framework's namespace
+and the app only references them.
+With --from-secret, the variable references a key of an existing secret in the framework's namespace.
+With --process, variables are set for the process only and take precedence over the app's variables.
 `
 
 func newEnvSetCmd(cfg config, out io.Writer) *cobra.Command {
@@ -32,6 +44,9 @@ func newEnvSetCmd(cfg config, out io.Writer) *cobra.Command {
 		},
 	}
 	cmd.Flags().StringVarP(&options.appName, deploy.FlagApp, deploy.FlagAppShort, "", "The name of the app.")
+	cmd.Flags().BoolVar(&options.secret, "secret", false, "Store values in a secret in the framework's namespace.")
+	cmd.Flags().StringVarP(&options.processName, "process", "p", "", "Set the environment variables for the process only.")
+	cmd.Flags().StringVar(&options.fromSecret, "from-secret", "", "Reference a key of an existing secret in the framework's namespace, in the format SECRET/KEY.")
 	cmd.MarkFlagRequired(deploy.FlagApp)
 	cmd.RegisterFlagCompletionFunc(deploy.FlagApp, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return autoCompleteAppNames(cfg, toComplete)
@@ -40,18 +55,42 @@ func newEnvSetCmd(cfg config, out io.Writer) *cobra.Command {
 }
 
 type envSetOptions struct {
-	appName string
-	envs    []string
+	appName     string
+	envs        []string
+	secret      bool
+	fromSecret  string
+	processName string
 }
 
 func envSet(ctx context.Context, cfg config, options envSetOptions, out io.Writer) error {
-	envs, err := utils.MakeEnvironments(options.envs)
-	if err != nil {
-		return fmt.Errorf("failed to get kubernetes client: %w", err)
+	if options.secret && len(options.fromSecret) > 0 {
+		return fmt.Errorf("--secret and --from-secret can't be used together")
 	}
 	app := ketchv1.App{}
-	if err = cfg.Client().Get(ctx, types.NamespacedName{Name: options.appName}, &app); err != nil {
-		log.Fatalf("failed to get the app: %v", err)
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.appName}, &app); err != nil {
+		return fmt.Errorf("failed to get the app: %w", err)
+	}
+
+	var envs []ketchv1.Env
+	var err error
+	switch {
+	case len(options.fromSecret) > 0:
+		envs, err = envsFromSecret(ctx, cfg, app, options)
+	case options.secret:
+		envs, err = envsToSecret(ctx, cfg, app, options)
+	default:
+		envs, err = utils.MakeEnvironments(options.envs)
+	}
+	if err != nil {
+		return err
+	}
+	if len(options.processName) > 0 {
+		changeSet := deploy.ProcessOptions{AppName: options.appName, Process: options.processName, Envs: envs}.GetChangeSet()
+		svc := &deploy.Services{Client: cfg.Client(), Writer: out}
+		if err := deploy.New(changeSet).UpdateProcesses(ctx, svc); err != nil {
+			return fmt.Errorf("failed to set environment variables of the process: %w", err)
+		}
+		return nil
 	}
 	app.SetEnvs(envs)
 	if err := cfg.Client().Update(ctx, &app); err != nil {
@@ -59,3 +98,90 @@ func envSet(ctx context.Context, cfg config, options envSetOptions, out io.Write
 	}
 	return nil
 }
+
+func appFrameworkNamespace(ctx context.Context, cfg config, app ketchv1.App) (string, error) {
+	framework := ketchv1.Framework{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: app.Spec.Framework}, &framework); err != nil {
+		return "", fmt.Errorf("failed to get the framework: %w", err)
+	}
+	return framework.Spec.NamespaceName, nil
+}
+
+// envsFromSecret returns an env variable referencing a key of an existing secret.
+func envsFromSecret(ctx context.Context, cfg config, app ketchv1.App, options envSetOptions) ([]ketchv1.Env, error) {
+	parts := strings.Split(options.fromSecret, "/")
+	if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		return nil, fmt.Errorf("invalid --from-secret %q, the format is SECRET/KEY", options.fromSecret)
+	}
+	if len(options.envs) != 1 || strings.Contains(options.envs[0], "=") {
+		return nil, fmt.Errorf("--from-secret requires exactly one environment variable name")
+	}
+	namespace, err := appFrameworkNamespace(ctx, cfg, app)
+	if err != nil {
+		return nil, err
+	}
+	secretName, key := parts[0], parts[1]
+	secret := corev1.Secret{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Namespace: namespace, Name: secretName}, &secret); err != nil {
+		return nil, fmt.Errorf("failed to get the secret: %w", err)
+	}
+	if _, ok := secret.Data[key]; !ok {
+		return nil, fmt.Errorf("secret %q has no key %q", secretName, key)
+	}
+	return []ketchv1.Env{
+		{
+			Name: options.envs[0],
+			ValueFrom: &ketchv1.EnvVarSource{
+				SecretKeyRef: &ketchv1.SecretKeySelector{Name: secretName, Key: key},
+			},
+		},
+	}, nil
+}
+
+// envsToSecret stores values of the env variables in the app's env secret
+// and returns env variables referencing them.
+func envsToSecret(ctx context.Context, cfg config, app ketchv1.App, options envSetOptions) ([]ketchv1.Env, error) {
+	values, err := utils.MakeEnvironments(options.envs)
+	if err != nil {
+		return nil, err
+	}
+	namespace, err := appFrameworkNamespace(ctx, cfg, app)
+	if err != nil {
+		return nil, err
+	}
+	secretName := app.EnvSecretName()
+	secret := corev1.Secret{}
+	err = cfg.Client().Get(ctx, types.NamespacedName{Namespace: namespace, Name: secretName}, &secret)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get the secret: %w", err)
+	}
+	exists := err == nil
+	secret.Name = secretName
+	secret.Namespace = namespace
+	if secret.Labels == nil {
+		secret.Labels = map[string]string{}
+	}
+	secret.Labels[utils.KetchAppNameLabel] = app.Name
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	envs := make([]ketchv1.Env, 0, len(values))
+	for _, value := range values {
+		secret.Data[value.Name] = []byte(value.Value)
+		envs = append(envs, ketchv1.Env{
+			Name: value.Name,
+			ValueFrom: &ketchv1.EnvVarSource{
+				SecretKeyRef: &ketchv1.SecretKeySelector{Name: secretName, Key: value.Name},
+			},
+		})
+	}
+	if exists {
+		err = cfg.Client().Update(ctx, &secret)
+	} else {
+		err = cfg.Client().Create(ctx, &secret)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to save the secret: %w", err)
+	}
+	return envs, nil
+}
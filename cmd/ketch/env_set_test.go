@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/mocks"
+	"github.com/theketchio/ketch/internal/utils"
+)
+
+func envTestObjects() []runtime.Object {
+	return []runtime.Object{
+		&ketchv1.App{
+			ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+			Spec: ketchv1.AppSpec{
+				Framework: "myframework",
+				Env:       []ketchv1.Env{{Name: "PORT", Value: "8080"}},
+				Deployments: []ketchv1.AppDeploymentSpec{
+					{
+						Version:   1,
+						Processes: []ketchv1.ProcessSpec{{Name: "web"}, {Name: "worker"}},
+					},
+				},
+			},
+		},
+		&ketchv1.Framework{
+			ObjectMeta: metav1.ObjectMeta{Name: "myframework"},
+			Spec:       ketchv1.FrameworkSpec{NamespaceName: "ketch-myframework"},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "ketch-myframework"},
+			Data:       map[string][]byte{"password": []byte("hunter2")},
+		},
+	}
+}
+
+func TestEnvSet(t *testing.T) {
+	tests := []struct {
+		name       string
+		options    envSetOptions
+		wantEnvs   []ketchv1.Env
+		wantWorker []ketchv1.Env
+		wantSecret map[string][]byte
+		wantErr    string
+	}{
+		{
+			name:    "plain values",
+			options: envSetOptions{appName: "dashboard", envs: []string{"DEBUG=true"}},
+			wantEnvs: []ketchv1.Env{
+				{Name: "PORT", Value: "8080"},
+				{Name: "DEBUG", Value: "true"},
+			},
+		},
+		{
+			name:    "values stored in a secret",
+			options: envSetOptions{appName: "dashboard", envs: []string{"TOKEN=abc"}, secret: true},
+			wantEnvs: []ketchv1.Env{
+				{Name: "PORT", Value: "8080"},
+				{Name: "TOKEN", ValueFrom: &ketchv1.EnvVarSource{SecretKeyRef: &ketchv1.SecretKeySelector{Name: "dashboard-env", Key: "TOKEN"}}},
+			},
+			wantSecret: map[string][]byte{"TOKEN": []byte("abc")},
+		},
+		{
+			name:    "reference to an existing secret",
+			options: envSetOptions{appName: "dashboard", envs: []string{"DB_PASSWORD"}, fromSecret: "db/password"},
+			wantEnvs: []ketchv1.Env{
+				{Name: "PORT", Value: "8080"},
+				{Name: "DB_PASSWORD", ValueFrom: &ketchv1.EnvVarSource{SecretKeyRef: &ketchv1.SecretKeySelector{Name: "db", Key: "password"}}},
+			},
+		},
+		{
+			name:       "process values",
+			options:    envSetOptions{appName: "dashboard", envs: []string{"QUEUE=jobs"}, processName: "worker"},
+			wantEnvs:   []ketchv1.Env{{Name: "PORT", Value: "8080"}},
+			wantWorker: []ketchv1.Env{{Name: "QUEUE", Value: "jobs"}},
+		},
+		{
+			name:    "unknown process",
+			options: envSetOptions{appName: "dashboard", envs: []string{"QUEUE=jobs"}, processName: "cron"},
+			wantErr: "failed to set environment variables of the process: process not found",
+		},
+		{
+			name:    "missing secret key",
+			options: envSetOptions{appName: "dashboard", envs: []string{"DB_USER"}, fromSecret: "db/user"},
+			wantErr: `secret "db" has no key "user"`,
+		},
+		{
+			name:    "invalid secret reference",
+			options: envSetOptions{appName: "dashboard", envs: []string{"DB_USER"}, fromSecret: "db"},
+			wantErr: `invalid --from-secret "db", the format is SECRET/KEY`,
+		},
+		{
+			name:    "value with a secret reference",
+			options: envSetOptions{appName: "dashboard", envs: []string{"DB_USER=admin"}, fromSecret: "db/password"},
+			wantErr: "--from-secret requires exactly one environment variable name",
+		},
+		{
+			name:    "both secret flags",
+			options: envSetOptions{appName: "dashboard", envs: []string{"DB_USER"}, fromSecret: "db/password", secret: true},
+			wantErr: "--secret and --from-secret can't be used together",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &mocks.Configuration{CtrlClientObjects: envTestObjects()}
+			err := envSet(context.Background(), cfg, tt.options, &bytes.Buffer{})
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.Nil(t, err)
+
+			app := ketchv1.App{}
+			require.Nil(t, cfg.Client().Get(context.Background(), types.NamespacedName{Name: "dashboard"}, &app))
+			require.Equal(t, tt.wantEnvs, app.Spec.Env)
+			require.Equal(t, tt.wantWorker, app.Spec.Deployments[0].Processes[1].Env)
+
+			if tt.wantSecret != nil {
+				secret := corev1.Secret{}
+				require.Nil(t, cfg.Client().Get(context.Background(), types.NamespacedName{Namespace: "ketch-myframework", Name: "dashboard-env"}, &secret))
+				require.Equal(t, tt.wantSecret, secret.Data)
+				require.Equal(t, "dashboard", secret.Labels[utils.KetchAppNameLabel])
+			}
+		})
+	}
+}
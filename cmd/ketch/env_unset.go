@@ -6,6 +6,8 @@ import (
 	"io"
 
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 
 	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
@@ -16,6 +18,9 @@ const envUnsetHelp = `
 Unset environment variables for an application.
 
 ketch env-unset <ENVIRONMENT_VARIABLE1> [ENVIRONMENT_VARIABLE2] ... [ENVIRONMENT_VARIABLEN]
+
+Values of variables set with --secret are removed from the "<APPNAME>-env" secret
+once no variable of the app references them.
 `
 
 func newEnvUnsetCmd(cfg config, out io.Writer) *cobra.Command {
@@ -32,6 +37,7 @@ func newEnvUnsetCmd(cfg config, out io.Writer) *cobra.Command {
 		},
 	}
 	cmd.Flags().StringVarP(&options.appName, deploy.FlagApp, deploy.FlagAppShort, "", "The name of the app.")
+	cmd.Flags().StringVarP(&options.processName, "process", "p", "", "Unset the environment variables of the process only.")
 	cmd.MarkFlagRequired(deploy.FlagApp)
 	cmd.RegisterFlagCompletionFunc(deploy.FlagApp, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return autoCompleteAppNames(cfg, toComplete)
@@ -40,8 +46,9 @@ func newEnvUnsetCmd(cfg config, out io.Writer) *cobra.Command {
 }
 
 type envUnsetOptions struct {
-	appName string
-	envs    []string
+	appName     string
+	envs        []string
+	processName string
 }
 
 func envUnset(ctx context.Context, cfg config, options envUnsetOptions, out io.Writer) error {
@@ -49,9 +56,72 @@ func envUnset(ctx context.Context, cfg config, options envUnsetOptions, out io.W
 	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.appName}, &app); err != nil {
 		return fmt.Errorf("failed to get the app: %w", err)
 	}
-	app.UnsetEnvs(options.envs)
+	referencedKeys := envSecretKeys(app)
+	if len(options.processName) > 0 {
+		if err := app.UnsetProcessEnvs(ketchv1.NewSelector(0, options.processName), options.envs); err != nil {
+			return fmt.Errorf("failed to unset environment variables of the process: %w", err)
+		}
+	} else {
+		app.UnsetEnvs(options.envs)
+	}
 	if err := cfg.Client().Update(ctx, &app); err != nil {
 		return fmt.Errorf("failed to update the app: %w", err)
 	}
+	stillReferencedKeys := envSecretKeys(app)
+	var unusedKeys []string
+	for _, name := range options.envs {
+		if referencedKeys[name] && !stillReferencedKeys[name] {
+			unusedKeys = append(unusedKeys, name)
+		}
+	}
+	if len(unusedKeys) == 0 {
+		return nil
+	}
+	return removeEnvSecretKeys(ctx, cfg, app, unusedKeys)
+}
+
+// envSecretKeys returns keys of the app's env secret referenced by the app's and its processes' env variables.
+func envSecretKeys(app ketchv1.App) map[string]bool {
+	keys := map[string]bool{}
+	addKeys := func(envs []ketchv1.Env) {
+		for _, env := range envs {
+			if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil && env.ValueFrom.SecretKeyRef.Name == app.EnvSecretName() {
+				keys[env.ValueFrom.SecretKeyRef.Key] = true
+			}
+		}
+	}
+	addKeys(app.Spec.Env)
+	for _, deployment := range app.Spec.Deployments {
+		for _, process := range deployment.Processes {
+			addKeys(process.Env)
+		}
+	}
+	return keys
+}
+
+// removeEnvSecretKeys removes the keys from the app's env secret and deletes the secret once it's empty.
+func removeEnvSecretKeys(ctx context.Context, cfg config, app ketchv1.App, keys []string) error {
+	namespace, err := appFrameworkNamespace(ctx, cfg, app)
+	if err != nil {
+		return err
+	}
+	secret := corev1.Secret{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Namespace: namespace, Name: app.EnvSecretName()}, &secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get the secret: %w", err)
+	}
+	for _, key := range keys {
+		delete(secret.Data, key)
+	}
+	if len(secret.Data) == 0 {
+		err = cfg.Client().Delete(ctx, &secret)
+	} else {
+		err = cfg.Client().Update(ctx, &secret)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update the secret: %w", err)
+	}
 	return nil
 }
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/mocks"
+)
+
+func TestEnvUnset(t *testing.T) {
+	secretEnv := func(name string) ketchv1.Env {
+		return ketchv1.Env{Name: name, ValueFrom: &ketchv1.EnvVarSource{SecretKeyRef: &ketchv1.SecretKeySelector{Name: "dashboard-env", Key: name}}}
+	}
+	objects := func() []runtime.Object {
+		return []runtime.Object{
+			&ketchv1.App{
+				ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+				Spec: ketchv1.AppSpec{
+					Framework: "myframework",
+					Env:       []ketchv1.Env{{Name: "PORT", Value: "8080"}, secretEnv("TOKEN"), secretEnv("PASSWORD")},
+					Deployments: []ketchv1.AppDeploymentSpec{
+						{
+							Version:   1,
+							Processes: []ketchv1.ProcessSpec{{Name: "web"}, {Name: "worker", Env: []ketchv1.Env{secretEnv("TOKEN")}}},
+						},
+					},
+				},
+			},
+			&ketchv1.Framework{
+				ObjectMeta: metav1.ObjectMeta{Name: "myframework"},
+				Spec:       ketchv1.FrameworkSpec{NamespaceName: "ketch-myframework"},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "dashboard-env", Namespace: "ketch-myframework"},
+				Data:       map[string][]byte{"TOKEN": []byte("abc"), "PASSWORD": []byte("hunter2")},
+			},
+		}
+	}
+	tests := []struct {
+		name       string
+		unsetFirst *envUnsetOptions
+		options    envUnsetOptions
+		wantEnvs   []ketchv1.Env
+		wantSecret map[string][]byte
+	}{
+		{
+			name:       "plain value",
+			options:    envUnsetOptions{appName: "dashboard", envs: []string{"PORT"}},
+			wantEnvs:   []ketchv1.Env{secretEnv("TOKEN"), secretEnv("PASSWORD")},
+			wantSecret: map[string][]byte{"TOKEN": []byte("abc"), "PASSWORD": []byte("hunter2")},
+		},
+		{
+			name:       "value stored in the secret",
+			options:    envUnsetOptions{appName: "dashboard", envs: []string{"PASSWORD"}},
+			wantEnvs:   []ketchv1.Env{{Name: "PORT", Value: "8080"}, secretEnv("TOKEN")},
+			wantSecret: map[string][]byte{"TOKEN": []byte("abc")},
+		},
+		{
+			name:       "value still referenced by a process",
+			options:    envUnsetOptions{appName: "dashboard", envs: []string{"TOKEN"}},
+			wantEnvs:   []ketchv1.Env{{Name: "PORT", Value: "8080"}, secretEnv("PASSWORD")},
+			wantSecret: map[string][]byte{"TOKEN": []byte("abc"), "PASSWORD": []byte("hunter2")},
+		},
+		{
+			name:       "last values stored in the secret",
+			unsetFirst: &envUnsetOptions{appName: "dashboard", envs: []string{"TOKEN"}, processName: "worker"},
+			options:    envUnsetOptions{appName: "dashboard", envs: []string{"TOKEN", "PASSWORD"}},
+			wantEnvs:   []ketchv1.Env{{Name: "PORT", Value: "8080"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &mocks.Configuration{CtrlClientObjects: objects()}
+			if tt.unsetFirst != nil {
+				require.Nil(t, envUnset(context.Background(), cfg, *tt.unsetFirst, &bytes.Buffer{}))
+			}
+			require.Nil(t, envUnset(context.Background(), cfg, tt.options, &bytes.Buffer{}))
+
+			app := ketchv1.App{}
+			require.Nil(t, cfg.Client().Get(context.Background(), types.NamespacedName{Name: "dashboard"}, &app))
+			require.Equal(t, tt.wantEnvs, app.Spec.Env)
+
+			secret := corev1.Secret{}
+			err := cfg.Client().Get(context.Background(), types.NamespacedName{Namespace: "ketch-myframework", Name: "dashboard-env"}, &secret)
+			if tt.wantSecret == nil {
+				require.True(t, apierrors.IsNotFound(err))
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.wantSecret, secret.Data)
+		})
+	}
+}
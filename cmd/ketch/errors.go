@@ -24,8 +24,18 @@ const (
 	ErrClusterIssuerNotFound cliError = "cluster issuer not found"
 
 	ErrClusterIssuerRequired cliError = "secure cnames require framework.IngressController.ClusterIssuer to be set"
+
+	ErrAppDrifted cliError = "app running in the cluster differs from the application file"
 )
 
+// exitCodeError is returned by commands which have to exit with a specific exit code.
+type exitCodeError struct {
+	code    int
+	message string
+}
+
+func (e exitCodeError) Error() string { return e.message }
+
 func unwrappedError(err error) error {
 	for {
 		if errors.Unwrap(err) == nil {
@@ -24,26 +24,33 @@ type frameworkListOutput struct {
 	IngressClassName string `json:"ingressClassName" yaml:"ingressClassName"`
 	ClusterIssuer    string `json:"clusterIssuer" yaml:"clusterIssuer"`
 	Apps             string `json:"apps" yaml:"apps"`
+	ServiceEndpoint  string `json:"serviceEndpoint" yaml:"serviceEndpoint" output:"wide"`
+}
+
+type frameworkListOptions struct {
+	output string
 }
 
 func newFrameworkListCmd(cfg config, out io.Writer) *cobra.Command {
+	options := frameworkListOptions{}
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all frameworks available for deploy.",
 		Long:  frameworkListHelp,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return frameworkList(cmd.Context(), cfg, out)
+			return frameworkList(cmd.Context(), cfg, options, out)
 		},
 	}
+	cmd.Flags().StringVarP(&options.output, "output", "o", "", output.Usage)
 	return cmd
 }
 
-func frameworkList(ctx context.Context, cfg config, out io.Writer) error {
+func frameworkList(ctx context.Context, cfg config, options frameworkListOptions, out io.Writer) error {
 	frameworks := ketchv1.FrameworkList{}
 	if err := cfg.Client().List(ctx, &frameworks); err != nil {
 		return fmt.Errorf("failed to get list of frameworks: %w", err)
 	}
-	return output.Write(generateFrameworkListOutput(frameworks), out, "column")
+	return output.WriteWithObjects(generateFrameworkListOutput(frameworks), frameworks, out, options.output)
 }
 
 func generateFrameworkListOutput(frameworks ketchv1.FrameworkList) []frameworkListOutput {
@@ -61,6 +68,7 @@ func generateFrameworkListOutput(frameworks ketchv1.FrameworkList) []frameworkLi
 			IngressClassName: item.Spec.IngressController.ClassName,
 			ClusterIssuer:    item.Spec.IngressController.ClusterIssuer,
 			Apps:             apps,
+			ServiceEndpoint:  item.Spec.IngressController.ServiceEndpoint,
 		})
 	}
 	return output
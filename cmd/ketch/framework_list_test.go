@@ -48,8 +48,9 @@ func Test_frameworkList(t *testing.T) {
 		},
 	}
 	tests := []struct {
-		name string
-		cfg  config
+		name    string
+		cfg     config
+		options frameworkListOptions
 
 		wantOut string
 		wantErr bool
@@ -62,13 +63,47 @@ func Test_frameworkList(t *testing.T) {
 			wantOut: `NAME           STATUS    NAMESPACE    INGRESS TYPE    INGRESS CLASS NAME    CLUSTER ISSUER    APPS
 framework-a              a            istio           istio                 letsencrypt       0/30
 framework-b              b            traefik         classname-b           letsencrypt       0/30
+`,
+		},
+		{
+			name: "wide output",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{frameworkA},
+			},
+			options: frameworkListOptions{output: "wide"},
+			wantOut: `NAME           STATUS    NAMESPACE    INGRESS TYPE    INGRESS CLASS NAME    CLUSTER ISSUER    APPS    SERVICE ENDPOINT
+framework-a              a            istio           istio                 letsencrypt       0/30    192.168.1.17
+`,
+		},
+		{
+			name: "go template output",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{frameworkA, frameworkB},
+			},
+			options: frameworkListOptions{output: `go-template={{range .items}}{{.metadata.name}}={{.spec.ingressController.className}}{{"\n"}}{{end}}`},
+			wantOut: "framework-a=istio\nframework-b=classname-b\n",
+		},
+		{
+			name: "yaml output",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{frameworkA},
+			},
+			options: frameworkListOptions{output: "yaml"},
+			wantOut: `- apps: 0/30
+  clusterIssuer: letsencrypt
+  ingressClassName: istio
+  ingressType: istio
+  name: framework-a
+  namespace: a
+  serviceEndpoint: 192.168.1.17
+  status: ""
 `,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			out := &bytes.Buffer{}
-			err := frameworkList(context.Background(), tt.cfg, out)
+			err := frameworkList(context.Background(), tt.cfg, tt.options, out)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("frameworkList() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -23,5 +23,9 @@ func newJobCmd(cfg config, out io.Writer) *cobra.Command {
 	cmd.AddCommand(newJobDeployCmd(cfg, out))
 	cmd.AddCommand(newJobRemoveCmd(cfg, out))
 	cmd.AddCommand(newJobExportCmd(cfg, out))
+	cmd.AddCommand(newJobInfoCmd(cfg, out))
+	cmd.AddCommand(newJobLogsCmd(cfg, out, jobLogs))
+	cmd.AddCommand(newJobRunCmd(cfg, out, jobRun))
+	cmd.AddCommand(newJobRunsCmd(cfg, out))
 	return cmd
 }
@@ -72,6 +72,9 @@ func jobDeploy(ctx context.Context, cfg config, filename string, out io.Writer)
 	}
 
 	fmt.Fprintln(out, "Successfully added!")
+	if spec.IsScheduled() {
+		fmt.Fprintf(out, "Schedule: %s\n", spec.Schedule)
+	}
 	return nil
 }
 
@@ -101,5 +104,26 @@ func validateJobSpec(jobSpec *ketchv1.JobSpec) error {
 	if jobSpec.Name == "" || jobSpec.Framework == "" {
 		return errors.New("job.name and job.framework are required")
 	}
+	if jobSpec.IsScheduled() && !validCronSchedule(jobSpec.Schedule) {
+		return fmt.Errorf("invalid job.schedule %q, it must be a cron expression with 5 fields or a macro like @daily", jobSpec.Schedule)
+	}
+	switch jobSpec.ConcurrencyPolicy {
+	case "", ketchv1.AllowConcurrent, ketchv1.ForbidConcurrent, ketchv1.ReplaceConcurrent:
+	default:
+		return fmt.Errorf("invalid job.concurrencyPolicy %q, it must be one of Allow, Forbid or Replace", jobSpec.ConcurrencyPolicy)
+	}
+	if !jobSpec.IsScheduled() && (jobSpec.ConcurrencyPolicy != "" || jobSpec.SuccessfulJobsHistoryLimit != nil ||
+		jobSpec.FailedJobsHistoryLimit != nil || jobSpec.StartingDeadlineSeconds != nil) {
+		return errors.New("job.concurrencyPolicy, job.successfulJobsHistoryLimit, job.failedJobsHistoryLimit and job.startingDeadlineSeconds require job.schedule")
+	}
 	return nil
 }
+
+// validCronSchedule performs a basic check of a cron expression,
+// the schedule is fully validated by kubernetes when the CronJob is created.
+func validCronSchedule(schedule string) bool {
+	if strings.HasPrefix(schedule, "@") {
+		return true
+	}
+	return len(strings.Fields(schedule)) == 5
+}
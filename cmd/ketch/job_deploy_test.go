@@ -12,6 +12,7 @@ import (
 
 	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
 	"github.com/theketchio/ketch/internal/mocks"
+	"github.com/theketchio/ketch/internal/utils/conversions"
 )
 
 func TestJobDeploy(t *testing.T) {
@@ -73,6 +74,64 @@ policy:
 			},
 			wantOut: "Successfully added!\n",
 		},
+		{
+			name:    "scheduled job",
+			jobName: "report",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{},
+			},
+			filename: "job.yaml",
+			yamlData: `name: report
+framework: myframework
+schedule: "0 3 * * *"
+concurrencyPolicy: Forbid
+successfulJobsHistoryLimit: 1
+containers:
+  - name: report
+    image: ubuntu
+    command:
+      - ls
+`,
+			wantJobSpec: ketchv1.JobSpec{
+				Name:                       "report",
+				Version:                    "v1",
+				Type:                       "Job",
+				Framework:                  "myframework",
+				Parallelism:                1,
+				Completions:                1,
+				BackoffLimit:               6,
+				Containers:                 []ketchv1.Container{{Name: "report", Image: "ubuntu", Command: []string{"ls"}}},
+				Policy:                     ketchv1.Policy{RestartPolicy: "Never"},
+				Schedule:                   "0 3 * * *",
+				ConcurrencyPolicy:          ketchv1.ForbidConcurrent,
+				SuccessfulJobsHistoryLimit: conversions.IntPtr(1),
+			},
+			wantOut: "Successfully added!\nSchedule: 0 3 * * *\n",
+		},
+		{
+			name:    "error - invalid schedule",
+			jobName: "report",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{},
+			},
+			filename: "job.yaml",
+			yamlData: `name: report
+framework: myframework
+schedule: "every night"`,
+			wantErr: `invalid job.schedule "every night", it must be a cron expression with 5 fields or a macro like @daily`,
+		},
+		{
+			name:    "error - history limit without schedule",
+			jobName: "report",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{},
+			},
+			filename: "job.yaml",
+			yamlData: `name: report
+framework: myframework
+failedJobsHistoryLimit: 1`,
+			wantErr: "job.concurrencyPolicy, job.successfulJobsHistoryLimit, job.failedJobsHistoryLimit and job.startingDeadlineSeconds require job.schedule",
+		},
 		{
 			name:    "error - validation fail",
 			jobName: "hello",
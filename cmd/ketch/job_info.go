@@ -0,0 +1,205 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"text/template"
+	"time"
+
+	"github.com/spf13/cobra"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/theketchio/ketch/cmd/ketch/output"
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/utils"
+	"github.com/theketchio/ketch/internal/validation"
+)
+
+var (
+	jobInfoTemplate = `Job: {{ .Name }}
+Framework: {{ .Framework }}
+{{- if .Schedule }}
+Schedule: {{ .Schedule }}
+{{- end }}
+{{- if .LastScheduleTime }}
+Last scheduled: {{ .LastScheduleTime }}
+{{- end }}
+Active: {{ .Active }}
+Succeeded: {{ .Succeeded }}
+Failed: {{ .Failed }}
+{{- if .StartTime }}
+Started: {{ .StartTime }}
+{{- end }}
+{{- if .CompletionTime }}
+Completed: {{ .CompletionTime }}
+{{- end }}
+{{- if not .Pods }}
+No pods.
+{{- end }}
+`
+)
+
+type jobInfoOutput struct {
+	Name             string         `json:"name" yaml:"name"`
+	Framework        string         `json:"framework" yaml:"framework"`
+	Schedule         string         `json:"schedule,omitempty" yaml:"schedule,omitempty"`
+	LastScheduleTime string         `json:"lastScheduleTime,omitempty" yaml:"lastScheduleTime,omitempty"`
+	Active           int32          `json:"active" yaml:"active"`
+	Succeeded        int32          `json:"succeeded" yaml:"succeeded"`
+	Failed           int32          `json:"failed" yaml:"failed"`
+	StartTime        string         `json:"startTime,omitempty" yaml:"startTime,omitempty"`
+	CompletionTime   string         `json:"completionTime,omitempty" yaml:"completionTime,omitempty"`
+	Pods             []jobPodOutput `json:"pods" yaml:"pods"`
+}
+
+type jobPodOutput struct {
+	Name     string `json:"name" yaml:"name"`
+	State    string `json:"state" yaml:"state"`
+	Restarts int32  `json:"restarts" yaml:"restarts"`
+	Started  string `json:"started" yaml:"started"`
+}
+
+// jobNameLabel is added by Kubernetes to pods created by a batch job.
+const jobNameLabel = "job-name"
+
+const jobInfoHelp = `
+Show information about a specific job and the state of its pods.
+`
+
+func newJobInfoCmd(cfg config, out io.Writer) *cobra.Command {
+	options := jobInfoOptions{}
+	cmd := &cobra.Command{
+		Use:   "info JOBNAME",
+		Short: "Show information about a specific job.",
+		Args:  cobra.ExactValidArgs(1),
+		Long:  jobInfoHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.name = args[0]
+			if !validation.ValidateName(options.name) {
+				return ErrInvalidJobName
+			}
+			return jobInfo(cmd.Context(), cfg, options, out)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return autoCompleteJobNames(cfg, toComplete)
+		},
+	}
+	cmd.Flags().StringVarP(&options.output, "output", "o", "", output.Usage)
+	return cmd
+}
+
+type jobInfoOptions struct {
+	name   string
+	output string
+}
+
+func jobInfo(ctx context.Context, cfg config, options jobInfoOptions, out io.Writer) error {
+	job := ketchv1.Job{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.name}, &job); err != nil {
+		return fmt.Errorf("failed to get job: %w", err)
+	}
+	framework := ketchv1.Framework{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: job.Spec.Framework}, &framework); err != nil {
+		return fmt.Errorf("failed to get framework: %w", err)
+	}
+	selector, err := jobPodSelector(ctx, cfg, job.Name, framework.Spec.NamespaceName)
+	if err != nil {
+		return err
+	}
+	var pods []corev1.Pod
+	if selector != nil {
+		podList, err := cfg.KubernetesClient().CoreV1().Pods(framework.Spec.NamespaceName).List(ctx, metav1.ListOptions{
+			LabelSelector: selector.String(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get list of pods: %w", err)
+		}
+		pods = podList.Items
+	}
+
+	data := generateJobInfoOutput(job, pods)
+	switch options.output {
+	case output.JSON, output.YAML:
+		return output.Write(data, out, options.output)
+	case output.Name:
+		_, err := fmt.Fprintln(out, job.Name)
+		return err
+	}
+	if output.IsTemplateFormat(options.output) {
+		return output.WriteWithObjects(data, job, out, options.output)
+	}
+
+	buf := bytes.Buffer{}
+	t := template.Must(template.New("job-info").Parse(jobInfoTemplate))
+	if err := t.Execute(&buf, data); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "%v", buf.String())
+	if len(data.Pods) == 0 {
+		return nil
+	}
+	return output.Write(data.Pods, out, options.output)
+}
+
+// jobPodSelector returns a selector matching pods of all batch jobs created for the ketch job, or nil if there are none.
+// Pods are selected by the "job-name" label Kubernetes adds to them, because the pod template of a job is immutable.
+func jobPodSelector(ctx context.Context, cfg config, jobName, namespace string) (labels.Selector, error) {
+	batchJobs := batchv1.JobList{}
+	if err := cfg.Client().List(ctx, &batchJobs, client.InNamespace(namespace), client.MatchingLabels{utils.KetchAppNameLabel: jobName}); err != nil {
+		return nil, fmt.Errorf("failed to get list of jobs: %w", err)
+	}
+	if len(batchJobs.Items) == 0 {
+		return nil, nil
+	}
+	names := make([]string, 0, len(batchJobs.Items))
+	for _, batchJob := range batchJobs.Items {
+		names = append(names, batchJob.Name)
+	}
+	requirement, err := labels.NewRequirement(jobNameLabel, selection.In, names)
+	if err != nil {
+		return nil, err
+	}
+	return labels.NewSelector().Add(*requirement), nil
+}
+
+func generateJobInfoOutput(job ketchv1.Job, pods []corev1.Pod) jobInfoOutput {
+	info := jobInfoOutput{
+		Name:             job.Name,
+		Framework:        job.Spec.Framework,
+		Schedule:         job.Spec.Schedule,
+		LastScheduleTime: formatJobTime(job.Status.LastScheduleTime),
+		Active:           job.Status.Active,
+		Succeeded:        job.Status.Succeeded,
+		Failed:           job.Status.Failed,
+		StartTime:        formatJobTime(job.Status.StartTime),
+		CompletionTime:   formatJobTime(job.Status.CompletionTime),
+	}
+	for _, pod := range pods {
+		var restarts int32
+		for _, c := range pod.Status.ContainerStatuses {
+			restarts += c.RestartCount
+		}
+		info.Pods = append(info.Pods, jobPodOutput{
+			Name:     pod.Name,
+			State:    string(podState(pod)),
+			Restarts: restarts,
+			Started:  formatJobTime(pod.Status.StartTime),
+		})
+	}
+	return info
+}
+
+func formatJobTime(t *metav1.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/mocks"
+	"github.com/theketchio/ketch/internal/utils"
+)
+
+func TestJobInfo(t *testing.T) {
+	started := metav1.NewTime(time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC))
+	completed := metav1.NewTime(time.Date(2021, 10, 1, 12, 5, 0, 0, time.UTC))
+	gke := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "gke"},
+		Spec:       ketchv1.FrameworkSpec{NamespaceName: "ketch-gke"},
+	}
+	backup := &ketchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup"},
+		Spec: ketchv1.JobSpec{
+			Name:      "backup",
+			Framework: "gke",
+			Schedule:  "0 2 * * *",
+		},
+		Status: ketchv1.JobStatus{
+			Succeeded:        1,
+			StartTime:        &started,
+			CompletionTime:   &completed,
+			LastScheduleTime: &started,
+		},
+	}
+	migrate := &ketchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "migrate"},
+		Spec:       ketchv1.JobSpec{Name: "migrate", Framework: "gke"},
+	}
+	batchJob := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "backup-27215160",
+			Namespace: "ketch-gke",
+			Labels:    map[string]string{utils.KetchAppNameLabel: "backup"},
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "backup-27215160-x7k2p",
+			Namespace: "ketch-gke",
+			Labels:    map[string]string{jobNameLabel: "backup-27215160"},
+		},
+		Status: corev1.PodStatus{
+			Phase:             corev1.PodSucceeded,
+			StartTime:         &started,
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "backup", RestartCount: 2}},
+		},
+	}
+	tests := []struct {
+		name    string
+		options jobInfoOptions
+		want    string
+		wantErr string
+	}{
+		{
+			name:    "scheduled job with pods",
+			options: jobInfoOptions{name: "backup"},
+			want: `Job: backup
+Framework: gke
+Schedule: 0 2 * * *
+Last scheduled: 2021-10-01T12:00:00Z
+Active: 0
+Succeeded: 1
+Failed: 0
+Started: 2021-10-01T12:00:00Z
+Completed: 2021-10-01T12:05:00Z
+NAME                     STATE        RESTARTS    STARTED
+backup-27215160-x7k2p    succeeded    2           2021-10-01T12:00:00Z
+`,
+		},
+		{
+			name:    "job without pods",
+			options: jobInfoOptions{name: "migrate"},
+			want: `Job: migrate
+Framework: gke
+Active: 0
+Succeeded: 0
+Failed: 0
+No pods.
+`,
+		},
+		{
+			name:    "no job",
+			options: jobInfoOptions{name: "cleanup"},
+			wantErr: `failed to get job: jobs.theketch.io "cleanup" not found`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{gke, backup, migrate, batchJob},
+				KubeClientObjects: []runtime.Object{pod},
+			}
+			out := &bytes.Buffer{}
+			err := jobInfo(context.Background(), cfg, tt.options, out)
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.want, out.String())
+		})
+	}
+}
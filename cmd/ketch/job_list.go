@@ -5,11 +5,17 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/theketchio/ketch/cmd/ketch/output"
 	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/utils"
 )
 
 const jobListHelp = `
@@ -17,40 +23,103 @@ List all jobs.
 `
 
 type jobListOutput struct {
-	Name        string `json:"name"`
-	Version     string `json:"version"`
-	Framework   string `json:"framework"`
-	Description string `json:"description"`
+	Name        string `json:"name" yaml:"name"`
+	Version     string `json:"version" yaml:"version"`
+	Framework   string `json:"framework" yaml:"framework"`
+	Description string `json:"description" yaml:"description"`
+	Schedule    string `json:"schedule" yaml:"schedule"`
+	LastRun     string `json:"lastRun" yaml:"lastRun"`
+	Images      string `json:"images" yaml:"images" output:"wide"`
+}
+
+type jobListOptions struct {
+	output string
 }
 
 func newJobListCmd(cfg config, out io.Writer) *cobra.Command {
+	options := jobListOptions{}
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all jobs.",
 		Long:  jobListHelp,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return jobList(cmd.Context(), cfg, out)
+			return jobList(cmd.Context(), cfg, options, out)
 		},
 	}
+	cmd.Flags().StringVarP(&options.output, "output", "o", "", output.Usage)
 	return cmd
 }
 
-func jobList(ctx context.Context, cfg config, out io.Writer) error {
+func jobList(ctx context.Context, cfg config, options jobListOptions, out io.Writer) error {
 	jobs := ketchv1.JobList{}
 	if err := cfg.Client().List(ctx, &jobs); err != nil {
 		return fmt.Errorf("failed to get list of jobs: %w", err)
 	}
-	return output.Write(generateJobListOutput(jobs), out, "column")
+	lastRuns, err := jobLastRuns(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	return output.WriteWithObjects(generateJobListOutput(jobs, lastRuns), jobs, out, options.output)
 }
 
-func generateJobListOutput(jobs ketchv1.JobList) []jobListOutput {
+// jobLastRuns returns start times of the latest runs of jobs by job name.
+// A scheduled job is run by a CronJob, other jobs are run by a Job of the same name.
+func jobLastRuns(ctx context.Context, cfg config) (map[string]time.Time, error) {
+	lastRuns := map[string]time.Time{}
+	cronJobs := batchv1.CronJobList{}
+	err := cfg.Client().List(ctx, &cronJobs, client.HasLabels{utils.KetchAppNameLabel})
+	for _, cronJob := range cronJobs.Items {
+		if cronJob.Status.LastScheduleTime != nil {
+			lastRuns[cronJob.Name] = cronJob.Status.LastScheduleTime.Time
+		}
+	}
+	if meta.IsNoMatchError(err) {
+		// batch/v1 CronJobs are served since kubernetes 1.21.
+		cronJobs := batchv1beta1.CronJobList{}
+		err = cfg.Client().List(ctx, &cronJobs, client.HasLabels{utils.KetchAppNameLabel})
+		for _, cronJob := range cronJobs.Items {
+			if cronJob.Status.LastScheduleTime != nil {
+				lastRuns[cronJob.Name] = cronJob.Status.LastScheduleTime.Time
+			}
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get list of cron jobs: %w", err)
+	}
+	batchJobs := batchv1.JobList{}
+	if err := cfg.Client().List(ctx, &batchJobs, client.HasLabels{utils.KetchAppNameLabel}); err != nil {
+		return nil, fmt.Errorf("failed to get list of batch jobs: %w", err)
+	}
+	for _, batchJob := range batchJobs.Items {
+		if batchJob.Name != batchJob.Labels[utils.KetchAppNameLabel] || batchJob.Status.StartTime == nil {
+			continue
+		}
+		if _, ok := lastRuns[batchJob.Name]; !ok {
+			lastRuns[batchJob.Name] = batchJob.Status.StartTime.Time
+		}
+	}
+	return lastRuns, nil
+}
+
+func generateJobListOutput(jobs ketchv1.JobList, lastRuns map[string]time.Time) []jobListOutput {
 	var output []jobListOutput
 	for _, item := range jobs.Items {
+		var lastRun string
+		if t, ok := lastRuns[item.Spec.Name]; ok {
+			lastRun = t.UTC().Format(time.RFC3339)
+		}
+		var images []string
+		for _, container := range item.Spec.Containers {
+			images = append(images, container.Image)
+		}
 		output = append(output, jobListOutput{
 			Name:        item.Name,
 			Version:     item.Spec.Version,
 			Framework:   item.Spec.Framework,
 			Description: item.Spec.Description,
+			Schedule:    item.Spec.Schedule,
+			LastRun:     lastRun,
+			Images:      strings.Join(images, " "),
 		})
 	}
 	return output
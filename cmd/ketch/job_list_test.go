@@ -4,13 +4,16 @@ import (
 	"bytes"
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
 	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
 	"github.com/theketchio/ketch/internal/mocks"
+	"github.com/theketchio/ketch/internal/utils"
 )
 
 func TestJobList(t *testing.T) {
@@ -38,9 +41,17 @@ func TestJobList(t *testing.T) {
 			Type: "Job",
 		},
 	}
+	scheduledJob := mockJob.DeepCopy()
+	scheduledJob.Name = "report"
+	scheduledJob.Spec.Name = "report"
+	scheduledJob.Spec.Description = "nightly"
+	scheduledJob.Spec.Schedule = "0 3 * * *"
+	lastRun := metav1.Date(2021, 2, 1, 3, 0, 0, 0, time.UTC)
+
 	tests := []struct {
 		name    string
 		cfg     config
+		options jobListOptions
 		wantOut string
 		wantErr string
 	}{
@@ -50,13 +61,54 @@ func TestJobList(t *testing.T) {
 				CtrlClientObjects:    []runtime.Object{mockJob},
 				DynamicClientObjects: []runtime.Object{},
 			},
-			wantOut: "NAME     VERSION    FRAMEWORK      DESCRIPTION\nhello    v1         myframework    test\n",
+			wantOut: "NAME     VERSION    FRAMEWORK      DESCRIPTION    SCHEDULE    LAST RUN\nhello    v1         myframework    test           \n",
+		},
+		{
+			name: "wide output",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{mockJob},
+			},
+			options: jobListOptions{output: "wide"},
+			wantOut: "NAME     VERSION    FRAMEWORK      DESCRIPTION    SCHEDULE    LAST RUN    IMAGES\nhello    v1         myframework    test                                   ubuntu\n",
+		},
+		{
+			name: "json output",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{mockJob},
+			},
+			options: jobListOptions{output: "json"},
+			wantOut: "[\n  {\n    \"name\": \"hello\",\n    \"version\": \"v1\",\n    \"framework\": \"myframework\",\n    \"description\": \"test\",\n    \"schedule\": \"\",\n    \"lastRun\": \"\",\n    \"images\": \"ubuntu\"\n  }\n]\n",
+		},
+		{
+			name: "scheduled job",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{
+					scheduledJob,
+					&batchv1.CronJob{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "report",
+							Namespace: "ketch-myframework",
+							Labels:    map[string]string{utils.KetchAppNameLabel: "report"},
+						},
+						Status: batchv1.CronJobStatus{LastScheduleTime: &lastRun},
+					},
+				},
+			},
+			wantOut: "NAME      VERSION    FRAMEWORK      DESCRIPTION    SCHEDULE     LAST RUN\nreport    v1         myframework    nightly        0 3 * * *    2021-02-01T03:00:00Z\n",
+		},
+		{
+			name: "json output without jobs",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{},
+			},
+			options: jobListOptions{output: "json"},
+			wantOut: "[]\n",
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			out := &bytes.Buffer{}
-			err := jobList(context.Background(), tt.cfg, out)
+			err := jobList(context.Background(), tt.cfg, tt.options, out)
 			if len(tt.wantErr) > 0 {
 				require.NotNil(t, err)
 				require.Equal(t, tt.wantErr, err.Error())
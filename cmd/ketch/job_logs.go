@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/validation"
+)
+
+const jobLogsHelp = `
+Show logs of a job.
+Logs are read from the first container of the job unless --container is set.
+`
+
+type jobLogsFn func(context.Context, config, jobLogsOptions, io.Writer, watchLogsFn) error
+
+func newJobLogsCmd(cfg config, out io.Writer, jobLogs jobLogsFn) *cobra.Command {
+	options := jobLogsOptions{}
+	cmd := &cobra.Command{
+		Use:   "logs JOBNAME",
+		Short: "Show logs of a job.",
+		Args:  cobra.ExactValidArgs(1),
+		Long:  jobLogsHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.jobName = args[0]
+			if !validation.ValidateName(options.jobName) {
+				return ErrInvalidJobName
+			}
+			return jobLogs(cmd.Context(), cfg, options, out, watchLogs)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return autoCompleteJobNames(cfg, toComplete)
+		},
+	}
+	cmd.Flags().StringVarP(&options.containerName, "container", "c", "", "Container name")
+	cmd.Flags().BoolVarP(&options.follow, "follow", "f", false, "Specify if the logs should be streamed")
+	cmd.Flags().BoolVar(&options.ignoreErrors, "ignore-errors", false, "If watching / following pod logs, allow for any errors that occur to be non-fatal")
+	cmd.Flags().BoolVar(&options.prefix, "prefix", false, "Prefix each log line with the log source (pod name and container name)")
+	cmd.Flags().BoolVar(&options.timestamps, "timestamps", false, "Include timestamps on each line in the log output")
+	return cmd
+}
+
+type jobLogsOptions struct {
+	jobName       string
+	containerName string
+	follow        bool
+	ignoreErrors  bool
+	timestamps    bool
+	prefix        bool
+}
+
+func jobLogs(ctx context.Context, cfg config, options jobLogsOptions, out io.Writer, watchLogs watchLogsFn) error {
+	job := ketchv1.Job{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.jobName}, &job); err != nil {
+		return fmt.Errorf("failed to get job instance: %w", err)
+	}
+	framework := ketchv1.Framework{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: job.Spec.Framework}, &framework); err != nil {
+		return fmt.Errorf("failed to get framework instance: %w", err)
+	}
+	containerName := options.containerName
+	if len(containerName) == 0 {
+		if len(job.Spec.Containers) == 0 {
+			return fmt.Errorf("job %s doesn't have containers", job.Name)
+		}
+		containerName = job.Spec.Containers[0].Name
+	}
+	selector, err := jobPodSelector(ctx, cfg, job.Name, framework.Spec.NamespaceName)
+	if err != nil {
+		return err
+	}
+	if selector == nil {
+		return fmt.Errorf("job %s has no runs", job.Name)
+	}
+	opts := watchOptions{
+		namespace:     framework.Spec.NamespaceName,
+		selector:      selector,
+		follow:        options.follow,
+		ignoreErrors:  options.ignoreErrors,
+		timestamps:    options.timestamps,
+		prefix:        options.prefix,
+		out:           out,
+		containerName: containerName,
+	}
+	return watchLogs(cfg.KubernetesClient(), opts, readLogs, streamLogs)
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/mocks"
+	"github.com/theketchio/ketch/internal/utils"
+)
+
+func Test_jobLogs(t *testing.T) {
+	gke := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "gke"},
+		Spec:       ketchv1.FrameworkSpec{NamespaceName: "ketch-gke"},
+	}
+	backup := &ketchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup"},
+		Spec: ketchv1.JobSpec{
+			Name:       "backup",
+			Framework:  "gke",
+			Containers: []ketchv1.Container{{Name: "dump"}, {Name: "upload"}},
+		},
+	}
+	newBatchJob := func(name string) *batchv1.Job {
+		return &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "ketch-gke",
+				Labels:    map[string]string{utils.KetchAppNameLabel: "backup"},
+			},
+		}
+	}
+	backupRun := newBatchJob("backup")
+	backupScheduledRun := newBatchJob("backup-27215160")
+	selector, err := labels.Parse(jobNameLabel + " in (backup,backup-27215160)")
+	require.Nil(t, err)
+	tests := []struct {
+		description      string
+		objects          []runtime.Object
+		options          jobLogsOptions
+		wantErr          string
+		wantWatchOptions watchOptions
+	}{
+		{
+			description: "first container + follow",
+			objects:     []runtime.Object{gke, backup, backupRun, backupScheduledRun},
+			options:     jobLogsOptions{jobName: "backup", follow: true},
+			wantWatchOptions: watchOptions{
+				namespace:     "ketch-gke",
+				selector:      selector,
+				follow:        true,
+				containerName: "dump",
+			},
+		},
+		{
+			description: "selected container + prefix",
+			objects:     []runtime.Object{gke, backup, backupRun, backupScheduledRun},
+			options:     jobLogsOptions{jobName: "backup", containerName: "upload", prefix: true},
+			wantWatchOptions: watchOptions{
+				namespace:     "ketch-gke",
+				selector:      selector,
+				prefix:        true,
+				containerName: "upload",
+			},
+		},
+		{
+			description: "job without runs",
+			objects:     []runtime.Object{gke, backup},
+			options:     jobLogsOptions{jobName: "backup"},
+			wantErr:     `job backup has no runs`,
+		},
+		{
+			description: "no job",
+			objects:     []runtime.Object{gke},
+			options:     jobLogsOptions{jobName: "backup"},
+			wantErr:     `failed to get job instance: jobs.theketch.io "backup" not found`,
+		},
+		{
+			description: "no framework",
+			objects:     []runtime.Object{backup},
+			options:     jobLogsOptions{jobName: "backup"},
+			wantErr:     `failed to get framework instance: frameworks.theketch.io "gke" not found`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			cfg := &mocks.Configuration{CtrlClientObjects: tt.objects}
+			called := false
+			watchFn := func(client kubernetes.Interface, options watchOptions, readLogs_ readLogsFn, streamLogs_ streamLogsFn) error {
+				called = true
+				options.out = nil
+				require.Equal(t, tt.wantWatchOptions, options)
+				return nil
+			}
+			err := jobLogs(context.Background(), cfg, tt.options, &bytes.Buffer{}, watchFn)
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				require.False(t, called)
+				return
+			}
+			require.Nil(t, err)
+			require.True(t, called)
+		})
+	}
+}
+
+func Test_watchOptions_podContainerName(t *testing.T) {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup-x7k2p"},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "dump"}, {Name: "upload"}},
+		},
+	}
+	name, err := watchOptions{containerName: "upload"}.podContainerName(pod)
+	require.Nil(t, err)
+	require.Equal(t, "upload", *name)
+
+	_, err = watchOptions{containerName: "istio-proxy"}.podContainerName(pod)
+	require.NotNil(t, err)
+	require.Equal(t, "pod backup-x7k2p doesn't have container istio-proxy", err.Error())
+
+	_, err = watchOptions{}.podContainerName(pod)
+	require.NotNil(t, err)
+	require.Equal(t, "pod backup-x7k2p doesn't have an app container", err.Error())
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/utils"
+	"github.com/theketchio/ketch/internal/validation"
+)
+
+const jobRunHelp = `
+Start a new run of a deployed job.
+Every run is a separate kubernetes Job, the job's previous runs are kept.
+Environment variables of the job's containers can be overridden for the run.
+
+  ketch job run JOBNAME
+  ketch job run JOBNAME --env DRY_RUN=false --env BATCH_SIZE=100
+`
+
+type jobRunFn func(context.Context, config, jobRunOptions, io.Writer) error
+
+func newJobRunCmd(cfg config, out io.Writer, jobRun jobRunFn) *cobra.Command {
+	options := jobRunOptions{}
+	cmd := &cobra.Command{
+		Use:   "run JOBNAME",
+		Short: "Start a new run of a job.",
+		Args:  cobra.ExactValidArgs(1),
+		Long:  jobRunHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.jobName = args[0]
+			if !validation.ValidateName(options.jobName) {
+				return ErrInvalidJobName
+			}
+			return jobRun(cmd.Context(), cfg, options, out)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return autoCompleteJobNames(cfg, toComplete)
+		},
+	}
+	cmd.Flags().StringArrayVarP(&options.envs, "env", "e", nil, "Override an environment variable for the run, in the format KEY=VALUE.")
+	return cmd
+}
+
+type jobRunOptions struct {
+	jobName string
+	envs    []string
+}
+
+func jobRun(ctx context.Context, cfg config, options jobRunOptions, out io.Writer) error {
+	envs, err := utils.MakeEnvironments(options.envs)
+	if err != nil {
+		return err
+	}
+	job := ketchv1.Job{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.jobName}, &job); err != nil {
+		return fmt.Errorf("failed to get job: %w", err)
+	}
+	framework := ketchv1.Framework{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: job.Spec.Framework}, &framework); err != nil {
+		return fmt.Errorf("failed to get framework: %w", err)
+	}
+	spec, err := deployedJobSpec(ctx, cfg, job, framework.Spec.NamespaceName)
+	if err != nil {
+		return err
+	}
+	run := newJobRun(job, framework.Spec.NamespaceName, *spec, envs)
+	// the run is owned by the job, so it's removed together with the job.
+	if err := controllerutil.SetOwnerReference(&job, run, cfg.Client().Scheme()); err != nil {
+		return fmt.Errorf("failed to set owner of job run: %w", err)
+	}
+	if err := cfg.Client().Create(ctx, run); err != nil {
+		return fmt.Errorf("failed to create job run: %w", err)
+	}
+	fmt.Fprintf(out, "Successfully started %s!\n", run.Name)
+	return nil
+}
+
+// deployedJobSpec returns the spec of the kubernetes Job installed for the job,
+// it's the job template of a CronJob for a scheduled job.
+func deployedJobSpec(ctx context.Context, cfg config, job ketchv1.Job, namespace string) (*batchv1.JobSpec, error) {
+	name := types.NamespacedName{Namespace: namespace, Name: job.Name}
+	if job.Spec.IsScheduled() {
+		cronJob := batchv1.CronJob{}
+		err := cfg.Client().Get(ctx, name, &cronJob)
+		if meta.IsNoMatchError(err) {
+			// batch/v1 CronJobs are served since kubernetes 1.21.
+			cronJobV1beta1 := batchv1beta1.CronJob{}
+			err = cfg.Client().Get(ctx, name, &cronJobV1beta1)
+			cronJob.Spec.JobTemplate.Spec = cronJobV1beta1.Spec.JobTemplate.Spec
+		}
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, fmt.Errorf("job %s is not deployed yet", job.Name)
+			}
+			return nil, fmt.Errorf("failed to get cron job: %w", err)
+		}
+		return &cronJob.Spec.JobTemplate.Spec, nil
+	}
+	batchJob := batchv1.Job{}
+	if err := cfg.Client().Get(ctx, name, &batchJob); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("job %s is not deployed yet", job.Name)
+		}
+		return nil, fmt.Errorf("failed to get batch job: %w", err)
+	}
+	return &batchJob.Spec, nil
+}
+
+// generatedJobLabels are the pod template labels kubernetes generates for every Job,
+// the batch.kubernetes.io prefixed ones are added since kubernetes 1.27.
+var generatedJobLabels = []string{
+	"controller-uid",
+	"job-name",
+	"batch.kubernetes.io/controller-uid",
+	"batch.kubernetes.io/job-name",
+}
+
+// newJobRun returns a kubernetes Job running the given spec with the env variables overridden.
+func newJobRun(job ketchv1.Job, namespace string, spec batchv1.JobSpec, envs []ketchv1.Env) *batchv1.Job {
+	spec = *spec.DeepCopy()
+	// the selector and its labels are generated by kubernetes for every Job.
+	spec.Selector = nil
+	spec.ManualSelector = nil
+	for _, label := range generatedJobLabels {
+		delete(spec.Template.Labels, label)
+	}
+	spec.Suspend = nil
+	for i := range spec.Template.Spec.Containers {
+		spec.Template.Spec.Containers[i].Env = overrideEnvs(spec.Template.Spec.Containers[i].Env, envs)
+	}
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: fmt.Sprintf("%s-run-", job.Name),
+			Namespace:    namespace,
+			Labels: map[string]string{
+				utils.KetchAppNameLabel: job.Name,
+				utils.KetchJobRunLabel:  "true",
+			},
+		},
+		Spec: spec,
+	}
+}
+
+func overrideEnvs(current []corev1.EnvVar, envs []ketchv1.Env) []corev1.EnvVar {
+	for _, env := range envs {
+		found := false
+		for i := range current {
+			if current[i].Name == env.Name {
+				current[i] = corev1.EnvVar{Name: env.Name, Value: env.Value}
+				found = true
+				break
+			}
+		}
+		if !found {
+			current = append(current, corev1.EnvVar{Name: env.Name, Value: env.Value})
+		}
+	}
+	return current
+}
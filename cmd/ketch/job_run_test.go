@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/mocks"
+	"github.com/theketchio/ketch/internal/utils"
+)
+
+func TestJobRun(t *testing.T) {
+	gke := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "gke"},
+		Spec:       ketchv1.FrameworkSpec{NamespaceName: "ketch-gke"},
+	}
+	podSpec := corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{utils.KetchAppNameLabel: "backup"},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			Containers: []corev1.Container{
+				{Name: "dump", Image: "postgres", Env: []corev1.EnvVar{{Name: "DRY_RUN", Value: "true"}}},
+			},
+		},
+	}
+	backup := &ketchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup"},
+		Spec:       ketchv1.JobSpec{Name: "backup", Framework: "gke"},
+	}
+	batchJob := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: "ketch-gke"},
+		Spec: batchv1.JobSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"controller-uid": "1234"}},
+			Template: *podSpec.DeepCopy(),
+		},
+	}
+	batchJob.Spec.Template.Labels["controller-uid"] = "1234"
+	batchJob.Spec.Template.Labels["job-name"] = "backup"
+	batchJob.Spec.Template.Labels["batch.kubernetes.io/controller-uid"] = "1234"
+	batchJob.Spec.Template.Labels["batch.kubernetes.io/job-name"] = "backup"
+	scheduledBackup := &ketchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup"},
+		Spec:       ketchv1.JobSpec{Name: "backup", Framework: "gke", Schedule: "0 2 * * *"},
+	}
+	cronJob := &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: "ketch-gke"},
+		Spec: batchv1.CronJobSpec{
+			Schedule:    "0 2 * * *",
+			JobTemplate: batchv1.JobTemplateSpec{Spec: batchv1.JobSpec{Template: *podSpec.DeepCopy()}},
+		},
+	}
+	tests := []struct {
+		name     string
+		objects  []runtime.Object
+		options  jobRunOptions
+		wantEnvs []corev1.EnvVar
+		wantErr  string
+	}{
+		{
+			name:     "job",
+			objects:  []runtime.Object{gke, backup, batchJob},
+			options:  jobRunOptions{jobName: "backup"},
+			wantEnvs: []corev1.EnvVar{{Name: "DRY_RUN", Value: "true"}},
+		},
+		{
+			name:     "scheduled job with env overrides",
+			objects:  []runtime.Object{gke, scheduledBackup, cronJob},
+			options:  jobRunOptions{jobName: "backup", envs: []string{"DRY_RUN=false", "BATCH_SIZE=100"}},
+			wantEnvs: []corev1.EnvVar{{Name: "DRY_RUN", Value: "false"}, {Name: "BATCH_SIZE", Value: "100"}},
+		},
+		{
+			name:    "job not deployed",
+			objects: []runtime.Object{gke, backup},
+			options: jobRunOptions{jobName: "backup"},
+			wantErr: "job backup is not deployed yet",
+		},
+		{
+			name:    "invalid env",
+			objects: []runtime.Object{gke, backup, batchJob},
+			options: jobRunOptions{jobName: "backup", envs: []string{"DRY_RUN"}},
+			wantErr: "env variables should have NAME=VALUE format",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &mocks.Configuration{CtrlClientObjects: tt.objects}
+			out := &bytes.Buffer{}
+			err := jobRun(context.Background(), cfg, tt.options, out)
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.Nil(t, err)
+
+			runs := batchv1.JobList{}
+			require.Nil(t, cfg.Client().List(context.Background(), &runs, client.MatchingLabels{utils.KetchJobRunLabel: "true"}))
+			require.Len(t, runs.Items, 1)
+			run := runs.Items[0]
+			require.True(t, strings.HasPrefix(run.Name, "backup-run-"))
+			require.Equal(t, "Successfully started "+run.Name+"!\n", out.String())
+			require.Equal(t, "ketch-gke", run.Namespace)
+			require.Equal(t, "backup", run.Labels[utils.KetchAppNameLabel])
+			require.Len(t, run.OwnerReferences, 1)
+			require.Equal(t, "backup", run.OwnerReferences[0].Name)
+			require.Nil(t, run.Spec.Selector)
+			require.Equal(t, map[string]string{utils.KetchAppNameLabel: "backup"}, run.Spec.Template.Labels)
+			require.Equal(t, tt.wantEnvs, run.Spec.Template.Spec.Containers[0].Env)
+		})
+	}
+}
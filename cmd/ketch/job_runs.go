@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/theketchio/ketch/cmd/ketch/output"
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/validation"
+)
+
+const jobRunsHelp = `
+List on-demand runs of a job started by "ketch job run", the most recent first.
+The number of finished runs kept is controlled by the job's runHistoryLimit.
+`
+
+type jobRunOutput struct {
+	Name      string `json:"name" yaml:"name"`
+	Status    string `json:"status" yaml:"status"`
+	Started   string `json:"started" yaml:"started"`
+	Completed string `json:"completed" yaml:"completed"`
+	Succeeded int32  `json:"succeeded" yaml:"succeeded" output:"wide"`
+	Failed    int32  `json:"failed" yaml:"failed" output:"wide"`
+}
+
+type jobRunsOptions struct {
+	jobName string
+	output  string
+}
+
+func newJobRunsCmd(cfg config, out io.Writer) *cobra.Command {
+	options := jobRunsOptions{}
+	cmd := &cobra.Command{
+		Use:   "runs JOBNAME",
+		Short: "List on-demand runs of a job.",
+		Args:  cobra.ExactValidArgs(1),
+		Long:  jobRunsHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.jobName = args[0]
+			if !validation.ValidateName(options.jobName) {
+				return ErrInvalidJobName
+			}
+			return jobRuns(cmd.Context(), cfg, options, out)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return autoCompleteJobNames(cfg, toComplete)
+		},
+	}
+	cmd.Flags().StringVarP(&options.output, "output", "o", "", output.Usage)
+	return cmd
+}
+
+func jobRuns(ctx context.Context, cfg config, options jobRunsOptions, out io.Writer) error {
+	job := ketchv1.Job{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.jobName}, &job); err != nil {
+		return fmt.Errorf("failed to get job: %w", err)
+	}
+	if len(job.Status.Runs) == 0 && len(options.output) == 0 {
+		fmt.Fprintln(out, "No runs.")
+		return nil
+	}
+	return output.WriteWithObjects(generateJobRunsOutput(job.Status.Runs), job.Status.Runs, out, options.output)
+}
+
+func generateJobRunsOutput(runs []ketchv1.JobRun) []jobRunOutput {
+	var output []jobRunOutput
+	for _, run := range runs {
+		output = append(output, jobRunOutput{
+			Name:      run.Name,
+			Status:    string(run.Phase),
+			Started:   formatJobTime(run.StartTime),
+			Completed: formatJobTime(run.CompletionTime),
+			Succeeded: run.Succeeded,
+			Failed:    run.Failed,
+		})
+	}
+	return output
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/mocks"
+)
+
+func TestJobRuns(t *testing.T) {
+	started := metav1.NewTime(time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC))
+	completed := metav1.NewTime(time.Date(2021, 10, 1, 12, 5, 0, 0, time.UTC))
+	backup := &ketchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup"},
+		Spec:       ketchv1.JobSpec{Name: "backup", Framework: "gke"},
+		Status: ketchv1.JobStatus{
+			Runs: []ketchv1.JobRun{
+				{Name: "backup-run-x7k2p", Phase: ketchv1.JobRunRunning, StartTime: &completed},
+				{Name: "backup-run-9fj3s", Phase: ketchv1.JobRunSucceeded, StartTime: &started, CompletionTime: &completed, Succeeded: 1},
+			},
+		},
+	}
+	migrate := &ketchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "migrate"},
+		Spec:       ketchv1.JobSpec{Name: "migrate", Framework: "gke"},
+	}
+	tests := []struct {
+		name    string
+		options jobRunsOptions
+		want    string
+		wantErr string
+	}{
+		{
+			name:    "runs",
+			options: jobRunsOptions{jobName: "backup"},
+			want: `NAME                STATUS       STARTED                 COMPLETED
+backup-run-x7k2p    Running      2021-10-01T12:05:00Z    
+backup-run-9fj3s    Succeeded    2021-10-01T12:00:00Z    2021-10-01T12:05:00Z
+`,
+		},
+		{
+			name:    "no runs",
+			options: jobRunsOptions{jobName: "migrate"},
+			want:    "No runs.\n",
+		},
+		{
+			name:    "no job",
+			options: jobRunsOptions{jobName: "cleanup"},
+			wantErr: `failed to get job: jobs.theketch.io "cleanup" not found`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &mocks.Configuration{CtrlClientObjects: []runtime.Object{backup, migrate}}
+			out := &bytes.Buffer{}
+			err := jobRuns(context.Background(), cfg, tt.options, out)
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.want, out.String())
+		})
+	}
+}
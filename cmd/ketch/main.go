@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"log"
 	"os"
 
@@ -30,6 +31,11 @@ func main() {
 
 	cmd := newRootCmd(&configuration.Configuration{}, out, packSvc, getKetchConfig())
 	if err := cmd.Execute(); err != nil {
+		var exitErr exitCodeError
+		if errors.As(err, &exitErr) {
+			log.Printf("execution failed %q", err)
+			os.Exit(exitErr.code)
+		}
 		log.Fatalf("execution failed %q", err)
 	}
 }
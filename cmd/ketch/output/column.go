@@ -14,6 +14,8 @@ import (
 type columnOutput struct {
 	data   interface{}
 	writer io.Writer
+	// wide enables columns of fields tagged with `output:"wide"`.
+	wide bool
 }
 
 // val is a structure for storing a Value and it's column struct tag together
@@ -77,6 +79,11 @@ func (c *columnOutput) marshal(v interface{}) ([]byte, error) {
 	if len(valSets) < 1 {
 		return nil, nil
 	}
+	if !c.wide {
+		for i := range valSets {
+			valSets[i] = valSets[i].withoutWide()
+		}
+	}
 
 	var buf bytes.Buffer
 	w := tabwriter.NewWriter(&buf, 0, 4, 4, ' ', 0)
@@ -145,9 +152,21 @@ func newValSet(value reflect.Value) valSet {
 					builder.WriteString(string(r)) // write currently uppercase runes
 				}
 			}
-			tag = reflect.StructTag(fmt.Sprintf("column:\"%s\"", builder.String()))
+			tag = reflect.StructTag(strings.TrimSpace(fmt.Sprintf("column:\"%s\" %s", builder.String(), tag)))
 		}
 		valSet = append(valSet, val{tag: tag, value: value.Field(i)})
 	}
 	return valSet
 }
+
+// withoutWide returns the valSet without values of fields tagged with `output:"wide"`
+func (vs valSet) withoutWide() valSet {
+	var filtered valSet
+	for _, val := range vs {
+		if hasOutputOption(val.tag, Wide) {
+			continue
+		}
+		filtered = append(filtered, val)
+	}
+	return filtered
+}
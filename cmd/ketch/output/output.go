@@ -1,7 +1,16 @@
+// Package output writes results of CLI commands in the format requested with the output flag.
+//
+// The column format prints a table with a column per field, headings are taken from the "column" struct tag.
+// Fields tagged with `output:"wide"` are only printed by the wide format.
+// The json and yaml formats print the data with the field names of their "json" struct tags, so the schema of
+// a command's output is the output struct of the command.
+// The name format prints the field tagged with `output:"name"` or the Name field of every item, one per line.
+// The custom-columns and go-template formats are written by WriteWithObjects and refer to fields of kubernetes objects.
 package output
 
 import (
 	"errors"
+	"fmt"
 	"io"
 	"os"
 
@@ -12,17 +21,49 @@ type writer interface {
 	write() error
 }
 
+const (
+	// Column is the default output format.
+	Column = "column"
+	// Wide is the column format with additional columns.
+	Wide = "wide"
+	JSON = "json"
+	YAML = "yaml"
+	// Name prints names of items only.
+	Name = "name"
+)
+
+// Usage is a usage message of the output flag.
+const Usage = "Output format. One of: json|yaml|wide|name|custom-columns=...|go-template=..."
+
 var ErrFileExists = errors.New("file already exists")
 
 // Write writes data to out, switching marshaling type based on outputFlag
 func Write(data interface{}, out io.Writer, outputFlag string) error {
 	var w writer
 	switch outputFlag {
-	default:
+	case "", Column, Wide:
 		w = &columnOutput{
 			data:   data,
 			writer: out,
+			wide:   outputFlag == Wide,
+		}
+	case JSON:
+		w = &jsonOutput{
+			data:   data,
+			writer: out,
 		}
+	case YAML:
+		w = &yamlOutput{
+			data:   data,
+			writer: out,
+		}
+	case Name:
+		w = &nameOutput{
+			data:   data,
+			writer: out,
+		}
+	default:
+		return fmt.Errorf("unsupported output format %q", outputFlag)
 	}
 	return w.write()
 }
@@ -0,0 +1,89 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type wideItem struct {
+	Key   string `json:"key" output:"name"`
+	Value int    `json:"value"`
+	Extra string `json:"extra" output:"wide"`
+}
+
+func TestWrite(t *testing.T) {
+	items := []wideItem{
+		{Key: "a", Value: 1, Extra: "x"},
+		{Key: "b", Value: 2, Extra: "y"},
+	}
+	tests := []struct {
+		description string
+		data        interface{}
+		format      string
+		expected    string
+		wantErr     bool
+	}{
+		{
+			description: "column",
+			data:        items,
+			format:      Column,
+			expected:    "KEY    VALUE\na      1\nb      2\n",
+		},
+		{
+			description: "wide",
+			data:        items,
+			format:      Wide,
+			expected:    "KEY    VALUE    EXTRA\na      1        x\nb      2        y\n",
+		},
+		{
+			description: "json",
+			data:        items[:1],
+			format:      JSON,
+			expected:    "[\n  {\n    \"key\": \"a\",\n    \"value\": 1,\n    \"extra\": \"x\"\n  }\n]\n",
+		},
+		{
+			description: "yaml",
+			data:        items[:1],
+			format:      YAML,
+			expected:    "- extra: x\n  key: a\n  value: 1\n",
+		},
+		{
+			description: "yaml without items",
+			data:        []wideItem(nil),
+			format:      YAML,
+			expected:    "[]\n",
+		},
+		{
+			description: "name",
+			data:        items,
+			format:      Name,
+			expected:    "a\nb\n",
+		},
+		{
+			description: "name of a struct without name field",
+			data:        struct{ Value int }{Value: 1},
+			format:      Name,
+			wantErr:     true,
+		},
+		{
+			description: "unsupported format",
+			data:        items,
+			format:      "xml",
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			err := Write(tt.data, out, tt.format)
+			if tt.wantErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.expected, out.String())
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// jsonOutput represents data and a writer for json output type
+type jsonOutput struct {
+	data   interface{}
+	writer io.Writer
+}
+
+func (j *jsonOutput) write() error {
+	b, err := json.MarshalIndent(emptySliceIfNil(j.data), "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(j.writer, string(b))
+	return err
+}
+
+// yamlOutput represents data and a writer for yaml output type
+type yamlOutput struct {
+	data   interface{}
+	writer io.Writer
+}
+
+func (y *yamlOutput) write() error {
+	b, err := yaml.Marshal(emptySliceIfNil(y.data))
+	if err != nil {
+		return err
+	}
+	_, err = y.writer.Write(b)
+	return err
+}
+
+// nameOutput represents data and a writer for name output type
+type nameOutput struct {
+	data   interface{}
+	writer io.Writer
+}
+
+func (n *nameOutput) write() error {
+	value := reflect.ValueOf(n.data)
+	var items []reflect.Value
+	switch value.Kind() {
+	case reflect.Slice:
+		for i := 0; i < value.Len(); i++ {
+			items = append(items, reflect.Indirect(value.Index(i)))
+		}
+	case reflect.Struct, reflect.Ptr:
+		items = append(items, reflect.Indirect(value))
+	default:
+		return fmt.Errorf("unsupported kind: %s", value.Kind())
+	}
+	for _, item := range items {
+		name, err := itemName(item)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(n.writer, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// itemName returns a value of the field tagged with `output:"name"` or of the Name field.
+func itemName(item reflect.Value) (interface{}, error) {
+	if item.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("unsupported kind: %s", item.Kind())
+	}
+	for i := 0; i < item.NumField(); i++ {
+		if hasOutputOption(item.Type().Field(i).Tag, Name) {
+			return item.Field(i).Interface(), nil
+		}
+	}
+	if field := item.FieldByName("Name"); field.IsValid() {
+		return field.Interface(), nil
+	}
+	return nil, fmt.Errorf("%s has no name field", item.Type())
+}
+
+func hasOutputOption(tag reflect.StructTag, option string) bool {
+	for _, o := range strings.Split(tag.Get("output"), ",") {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+// emptySliceIfNil makes nil slices marshal as empty lists instead of null.
+func emptySliceIfNil(data interface{}) interface{} {
+	value := reflect.ValueOf(data)
+	if value.Kind() == reflect.Slice && value.IsNil() {
+		return reflect.MakeSlice(value.Type(), 0, 0).Interface()
+	}
+	return data
+}
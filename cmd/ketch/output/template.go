@@ -0,0 +1,91 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/kubectl/pkg/cmd/get"
+)
+
+const (
+	// CustomColumnsPrefix starts the custom-columns format, e.g. custom-columns=NAME:.metadata.name,IMAGE:.spec.deployments[0].image
+	CustomColumnsPrefix = "custom-columns="
+	// GoTemplatePrefix starts the go-template format, e.g. go-template={{range .items}}{{.metadata.name}}{{"\n"}}{{end}}
+	GoTemplatePrefix = "go-template="
+)
+
+// WriteWithObjects writes objects in the custom-columns and go-template formats with kubectl's printers,
+// and writes data with Write for the other formats.
+// Lists of objects are expected to have the "items" field like kubernetes lists have, slices are printed as lists.
+func WriteWithObjects(data, objects interface{}, out io.Writer, outputFlag string) error {
+	var printer printers.ResourcePrinter
+	switch {
+	case strings.HasPrefix(outputFlag, CustomColumnsPrefix):
+		p, err := get.NewCustomColumnsPrinterFromSpec(strings.TrimPrefix(outputFlag, CustomColumnsPrefix), unstructured.UnstructuredJSONScheme, false)
+		if err != nil {
+			return err
+		}
+		printer = p
+	case strings.HasPrefix(outputFlag, GoTemplatePrefix):
+		p, err := printers.NewGoTemplatePrinter([]byte(strings.TrimPrefix(outputFlag, GoTemplatePrefix)))
+		if err != nil {
+			return fmt.Errorf("failed to parse go-template: %w", err)
+		}
+		printer = p
+	default:
+		return Write(data, out, outputFlag)
+	}
+	obj, err := toUnstructured(objects)
+	if err != nil {
+		return err
+	}
+	return printer.PrintObj(obj, out)
+}
+
+// IsTemplateFormat returns true if the format refers to fields of objects instead of output data.
+func IsTemplateFormat(outputFlag string) bool {
+	return strings.HasPrefix(outputFlag, CustomColumnsPrefix) || strings.HasPrefix(outputFlag, GoTemplatePrefix)
+}
+
+// toUnstructured converts objects to an unstructured object or list with keys from "json" struct tags
+// so that templates and json paths refer to fields the same way as kubectl.
+func toUnstructured(objects interface{}) (runtime.Object, error) {
+	b, err := json.Marshal(objects)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	if err := json.Unmarshal(b, &generic); err != nil {
+		return nil, err
+	}
+	switch generic := generic.(type) {
+	case nil:
+		return &unstructured.UnstructuredList{}, nil
+	case []interface{}:
+		return newUnstructuredList(nil, generic)
+	case map[string]interface{}:
+		if items, ok := generic["items"].([]interface{}); ok {
+			return newUnstructuredList(generic, items)
+		}
+		return &unstructured.Unstructured{Object: generic}, nil
+	default:
+		return nil, fmt.Errorf("unexpected objects to print: %T", objects)
+	}
+}
+
+func newUnstructuredList(object map[string]interface{}, items []interface{}) (*unstructured.UnstructuredList, error) {
+	list := &unstructured.UnstructuredList{Object: object}
+	for _, item := range items {
+		itemObject, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected list item to print: %T", item)
+		}
+		list.Items = append(list.Items, unstructured.Unstructured{Object: itemObject})
+	}
+	return list, nil
+}
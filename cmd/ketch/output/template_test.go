@@ -0,0 +1,97 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testObject struct {
+	Metadata testMetadata `json:"metadata"`
+	Spec     testSpec     `json:"spec"`
+}
+
+type testMetadata struct {
+	Name string `json:"name"`
+}
+
+type testSpec struct {
+	Images []string `json:"images,omitempty"`
+}
+
+type testObjectList struct {
+	Items []testObject `json:"items"`
+}
+
+func TestWriteWithObjects(t *testing.T) {
+	objects := testObjectList{
+		Items: []testObject{
+			{Metadata: testMetadata{Name: "app-a"}, Spec: testSpec{Images: []string{"nginx:1", "nginx:2"}}},
+			{Metadata: testMetadata{Name: "app-b"}},
+		},
+	}
+	tests := []struct {
+		description string
+		objects     interface{}
+		format      string
+		expected    string
+		wantErr     bool
+	}{
+		{
+			description: "custom columns of a list",
+			objects:     objects,
+			format:      "custom-columns=NAME:.metadata.name,IMAGE:.spec.images[0]",
+			expected:    "NAME    IMAGE\napp-a   nginx:1\napp-b   <none>\n",
+		},
+		{
+			description: "custom columns of an object with relaxed paths",
+			objects:     objects.Items[0],
+			format:      "custom-columns=NAME:metadata.name,IMAGES:{.spec.images[*]}",
+			expected:    "NAME    IMAGES\napp-a   nginx:1,nginx:2\n",
+		},
+		{
+			description: "custom columns without columns",
+			objects:     objects,
+			format:      "custom-columns=",
+			wantErr:     true,
+		},
+		{
+			description: "custom columns without path",
+			objects:     objects,
+			format:      "custom-columns=NAME",
+			wantErr:     true,
+		},
+		{
+			description: "go template",
+			objects:     objects,
+			format:      `go-template={{range .items}}{{.metadata.name}}{{"\n"}}{{end}}`,
+			expected:    "app-a\napp-b\n",
+		},
+		{
+			description: "invalid go template",
+			objects:     objects,
+			format:      "go-template={{range .items}",
+			wantErr:     true,
+		},
+		{
+			description: "other formats write data",
+			objects:     objects,
+			format:      Name,
+			expected:    "data\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			data := []struct{ Name string }{{Name: "data"}}
+			err := WriteWithObjects(data, tt.objects, out, tt.format)
+			if tt.wantErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.expected, out.String())
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/spf13/cobra"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/theketchio/ketch/cmd/ketch/configuration"
@@ -20,6 +21,8 @@ type config interface {
 	KubernetesClient() kubernetes.Interface
 	// DynamicClient returns kubernetes dynamic client. It's used to work with CRDs for which we don't have go types like ClusterIssuer.
 	DynamicClient() dynamic.Interface
+	// RestConfig returns a config of kubernetes clients. It's used to execute commands in pods.
+	RestConfig() *rest.Config
 }
 
 // RootCmd represents the base command when called without any subcommands
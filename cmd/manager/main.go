@@ -34,8 +34,11 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/canary"
 	"github.com/theketchio/ketch/internal/chart"
 	"github.com/theketchio/ketch/internal/controllers"
+	"github.com/theketchio/ketch/internal/idle"
+	"github.com/theketchio/ketch/internal/prometheus"
 	"github.com/theketchio/ketch/internal/templates"
 	// +kubebuilder:scaffold:imports
 )
@@ -117,6 +120,7 @@ func main() {
 	eventBroadcaster.StartLogging(func(format string, args ...interface{}) { logg.Info(fmt.Sprintf(format, args...)) })
 	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: clientSet.CoreV1().Events("")})
 
+	prometheusClient := prometheus.NewClient()
 	if err = (&controllers.AppReconciler{
 		TemplateReader: storage,
 		Client:         mgr.GetClient(),
@@ -131,7 +135,9 @@ func main() {
 			Component: "ketch-controller",
 		},
 		),
-		Config: ctrl.GetConfigOrDie(),
+		Config:         ctrl.GetConfigOrDie(),
+		CanaryAnalyzer: canary.NewAnalyzer(prometheusClient),
+		IdleDetector:   idle.NewDetector(prometheusClient),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "App")
 		os.Exit(1)
@@ -171,6 +177,10 @@ func main() {
 			setupLog.Error(err, "unable to create webhook", "webhook", "Job")
 			os.Exit(1)
 		}
+		if err = (&ketchv1.App{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "App")
+			os.Exit(1)
+		}
 	}
 	// +kubebuilder:scaffold:builder
 
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"math"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -29,12 +30,20 @@ import (
 	"k8s.io/client-go/tools/record"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
 	ShipaCloudDomain     = "shipa.cloud"
 	DefaultNumberOfUnits = 1
+
+	// DeploymentHistoryLimit is the maximum number of deployments kept in AppStatus.History.
+	DeploymentHistoryLimit = 10
+
+	// DefaultCanaryFailureLimit is the number of consecutive canary analyses which can fail
+	// before the canary deployment is rolled back, unless CanaryAnalysisSpec.FailureLimit is set.
+	DefaultCanaryFailureLimit = 3
 )
 
 // Env represents an environment variable present in an application.
@@ -44,7 +53,41 @@ type Env struct {
 	Name string `json:"name"`
 
 	// Value of the environment variable.
-	Value string `json:"value"`
+	Value string `json:"value,omitempty"`
+
+	// ValueFrom is a source for the environment variable's value. Value must be empty when ValueFrom is set.
+	ValueFrom *EnvVarSource `json:"valueFrom,omitempty"`
+}
+
+// EnvVarSource represents a source for the value of an environment variable.
+type EnvVarSource struct {
+	// SecretKeyRef selects a key of a secret in the framework's namespace.
+	SecretKeyRef *SecretKeySelector `json:"secretKeyRef,omitempty"`
+}
+
+// SecretKeySelector selects a key of a secret.
+type SecretKeySelector struct {
+	// +kubebuilder:validation:MinLength=1
+	// Name of the secret.
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:MinLength=1
+	// Key of the secret to select.
+	Key string `json:"key"`
+}
+
+// EnvFromSource represents a source of a set of environment variables.
+type EnvFromSource struct {
+	// ConfigMapRef selects a config map in the framework's namespace.
+	ConfigMapRef *ConfigMapEnvSource `json:"configMapRef,omitempty"`
+}
+
+// ConfigMapEnvSource selects a config map to populate environment variables with.
+// Every key of the config map becomes an environment variable.
+type ConfigMapEnvSource struct {
+	// +kubebuilder:validation:MinLength=1
+	// Name of the config map.
+	Name string `json:"name"`
 }
 
 // Label represents an environment variable present in an application.
@@ -74,6 +117,28 @@ type Cname struct {
 // then 3 of 10 incoming requests will be sent to the first deployment (approximately).
 type RoutingSettings struct {
 	Weight uint8 `json:"weight"`
+	// Match is a list of rules, a request matching any of the rules is sent to the current deployment regardless of its weight.
+	// Supported by istio and traefik ingress controllers.
+	Match []RouteMatch `json:"match,omitempty"`
+}
+
+// RouteMatchType is a part of a request checked by a RouteMatch.
+type RouteMatchType string
+
+const (
+	RouteMatchHeader RouteMatchType = "header"
+	RouteMatchCookie RouteMatchType = "cookie"
+	RouteMatchQuery  RouteMatchType = "query"
+)
+
+// RouteMatch matches a request with a header, cookie or query parameter with the given value.
+type RouteMatch struct {
+	// +kubebuilder:validation:Enum=header;cookie;query
+	Type RouteMatchType `json:"type"`
+	// Name of a header, cookie or query parameter.
+	Name string `json:"name"`
+	// Value is an exact value of a header, cookie or query parameter.
+	Value string `json:"value"`
 }
 
 // ProcessSpec is a specification of the desired behavior of a process.
@@ -99,6 +164,56 @@ type ProcessSpec struct {
 
 	// Security options the process should run with.
 	SecurityContext *v1.SecurityContext `json:"securityContext,omitempty"`
+
+	// Autoscaling configures a horizontal pod autoscaler for the process.
+	// When it's set, the number of units is managed by the autoscaler within its bounds.
+	Autoscaling *AutoscalingSpec `json:"autoscaling,omitempty"`
+}
+
+// AutoscalingSpec describes how a process is scaled horizontally.
+type AutoscalingSpec struct {
+	// +kubebuilder:validation:Minimum=1
+	// MinUnits is the lower limit for the number of units.
+	MinUnits int32 `json:"minUnits"`
+
+	// +kubebuilder:validation:Minimum=1
+	// MaxUnits is the upper limit for the number of units, it can't be less than MinUnits.
+	MaxUnits int32 `json:"maxUnits"`
+
+	// +kubebuilder:validation:Minimum=1
+	// TargetCPUUtilization is the target average CPU utilization as a percentage of the requested CPU.
+	TargetCPUUtilization *int32 `json:"targetCPUUtilization,omitempty"`
+
+	// +kubebuilder:validation:Minimum=1
+	// TargetMemoryUtilization is the target average memory utilization as a percentage of the requested memory.
+	TargetMemoryUtilization *int32 `json:"targetMemoryUtilization,omitempty"`
+
+	// CustomMetric is a metric describing each unit of the process, like requests per second.
+	CustomMetric *CustomMetricSpec `json:"customMetric,omitempty"`
+}
+
+// CustomMetricSpec specifies a target value of a custom metric averaged across all units of a process.
+type CustomMetricSpec struct {
+	// +kubebuilder:validation:MinLength=1
+	// Name of the metric.
+	Name string `json:"name"`
+
+	// TargetAverageValue is the target value of the metric averaged across all units.
+	TargetAverageValue resource.Quantity `json:"targetAverageValue"`
+}
+
+// clampUnits returns the number of units within the autoscaler's bounds.
+func (a *AutoscalingSpec) clampUnits(units int) int {
+	if a == nil {
+		return units
+	}
+	if units < int(a.MinUnits) {
+		return int(a.MinUnits)
+	}
+	if units > int(a.MaxUnits) {
+		return int(a.MaxUnits)
+	}
+	return units
 }
 
 type DeploymentVersion int
@@ -158,6 +273,16 @@ type AppStatus struct {
 	Conditions []Condition `json:"conditions,omitempty"`
 
 	Framework *v1.ObjectReference `json:"framework,omitempty"`
+
+	// History contains the most recent deployments of the application ordered by version.
+	// It is used to roll the application back to one of its previous deployments.
+	History []AppDeploymentSpec `json:"history,omitempty"`
+
+	// ObservedGeneration is the generation of the spec the application was last reconciled with successfully.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// LastIdleCheckTime holds time when the traffic of an application with an idle policy was last checked.
+	LastIdleCheckTime *metav1.Time `json:"lastIdleCheckTime,omitempty"`
 }
 
 // CanarySpec represents configuration for a canary deployment.
@@ -181,6 +306,111 @@ type CanarySpec struct {
 	Started *metav1.Time `json:"started,omitempty"`
 	// Target map of processes and target units value
 	Target map[string]uint16 `json:"target,omitempty"`
+	// Paused stops the canary deployment at its current step until it is resumed.
+	Paused bool `json:"paused,omitempty"`
+	// Aborted is set to abort the canary deployment.
+	// Ketch controller rolls the traffic back to the primary deployment once it is set.
+	Aborted bool `json:"aborted,omitempty"`
+	// Promoted is set to finish the canary deployment immediately.
+	// Ketch controller routes all traffic to the canary deployment once it is set.
+	Promoted bool `json:"promoted,omitempty"`
+	// Analysis contains metrics checked before each step of the canary deployment.
+	Analysis *CanaryAnalysisSpec `json:"analysis,omitempty"`
+	// AnalysisFailures is the number of consecutive canary analyses which couldn't be evaluated.
+	AnalysisFailures int `json:"analysisFailures,omitempty"`
+}
+
+// CanaryAnalysisSpec describes metrics checked before each step of a canary deployment.
+// The canary deployment is rolled back once any of the metrics is out of its bounds.
+type CanaryAnalysisSpec struct {
+	// PrometheusURL is the address of a Prometheus compatible HTTP API, e.g. http://prometheus.istio-system:9090.
+	PrometheusURL string `json:"prometheusURL"`
+	// Metrics is a list of metrics to check.
+	// +kubebuilder:validation:MinItems=1
+	Metrics []CanaryMetric `json:"metrics"`
+	// NoData is how a metric which query returns no data is treated, either pass or fail. Defaults to pass
+	// because a canary deployment usually receives no traffic before its first step.
+	// +kubebuilder:validation:Enum=pass;fail
+	NoData CanaryNoDataPolicy `json:"noData,omitempty"`
+	// FailureLimit is the number of consecutive analyses which can't be evaluated, e.g. because the API is unavailable,
+	// before the canary deployment is rolled back. Defaults to DefaultCanaryFailureLimit.
+	// +kubebuilder:validation:Minimum=1
+	FailureLimit int `json:"failureLimit,omitempty"`
+}
+
+// CanaryNoDataPolicy describes how a canary metric which query returns no data is treated.
+type CanaryNoDataPolicy string
+
+const (
+	// CanaryNoDataPass treats a metric without data as within its bounds.
+	CanaryNoDataPass CanaryNoDataPolicy = "pass"
+	// CanaryNoDataFail treats a metric without data as out of its bounds, so the canary deployment is rolled back.
+	CanaryNoDataFail CanaryNoDataPolicy = "fail"
+)
+
+func (a *CanaryAnalysisSpec) failureLimit() int {
+	if a == nil || a.FailureLimit <= 0 {
+		return DefaultCanaryFailureLimit
+	}
+	return a.FailureLimit
+}
+
+// CanaryMetric is a Prometheus query with bounds for its result.
+type CanaryMetric struct {
+	// Name of the metric, e.g. error-rate.
+	Name string `json:"name"`
+	// Query is a PromQL query returning a single value. It is a go template with the following fields:
+	// .App is the value of the destination_app label of the application,
+	// .Version is the version of the canary deployment,
+	// .Interval is the step interval of the canary deployment, e.g. 300s.
+	Query string `json:"query"`
+	// Min is the minimum accepted value of the query result.
+	Min *resource.Quantity `json:"min,omitempty"`
+	// Max is the maximum accepted value of the query result.
+	Max *resource.Quantity `json:"max,omitempty"`
+}
+
+// BlueGreenSpec contains a configuration of a blue-green deployment.
+// A new deployment is brought up next to the current one at full units without any traffic,
+// it is accessible via a preview cname until it gets all traffic at once on promotion.
+type BlueGreenSpec struct {
+	// Active shows if blue-green deployment is active for this application.
+	Active bool `json:"active,omitempty"`
+	// GracePeriod is how long the previous deployment is kept after promotion,
+	// so the traffic can be switched back to it instantly.
+	GracePeriod time.Duration `json:"gracePeriod,omitempty"`
+	// PromotedAt holds time when the traffic was switched to the new deployment.
+	PromotedAt *metav1.Time `json:"promotedAt,omitempty"`
+}
+
+// IdlePolicySpec describes when an application is put to sleep because of inactivity.
+// A sleeping application has all its processes scaled to 0 units until it is woken up.
+type IdlePolicySpec struct {
+	// IdleMinutes is how many minutes the application can receive no ingress traffic before it is put to sleep.
+	// +kubebuilder:validation:Minimum=1
+	IdleMinutes int `json:"idleMinutes"`
+	// PrometheusURL is the address of a Prometheus compatible HTTP API, e.g. http://prometheus.istio-system:9090.
+	// +kubebuilder:validation:MinLength=1
+	PrometheusURL string `json:"prometheusURL"`
+	// Query is a PromQL query returning the number of requests received by the application.
+	// It is a go template with the following fields:
+	// .App is the value of the destination_app label of the application,
+	// .Window is the idle window, e.g. 1800s.
+	// If not set, istio_requests_total is used, which requires the framework's ingress controller to be istio.
+	Query string `json:"query,omitempty"`
+	// Asleep shows if the application is put to sleep.
+	Asleep bool `json:"asleep,omitempty"`
+	// LastTransitionTime holds time when the application was put to sleep or woken up.
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+	// Units holds the number of units of each process before the application was put to sleep.
+	Units []ProcessUnits `json:"units,omitempty"`
+}
+
+// ProcessUnits is the number of units of a process of a deployment.
+type ProcessUnits struct {
+	Version DeploymentVersion `json:"version"`
+	Process string            `json:"process"`
+	Units   *int              `json:"units,omitempty"`
 }
 
 // AppSpec defines the desired state of App.
@@ -200,6 +430,12 @@ type AppSpec struct {
 	// Canary contains a configuration which will be required for canary deployments.
 	Canary CanarySpec `json:"canary,omitempty"`
 
+	// BlueGreen contains a configuration which will be required for blue-green deployments.
+	BlueGreen BlueGreenSpec `json:"blueGreen,omitempty"`
+
+	// IdlePolicy puts the application to sleep when it receives no ingress traffic.
+	IdlePolicy *IdlePolicySpec `json:"idlePolicy,omitempty"`
+
 	// Deployments is a list of running deployments.
 	Deployments []AppDeploymentSpec `json:"deployments"`
 
@@ -209,6 +445,9 @@ type AppSpec struct {
 	// List of environment variables of the application.
 	Env []Env `json:"env,omitempty"`
 
+	// EnvFrom is a list of sources to populate environment variables of the application.
+	EnvFrom []EnvFromSource `json:"envFrom,omitempty"`
+
 	// Framework is a name of a Framework used to run the application.
 	// +kubebuilder:validation:MinLength=1
 	Framework string `json:"framework"`
@@ -282,6 +521,16 @@ func (s *AppDeploymentSpec) setUnits(process string, units int) error {
 	return ErrProcessNotFound
 }
 
+// autoscaling returns the autoscaling spec of the process or nil if the process isn't autoscaled.
+func (s *AppDeploymentSpec) autoscaling(process string) *AutoscalingSpec {
+	for _, processSpec := range s.Processes {
+		if processSpec.Name == process {
+			return processSpec.Autoscaling
+		}
+	}
+	return nil
+}
+
 func (s *AppDeploymentSpec) setUnitsForAllProcess(units int) {
 	for i := range s.Processes {
 		s.Processes[i].Units = &units
@@ -313,12 +562,16 @@ func (app *App) SetUnits(selector Selector, units int) error {
 // SetEnvs extends the current list of environment variables with the provided list.
 // If the current list has an env variable from the provided list, the env variable will be updated with a new value.
 func (app *App) SetEnvs(envs []Env) {
+	app.Spec.Env = setEnvs(app.Spec.Env, envs)
+}
+
+func setEnvs(current []Env, envs []Env) []Env {
 	names := make(map[string]Env, len(envs))
 	for _, env := range envs {
 		names[env.Name] = env
 	}
 	newEnvs := make([]Env, 0, len(envs))
-	for _, env := range app.Spec.Env {
+	for _, env := range current {
 		if newEnv, hasNewValue := names[env.Name]; hasNewValue {
 			newEnvs = append(newEnvs, newEnv)
 			delete(names, env.Name)
@@ -326,10 +579,103 @@ func (app *App) SetEnvs(envs []Env) {
 		}
 		newEnvs = append(newEnvs, env)
 	}
-	for _, env := range names {
-		newEnvs = append(newEnvs, env)
+	// keep the order of the provided list for new env variables.
+	for _, env := range envs {
+		if _, isNew := names[env.Name]; isNew {
+			newEnvs = append(newEnvs, env)
+			delete(names, env.Name)
+		}
+	}
+	return newEnvs
+}
+
+// SetProcessEnvs extends environment variables of the specified processes with the provided list.
+// Env variables of a process take precedence over env variables of the app with the same name.
+func (app *App) SetProcessEnvs(selector Selector, envs []Env) error {
+	return app.updateProcesses(selector, func(process *ProcessSpec) {
+		process.Env = setEnvs(process.Env, envs)
+	})
+}
+
+// UnsetProcessEnvs unsets environment variables of the specified processes.
+func (app *App) UnsetProcessEnvs(selector Selector, names []string) error {
+	return app.updateProcesses(selector, func(process *ProcessSpec) {
+		process.Env = unsetEnvs(process.Env, names)
+	})
+}
+
+// SetResources sets compute resources of the specified processes.
+// Resources that are not in the provided lists are kept unchanged.
+func (app *App) SetResources(selector Selector, requests v1.ResourceList, limits v1.ResourceList) error {
+	return app.updateProcesses(selector, func(process *ProcessSpec) {
+		if process.Resources == nil {
+			process.Resources = &v1.ResourceRequirements{}
+		}
+		for name, quantity := range requests {
+			if process.Resources.Requests == nil {
+				process.Resources.Requests = v1.ResourceList{}
+			}
+			process.Resources.Requests[name] = quantity.DeepCopy()
+		}
+		for name, quantity := range limits {
+			if process.Resources.Limits == nil {
+				process.Resources.Limits = v1.ResourceList{}
+			}
+			process.Resources.Limits[name] = quantity.DeepCopy()
+		}
+	})
+}
+
+// DesiredUnits returns the number of units the process is deployed with, DefaultNumberOfUnits if Units isn't set.
+func (p ProcessSpec) DesiredUnits() int {
+	if p.Units == nil {
+		return DefaultNumberOfUnits
 	}
-	app.Spec.Env = newEnvs
+	return *p.Units
+}
+
+// SetAutoscaling sets autoscaling of the specified processes, nil disables autoscaling.
+// Units of the processes are kept within the autoscaler's bounds.
+func (app *App) SetAutoscaling(selector Selector, autoscaling *AutoscalingSpec) error {
+	return app.updateProcesses(selector, func(process *ProcessSpec) {
+		process.Autoscaling = autoscaling.DeepCopy()
+		if autoscaling == nil {
+			return
+		}
+		units := autoscaling.clampUnits(process.DesiredUnits())
+		process.Units = &units
+	})
+}
+
+// updateProcesses calls the update function for every process specified by the selector.
+func (app *App) updateProcesses(selector Selector, update func(process *ProcessSpec)) error {
+	deploymentFound := false
+	for _, deploymentSpec := range app.Spec.Deployments {
+		if selector.DeploymentVersion != nil && *selector.DeploymentVersion != deploymentSpec.Version {
+			continue
+		}
+		deploymentFound = true
+		processFound := false
+		for i, processSpec := range deploymentSpec.Processes {
+			if selector.Process != nil && *selector.Process != processSpec.Name {
+				continue
+			}
+			processFound = true
+			update(&deploymentSpec.Processes[i])
+		}
+		if selector.Process != nil && !processFound {
+			return ErrProcessNotFound
+		}
+	}
+	if selector.DeploymentVersion != nil && !deploymentFound {
+		return ErrDeploymentNotFound
+	}
+	return nil
+}
+
+// EnvSecretName returns a name of the secret which stores values of the app's secret env variables.
+func (app *App) EnvSecretName() string {
+	return fmt.Sprintf("%s-env", app.Name)
 }
 
 // Envs returns values of the asked env variables.
@@ -354,17 +700,32 @@ func (app *App) Envs(names []string) map[string]string {
 
 // UnsetEnvs unsets environment values.
 func (app *App) UnsetEnvs(envs []string) {
+	app.Spec.Env = unsetEnvs(app.Spec.Env, envs)
+}
+
+func unsetEnvs(current []Env, envs []string) []Env {
 	names := make(map[string]struct{}, len(envs))
 	for _, name := range envs {
 		names[name] = struct{}{}
 	}
 	var newEnvs []Env
-	for _, env := range app.Spec.Env {
+	for _, env := range current {
 		if _, remove := names[env.Name]; !remove {
 			newEnvs = append(newEnvs, env)
 		}
 	}
-	app.Spec.Env = newEnvs
+	return newEnvs
+}
+
+// AddEnvFromConfigMap adds the config map to the sources of the app's environment variables
+// unless it's already there.
+func (app *App) AddEnvFromConfigMap(name string) {
+	for _, source := range app.Spec.EnvFrom {
+		if source.ConfigMapRef != nil && source.ConfigMapRef.Name == name {
+			return
+		}
+	}
+	app.Spec.EnvFrom = append(app.Spec.EnvFrom, EnvFromSource{ConfigMapRef: &ConfigMapEnvSource{Name: name}})
 }
 
 // Stop stops processes specified by the selector.
@@ -372,6 +733,112 @@ func (app *App) Stop(selector Selector) error {
 	return app.SetUnits(selector, 0)
 }
 
+// IdleWindow returns how long the application can receive no traffic before it is put to sleep.
+func (app *App) IdleWindow() time.Duration {
+	if app.Spec.IdlePolicy == nil {
+		return 0
+	}
+	return time.Duration(app.Spec.IdlePolicy.IdleMinutes) * time.Minute
+}
+
+// IdleCheckLeft returns how long it is until the application's traffic has to be checked.
+// It returns 0 if the check is due and false if the application isn't checked at all:
+// it has no idle policy, it is asleep already or a canary or blue-green deployment is in progress.
+func (app *App) IdleCheckLeft(now metav1.Time) (time.Duration, bool) {
+	policy := app.Spec.IdlePolicy
+	if policy == nil || policy.Asleep || app.Spec.Canary.Active || app.Spec.BlueGreen.Active || len(app.Spec.Deployments) == 0 {
+		return 0, false
+	}
+	// an application which was just woken up or checked gets the whole window to receive traffic.
+	last := policy.LastTransitionTime
+	if checked := app.Status.LastIdleCheckTime; checked != nil && (last == nil || checked.After(last.Time)) {
+		last = checked
+	}
+	if last == nil {
+		return 0, true
+	}
+	left := last.Add(app.IdleWindow()).Sub(now.Time)
+	if left < 0 {
+		return 0, true
+	}
+	return left, true
+}
+
+// IsReconciled returns true if the current spec of the application was reconciled successfully.
+func (app *App) IsReconciled() bool {
+	condition := app.Status.Condition(Scheduled)
+	return condition != nil && condition.Status == v1.ConditionTrue && app.Status.ObservedGeneration == app.Generation
+}
+
+// Sleep stops all processes of the application and records their units so that Wake can restore them.
+func (app *App) Sleep(now metav1.Time, recorder record.EventRecorder) {
+	policy := app.Spec.IdlePolicy
+	if policy == nil || policy.Asleep {
+		return
+	}
+	policy.Units = nil
+	for _, deployment := range app.Spec.Deployments {
+		for _, process := range deployment.Processes {
+			units := ProcessUnits{Version: deployment.Version, Process: process.Name}
+			if process.Units != nil {
+				value := *process.Units
+				units.Units = &value
+			}
+			policy.Units = append(policy.Units, units)
+		}
+	}
+	_ = app.Stop(Selector{})
+	policy.Asleep = true
+	policy.LastTransitionTime = &now
+	recorder.Eventf(app, v1.EventTypeNormal, AppSleep, "%s - App %s received no traffic for %d minutes - %s", AppSleep, app.Name, policy.IdleMinutes, AppSleepDesc)
+}
+
+// Wake restores units of the application's processes recorded when it was put to sleep.
+// Processes of deployments added afterwards are started with 1 unit.
+func (app *App) Wake(now metav1.Time) error {
+	policy := app.Spec.IdlePolicy
+	if policy == nil || !policy.Asleep {
+		return ErrAppNotAsleep
+	}
+	for i, deployment := range app.Spec.Deployments {
+		for j, process := range deployment.Processes {
+			units, found := policy.units(deployment.Version, process.Name)
+			if !found {
+				value := 1
+				units = &value
+			}
+			app.Spec.Deployments[i].Processes[j].Units = units
+		}
+	}
+	policy.awake(now)
+	return nil
+}
+
+// IsAsleep returns true if the application is put to sleep by its idle policy.
+func (app *App) IsAsleep() bool {
+	return app.Spec.IdlePolicy != nil && app.Spec.IdlePolicy.Asleep
+}
+
+// awake clears the sleep state of the policy, units of the processes are set by the caller.
+func (p *IdlePolicySpec) awake(now metav1.Time) {
+	p.Asleep = false
+	p.Units = nil
+	p.LastTransitionTime = &now
+}
+
+func (p *IdlePolicySpec) units(version DeploymentVersion, process string) (*int, bool) {
+	for _, units := range p.Units {
+		if units.Version == version && units.Process == process {
+			if units.Units == nil {
+				return nil, true
+			}
+			value := *units.Units
+			return &value, true
+		}
+	}
+	return nil, false
+}
+
 // Start starts processes specified by the selector.
 // We start a process by setting its unit quantity to 1.
 // If a process has running units, nothing will be changed.
@@ -401,6 +868,10 @@ func (app *App) Start(selector Selector) error {
 	if selector.DeploymentVersion != nil && !deploymentFound {
 		return ErrDeploymentNotFound
 	}
+	// a started application isn't asleep anymore, so it is checked for idleness again.
+	if app.IsAsleep() {
+		app.Spec.IdlePolicy.awake(metav1.Now())
+	}
 	return nil
 }
 
@@ -437,6 +908,17 @@ func (app *App) DefaultCname(framework *Framework) *string {
 	return &url
 }
 
+// PreviewCname returns a cname to access the preview deployment of a blue-green deployment.
+// A preview cname uses the following format: <app name>-preview.<Framework's ServiceEndpoint>.shipa.cloud.
+func (app *App) PreviewCname(framework *Framework) *string {
+	defaultCname := app.DefaultCname(framework)
+	if defaultCname == nil {
+		return nil
+	}
+	url := fmt.Sprintf("%s-preview%s", app.Name, strings.TrimPrefix(*defaultCname, app.Name))
+	return &url
+}
+
 // Units returns a total number units.
 func (app *App) Units() int {
 	units := 0
@@ -538,6 +1020,10 @@ func (app *App) DoCanary(now metav1.Time, logger logr.Logger, recorder record.Ev
 		return errors.New("no canary deployment found")
 	}
 
+	if app.Spec.Canary.Paused {
+		return nil
+	}
+
 	if app.Spec.Canary.NextScheduledTime == nil {
 		failEvent := newCanaryEvent(app, CanaryNoScheduledSteps, CanaryNoScheduledStepsDesc)
 		recorder.AnnotatedEventf(app, failEvent.Annotations, v1.EventTypeWarning, failEvent.Name, failEvent.Message())
@@ -560,6 +1046,9 @@ func (app *App) DoCanary(now metav1.Time, logger logr.Logger, recorder record.Ev
 			// scale units based on weight and process target
 			for processName, target := range app.Spec.Canary.Target {
 				p1Units, p2Units := getUpdatedUnits(app.Spec.Deployments[0].RoutingSettings.Weight, target)
+				// units of autoscaled processes stay within the autoscalers' bounds.
+				p1Units = app.Spec.Deployments[0].autoscaling(processName).clampUnits(p1Units)
+				p2Units = app.Spec.Deployments[1].autoscaling(processName).clampUnits(p2Units)
 				// might be fine to ignore these errors
 				if err := app.Spec.Deployments[0].setUnits(processName, p1Units); err != nil {
 					logger.Info("the process: %s is not present in the previous deployment\n", processName)
@@ -575,7 +1064,7 @@ func (app *App) DoCanary(now metav1.Time, logger logr.Logger, recorder record.Ev
 			// if a process in the updated deployment isn't found in target create 1 unit
 			for _, process := range app.Spec.Deployments[1].Processes {
 				if _, found := app.Spec.Canary.Target[process.Name]; !found {
-					_ = app.Spec.Deployments[1].setUnits(process.Name, 1)
+					_ = app.Spec.Deployments[1].setUnits(process.Name, process.Autoscaling.clampUnits(1))
 				}
 			}
 			// for previous deployment, any processes not in target will be terminated by the end of the canary deployment
@@ -586,27 +1075,10 @@ func (app *App) DoCanary(now metav1.Time, logger logr.Logger, recorder record.Ev
 
 		// check if the canary weight is exceeding 100% of traffic
 		if app.Spec.Deployments[1].RoutingSettings.Weight >= 100 || app.Spec.Canary.CurrentStep == app.Spec.Canary.Steps {
-			// canary is finished, update new deployment to the target values
-			for i, process := range app.Spec.Deployments[1].Processes {
-				if target, found := app.Spec.Canary.Target[process.Name]; found {
-					finalUnits := int(target)
-					process.Units = &finalUnits
-					app.Spec.Deployments[1].Processes[i] = process
-				}
-			}
-
-			// we need to set weight of the target deployment to 100
-			// because there is a chance that on the last step weight is not equal to 100 (e.g. steps=3, step-weight=33)
-			app.Spec.Deployments[1].RoutingSettings.Weight = 100
-
-			app.Spec.Canary.Active = false
-			app.Spec.Canary.CurrentStep = app.Spec.Canary.Steps
-			app.Spec.Canary.NextScheduledTime = nil
+			app.finishCanary()
 
 			eventFinished := newCanaryEvent(app, CanaryFinished, CanaryFinishedDesc)
 			recorder.AnnotatedEventf(app, eventFinished.Annotations, v1.EventTypeNormal, eventFinished.Name, eventFinished.Message())
-
-			app.Spec.Deployments = []AppDeploymentSpec{app.Spec.Deployments[1]}
 		}
 		app.Spec.Canary.CurrentStep++
 	}
@@ -614,12 +1086,297 @@ func (app *App) DoCanary(now metav1.Time, logger logr.Logger, recorder record.Ev
 	return nil
 }
 
-// DoRollback performs rollback
+// finishCanary routes all traffic to the canary deployment, scales it to the target units
+// and removes the primary deployment.
+func (app *App) finishCanary() {
+	// canary is finished, update new deployment to the target values
+	for i, process := range app.Spec.Deployments[1].Processes {
+		if target, found := app.Spec.Canary.Target[process.Name]; found {
+			finalUnits := process.Autoscaling.clampUnits(int(target))
+			process.Units = &finalUnits
+			app.Spec.Deployments[1].Processes[i] = process
+		}
+	}
+
+	// we need to set weight of the target deployment to 100
+	// because there is a chance that on the last step weight is not equal to 100 (e.g. steps=3, step-weight=33)
+	app.Spec.Deployments[1].RoutingSettings.Weight = 100
+	app.Spec.Deployments[1].RoutingSettings.Match = nil
+
+	app.Spec.Canary.Active = false
+	app.Spec.Canary.Paused = false
+	app.Spec.Canary.Aborted = false
+	app.Spec.Canary.Promoted = false
+	app.Spec.Canary.CurrentStep = app.Spec.Canary.Steps
+	app.Spec.Canary.NextScheduledTime = nil
+
+	app.Spec.Deployments = []AppDeploymentSpec{app.Spec.Deployments[1]}
+}
+
+// DoRollback performs rollback.
+// It routes all traffic back to the primary deployment and removes the canary deployment.
+// Units of the primary deployment scaled down by the canary deployment are restored from the history.
 func (app *App) DoRollback() {
-	// we need to rollback all weight to the primary deployment
-	app.Spec.Deployments[0].RoutingSettings.Weight = 100
-	app.Spec.Deployments[1].RoutingSettings.Weight = 0
+	primary := app.Spec.Deployments[0]
+	primary.RoutingSettings.Weight = 100
+	for _, recorded := range app.Status.History {
+		if recorded.Version != primary.Version {
+			continue
+		}
+		for i, process := range primary.Processes {
+			for _, recordedProcess := range recorded.Processes {
+				if recordedProcess.Name == process.Name && recordedProcess.Units != nil {
+					units := *recordedProcess.Units
+					primary.Processes[i].Units = &units
+				}
+			}
+		}
+	}
+	app.Spec.Deployments = []AppDeploymentSpec{primary}
 	app.Spec.Canary.Active = false
+	app.Spec.Canary.Paused = false
+	app.Spec.Canary.Aborted = false
+	app.Spec.Canary.Promoted = false
+	app.Spec.Canary.NextScheduledTime = nil
+}
+
+// DoAbort rolls back a canary deployment requested to be aborted.
+func (app *App) DoAbort(recorder record.EventRecorder) {
+	event := newCanaryEvent(app, CanaryAborted, CanaryAbortedDesc)
+	app.DoRollback()
+	recorder.AnnotatedEventf(app, event.Annotations, v1.EventTypeWarning, event.Name, event.Message())
+}
+
+// DoPromote finishes a canary deployment requested to be promoted.
+func (app *App) DoPromote(recorder record.EventRecorder) {
+	app.finishCanary()
+	event := newCanaryEvent(app, CanaryFinished, CanaryFinishedDesc)
+	recorder.AnnotatedEventf(app, event.Annotations, v1.EventTypeNormal, event.Name, event.Message())
+}
+
+// DoAnalysisRollback rolls back a canary deployment which metric analysis has failed.
+func (app *App) DoAnalysisRollback(reason string, recorder record.EventRecorder) {
+	event := newCanaryEvent(app, CanaryAnalysisFailed, reason)
+	app.DoRollback()
+	recorder.AnnotatedEventf(app, event.Annotations, v1.EventTypeWarning, event.Name, event.Message())
+}
+
+// DoAnalysisFailure handles a canary analysis which couldn't be evaluated.
+// The step is retried one step interval later, the canary deployment is rolled back
+// once the analysis has failed FailureLimit times in a row.
+func (app *App) DoAnalysisFailure(analysisErr error, now metav1.Time, recorder record.EventRecorder) {
+	app.Spec.Canary.AnalysisFailures += 1
+	limit := app.Spec.Canary.Analysis.failureLimit()
+	if app.Spec.Canary.AnalysisFailures >= limit {
+		app.DoAnalysisRollback(fmt.Sprintf("analysis failed %d times in a row: %v", app.Spec.Canary.AnalysisFailures, analysisErr), recorder)
+		return
+	}
+	nextScheduledTime := metav1.NewTime(now.Add(app.Spec.Canary.StepTimeInteval))
+	app.Spec.Canary.NextScheduledTime = &nextScheduledTime
+	event := newCanaryEvent(app, CanaryAnalysisError, fmt.Sprintf("analysis failed (%d of %d): %v", app.Spec.Canary.AnalysisFailures, limit, analysisErr))
+	recorder.AnnotatedEventf(app, event.Annotations, v1.EventTypeWarning, event.Name, event.Message())
+}
+
+// IsCanaryStepDue returns true if the next step of the canary deployment has to be performed at the given time.
+func (app *App) IsCanaryStepDue(now metav1.Time) bool {
+	canary := app.Spec.Canary
+	if !canary.Active || canary.Paused || canary.NextScheduledTime == nil {
+		return false
+	}
+	return !canary.NextScheduledTime.After(now.Time)
+}
+
+// PauseCanary stops the canary deployment at its current step.
+func (app *App) PauseCanary() error {
+	if !app.Spec.Canary.Active {
+		return ErrCanaryNotActive
+	}
+	app.Spec.Canary.Paused = true
+	return nil
+}
+
+// ResumeCanary resumes a paused canary deployment.
+// The next step is scheduled one step interval after now.
+func (app *App) ResumeCanary(now metav1.Time) error {
+	if !app.Spec.Canary.Active {
+		return ErrCanaryNotActive
+	}
+	if !app.Spec.Canary.Paused {
+		return nil
+	}
+	app.Spec.Canary.Paused = false
+	nextScheduledTime := metav1.NewTime(now.Add(app.Spec.Canary.StepTimeInteval))
+	app.Spec.Canary.NextScheduledTime = &nextScheduledTime
+	return nil
+}
+
+// PromoteCanary marks the canary deployment as promoted.
+// Ketch controller finishes it immediately by routing all traffic to the canary deployment
+// and scaling it to the target units, see DoPromote.
+func (app *App) PromoteCanary() error {
+	if !app.Spec.Canary.Active {
+		return ErrCanaryNotActive
+	}
+	if len(app.Spec.Deployments) <= 1 {
+		return ErrDeploymentNotFound
+	}
+	app.Spec.Canary.Promoted = true
+	return nil
+}
+
+// AbortCanary marks the canary deployment as aborted.
+// Ketch controller rolls the traffic back to the primary deployment, see DoAbort.
+func (app *App) AbortCanary() error {
+	if !app.Spec.Canary.Active {
+		return ErrCanaryNotActive
+	}
+	app.Spec.Canary.Aborted = true
+	return nil
+}
+
+// PreviewDeployment returns the deployment of an active blue-green deployment which receives no traffic.
+// Before promotion it is the new deployment, after promotion it is the previous one kept for the grace period.
+func (app *App) PreviewDeployment() *AppDeploymentSpec {
+	if !app.Spec.BlueGreen.Active || len(app.Spec.Deployments) != 2 {
+		return nil
+	}
+	for i := range app.Spec.Deployments {
+		if app.Spec.Deployments[i].RoutingSettings.Weight == 0 {
+			return &app.Spec.Deployments[i]
+		}
+	}
+	return nil
+}
+
+// PromoteBlueGreen switches all traffic to the preview deployment at once.
+// Promoting again during the grace period switches the traffic back to the previous deployment.
+func (app *App) PromoteBlueGreen(now metav1.Time) error {
+	if !app.Spec.BlueGreen.Active {
+		return ErrBlueGreenNotActive
+	}
+	preview := app.PreviewDeployment()
+	if preview == nil {
+		return ErrDeploymentNotFound
+	}
+	previewVersion := preview.Version
+	for i := range app.Spec.Deployments {
+		if app.Spec.Deployments[i].Version == previewVersion {
+			app.Spec.Deployments[i].RoutingSettings.Weight = 100
+		} else {
+			app.Spec.Deployments[i].RoutingSettings.Weight = 0
+		}
+	}
+	app.Spec.BlueGreen.PromotedAt = &now
+	return nil
+}
+
+// BlueGreenGracePeriodLeft returns how long the previous deployment of a promoted blue-green deployment is still kept.
+func (app *App) BlueGreenGracePeriodLeft(now metav1.Time) time.Duration {
+	blueGreen := app.Spec.BlueGreen
+	if !blueGreen.Active || blueGreen.PromotedAt == nil {
+		return 0
+	}
+	left := blueGreen.PromotedAt.Add(blueGreen.GracePeriod).Sub(now.Time)
+	if left < 0 {
+		return 0
+	}
+	return left
+}
+
+// IsBlueGreenFinished returns true if the grace period of a promoted blue-green deployment is over.
+func (app *App) IsBlueGreenFinished(now metav1.Time) bool {
+	blueGreen := app.Spec.BlueGreen
+	return blueGreen.Active && blueGreen.PromotedAt != nil && app.BlueGreenGracePeriodLeft(now) == 0
+}
+
+// FinishBlueGreen removes the deployment which receives no traffic and resets the blue-green deployment.
+func (app *App) FinishBlueGreen(recorder record.EventRecorder) {
+	if preview := app.PreviewDeployment(); preview != nil {
+		previewVersion := preview.Version
+		deployments := make([]AppDeploymentSpec, 0, len(app.Spec.Deployments)-1)
+		for _, deployment := range app.Spec.Deployments {
+			if deployment.Version != previewVersion {
+				deployments = append(deployments, deployment)
+			}
+		}
+		app.Spec.Deployments = deployments
+	}
+	app.Spec.BlueGreen = BlueGreenSpec{}
+	if len(app.Spec.Deployments) > 0 {
+		version := app.Spec.Deployments[0].Version
+		recorder.Eventf(app, v1.EventTypeNormal, BlueGreenFinished, "%s - Blue-green deployment for app %s | version %d - %s", BlueGreenFinished, app.Name, version, BlueGreenFinishedDesc)
+	}
+}
+
+// RecordDeploymentHistory adds the deployment of the application to its history once it is fully rolled out
+// and keeps only the last DeploymentHistoryLimit of them.
+// A deployment is fully rolled out when it's the only one and no canary or blue-green deployment is in progress.
+// It is recorded once, so units changed later by a canary deployment or by putting the application to sleep
+// don't alter it.
+func (app *App) RecordDeploymentHistory() {
+	if len(app.Spec.Deployments) != 1 || app.Spec.Canary.Active || app.Spec.BlueGreen.Active {
+		return
+	}
+	if app.IsAsleep() {
+		return
+	}
+	deployment := app.Spec.Deployments[0]
+	for _, item := range app.Status.History {
+		if item.Version == deployment.Version {
+			return
+		}
+	}
+	app.Status.History = append(app.Status.History, *deployment.DeepCopy())
+	sort.Slice(app.Status.History, func(i, j int) bool {
+		return app.Status.History[i].Version < app.Status.History[j].Version
+	})
+	if len(app.Status.History) > DeploymentHistoryLimit {
+		app.Status.History = app.Status.History[len(app.Status.History)-DeploymentHistoryLimit:]
+	}
+}
+
+// Rollback replaces the deployments of the application with a deployment from its history.
+// The deployment gets a new version, so it is rolled out as any other new deployment.
+// If version is 0, the application is rolled back to the deployment preceding the current one.
+func (app *App) Rollback(version DeploymentVersion) error {
+	if app.Spec.Canary.Active {
+		return ErrCanaryInProgress
+	}
+	if app.Spec.BlueGreen.Active {
+		return ErrBlueGreenInProgress
+	}
+	var deployment *AppDeploymentSpec
+	if version == 0 {
+		current := DeploymentVersion(math.MaxInt32)
+		if len(app.Spec.Deployments) > 0 {
+			current = app.Spec.Deployments[len(app.Spec.Deployments)-1].Version
+		}
+		for i := len(app.Status.History) - 1; i >= 0; i-- {
+			if app.Status.History[i].Version < current {
+				deployment = &app.Status.History[i]
+				break
+			}
+		}
+		if deployment == nil {
+			return ErrNoPreviousDeployment
+		}
+	} else {
+		for i := range app.Status.History {
+			if app.Status.History[i].Version == version {
+				deployment = &app.Status.History[i]
+				break
+			}
+		}
+		if deployment == nil {
+			return ErrDeploymentNotFound
+		}
+	}
+	rollback := *deployment.DeepCopy()
+	app.Spec.DeploymentsCount += 1
+	rollback.Version = DeploymentVersion(app.Spec.DeploymentsCount)
+	rollback.RoutingSettings.Weight = 100
+	app.Spec.Deployments = []AppDeploymentSpec{rollback}
+	return nil
 }
 
 // PodState describes the simplified state of a pod in the cluster
@@ -676,6 +1433,17 @@ const (
 	CanaryStartedDesc  = "started"
 	CanaryFinished     = "CanaryFinished"
 	CanaryFinishedDesc = "finished"
+	CanaryAborted      = "CanaryAborted"
+	CanaryAbortedDesc  = "aborted"
+
+	CanaryAnalysisFailed = "CanaryAnalysisFailed"
+	CanaryAnalysisError  = "CanaryAnalysisError"
+
+	BlueGreenFinished     = "BlueGreenFinished"
+	BlueGreenFinishedDesc = "finished"
+
+	AppSleep     = "AppSleep"
+	AppSleepDesc = "scaled to 0"
 
 	CanaryNextStep       = "CanaryNextStep"
 	CanaryNextStepDesc   = "weight change"
@@ -703,8 +1471,8 @@ type CanaryEvent struct {
 	DeploymentVersion int
 
 	// Name represents canary event name. It is translated into Reason column of kubernetes event
-	// values: CanaryStarted, CanaryFinished
-	// errored values: CanaryNotActiveEvent, CanaryNoDeployments, CanaryNoScheduledSteps
+	// values: CanaryStarted, CanaryFinished, CanaryAborted
+	// errored values: CanaryNotActiveEvent, CanaryNoDeployments, CanaryNoScheduledSteps, CanaryAnalysisFailed
 	Name string
 	// Description states what is the outcome of this event
 	Description string
@@ -969,6 +1737,17 @@ const (
 	AppReconcileComplete = "AppReconcileComplete"
 	AppReconcileUpdate   = "AppReconcileUpdate"
 	AppReconcileError    = "AppReconcileError"
+
+	// Descriptions of AppReconcileUpdate events, "ketch app deploy --wait" parses them with fmt.Sscanf to show progress.
+	AppReconcileUnitsCreatedFormat            = "%d of %d new units created"
+	AppReconcileUnitsReadyFormat              = "%d of %d new units ready"
+	AppReconcileUnitsPendingTerminationFormat = "%d old units pending termination"
+	AppReconcileHealthcheckWaitFormat         = "waiting healthcheck on %d created units"
+
+	// Descriptions of AppReconcileError events start with these prefixes.
+	AppReconcileHealthcheckError      = "error waiting for healthcheck"
+	AppReconcileTimeoutError          = "deployment timeout"
+	AppReconcileProgressDeadlineError = "exceeded its progress deadline"
 )
 
 // AppDeploymentEvent represents fields and annotations for an Event that describes an app deployment.
@@ -11,6 +11,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/require"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
 )
@@ -356,6 +357,112 @@ func TestApp_UnsetEnvs(t *testing.T) {
 	}
 }
 
+func TestApp_SetProcessEnvs(t *testing.T) {
+	tests := []struct {
+		name     string
+		selector Selector
+		envs     []Env
+		wantEnvs map[string][]Env
+		wantErr  error
+	}{
+		{
+			name:     "process of all deployments",
+			selector: NewSelector(0, "worker"),
+			envs:     []Env{{Name: "QUEUE", Value: "jobs"}},
+			wantEnvs: map[string][]Env{
+				"1/worker": {{Name: "QUEUE", Value: "jobs"}},
+				"2/worker": {{Name: "QUEUE", Value: "jobs"}},
+			},
+		},
+		{
+			name:     "process of a deployment",
+			selector: NewSelector(2, "web"),
+			envs:     []Env{{Name: "DEBUG", Value: "true"}},
+			wantEnvs: map[string][]Env{
+				"2/web": {{Name: "DEBUG", Value: "true"}},
+			},
+		},
+		{
+			name:     "no such process",
+			selector: NewSelector(0, "cron"),
+			envs:     []Env{{Name: "DEBUG", Value: "true"}},
+			wantErr:  ErrProcessNotFound,
+		},
+		{
+			name:     "no such deployment",
+			selector: NewSelector(3, "web"),
+			envs:     []Env{{Name: "DEBUG", Value: "true"}},
+			wantErr:  ErrDeploymentNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := &App{Spec: defaultSpec()}
+			err := app.SetProcessEnvs(tt.selector, tt.envs)
+			if tt.wantErr != nil {
+				require.Equal(t, tt.wantErr, err)
+				return
+			}
+			require.Nil(t, err)
+			got := map[string][]Env{}
+			for _, deployment := range app.Spec.Deployments {
+				for _, process := range deployment.Processes {
+					if len(process.Env) > 0 {
+						got[fmt.Sprintf("%d/%s", deployment.Version, process.Name)] = process.Env
+					}
+				}
+			}
+			require.Equal(t, tt.wantEnvs, got)
+
+			require.Nil(t, app.UnsetProcessEnvs(tt.selector, []string{tt.envs[0].Name}))
+			for _, deployment := range app.Spec.Deployments {
+				for _, process := range deployment.Processes {
+					require.Empty(t, process.Env)
+				}
+			}
+		})
+	}
+}
+
+func TestApp_SetResources(t *testing.T) {
+	app := &App{Spec: defaultSpec()}
+	app.Spec.Deployments[0].Processes[0].Resources = &v1.ResourceRequirements{
+		Limits: v1.ResourceList{v1.ResourceMemory: resource.MustParse("1Gi")},
+	}
+	err := app.SetResources(NewSelector(1, "web"),
+		v1.ResourceList{v1.ResourceCPU: resource.MustParse("500m"), v1.ResourceMemory: resource.MustParse("512Mi")},
+		v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")},
+	)
+	require.Nil(t, err)
+	want := &v1.ResourceRequirements{
+		Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("500m"), v1.ResourceMemory: resource.MustParse("512Mi")},
+		Limits:   v1.ResourceList{v1.ResourceCPU: resource.MustParse("1"), v1.ResourceMemory: resource.MustParse("1Gi")},
+	}
+	require.Equal(t, want, app.Spec.Deployments[0].Processes[0].Resources)
+	require.Nil(t, app.Spec.Deployments[0].Processes[1].Resources)
+	require.Nil(t, app.Spec.Deployments[1].Processes[0].Resources)
+
+	err = app.SetResources(NewSelector(1, "cron"), v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")}, nil)
+	require.Equal(t, ErrProcessNotFound, err)
+}
+
+func TestApp_SetAutoscaling(t *testing.T) {
+	app := &App{Spec: defaultSpec()}
+	autoscaling := &AutoscalingSpec{MinUnits: 2, MaxUnits: 5}
+	require.Nil(t, app.SetAutoscaling(NewSelector(1, "web"), autoscaling))
+	require.Equal(t, autoscaling, app.Spec.Deployments[0].Processes[0].Autoscaling)
+	// units are moved into the autoscaler's bounds.
+	require.Equal(t, intRef(2), app.Spec.Deployments[0].Processes[0].Units)
+	require.Nil(t, app.Spec.Deployments[0].Processes[1].Autoscaling)
+	require.Nil(t, app.Spec.Deployments[1].Processes[0].Autoscaling)
+
+	require.Nil(t, app.SetAutoscaling(NewSelector(1, "web"), nil))
+	require.Nil(t, app.Spec.Deployments[0].Processes[0].Autoscaling)
+	require.Equal(t, intRef(2), app.Spec.Deployments[0].Processes[0].Units)
+
+	require.Equal(t, ErrProcessNotFound, app.SetAutoscaling(NewSelector(1, "cron"), autoscaling))
+}
+
 func TestApp_DefaultCname(t *testing.T) {
 	tests := []struct {
 		name                 string
@@ -416,6 +523,24 @@ func TestApp_DefaultCname(t *testing.T) {
 	}
 }
 
+func TestApp_PreviewCname(t *testing.T) {
+	framework := &Framework{
+		Spec: FrameworkSpec{
+			IngressController: IngressControllerSpec{
+				ServiceEndpoint: "20.20.20.20",
+			},
+		},
+	}
+	app := &App{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-2"},
+		Spec:       AppSpec{Ingress: IngressSpec{GenerateDefaultCname: true}},
+	}
+	require.Equal(t, stringRef("app-2-preview.20.20.20.20.shipa.cloud"), app.PreviewCname(framework))
+
+	app.Spec.Ingress.GenerateDefaultCname = false
+	require.Nil(t, app.PreviewCname(framework))
+}
+
 func TestApp_CNames(t *testing.T) {
 	framework := Framework{
 		Spec: FrameworkSpec{
@@ -1011,6 +1136,44 @@ func TestApp_DoCanary(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "autoscaled processes stay within bounds",
+			now:  *timeRef(10, 31),
+			app: App{
+				Spec: AppSpec{
+					Canary: CanarySpec{
+						Steps:             3,
+						StepWeight:        33,
+						StepTimeInteval:   10 * time.Minute,
+						NextScheduledTime: timeRef(10, 30),
+						CurrentStep:       1,
+						Active:            true,
+						Target:            map[string]uint16{"p1": 8},
+					},
+					Deployments: []AppDeploymentSpec{
+						{Version: 2, RoutingSettings: RoutingSettings{Weight: 67}, Processes: []ProcessSpec{{Name: "p1", Units: intRef(3), Autoscaling: &AutoscalingSpec{MinUnits: 3, MaxUnits: 10}}}},
+						{Version: 3, RoutingSettings: RoutingSettings{Weight: 33}, Processes: []ProcessSpec{{Name: "p1", Units: intRef(1), Autoscaling: &AutoscalingSpec{MinUnits: 1, MaxUnits: 4}}}},
+					},
+				},
+			},
+			wantApp: App{
+				Spec: AppSpec{
+					Canary: CanarySpec{
+						Steps:             3,
+						StepWeight:        33,
+						StepTimeInteval:   10 * time.Minute,
+						NextScheduledTime: timeRef(10, 40),
+						CurrentStep:       2,
+						Active:            true,
+						Target:            map[string]uint16{"p1": 8},
+					},
+					Deployments: []AppDeploymentSpec{
+						{Version: 2, RoutingSettings: RoutingSettings{Weight: 34}, Processes: []ProcessSpec{{Name: "p1", Units: intRef(3), Autoscaling: &AutoscalingSpec{MinUnits: 3, MaxUnits: 10}}}},
+						{Version: 3, RoutingSettings: RoutingSettings{Weight: 66}, Processes: []ProcessSpec{{Name: "p1", Units: intRef(4), Autoscaling: &AutoscalingSpec{MinUnits: 1, MaxUnits: 4}}}},
+					},
+				},
+			},
+		},
 		{
 			name: "happy path - the last step of canary",
 			now:  *timeRef(10, 31),
@@ -1208,6 +1371,672 @@ func TestApp_DoCanary(t *testing.T) {
 	}
 }
 
+func TestApp_PauseCanary(t *testing.T) {
+	app := App{Spec: AppSpec{Canary: CanarySpec{Active: true}}}
+	require.Nil(t, app.PauseCanary())
+	require.True(t, app.Spec.Canary.Paused)
+
+	app = App{}
+	require.Equal(t, ErrCanaryNotActive, app.PauseCanary())
+}
+
+func TestApp_ResumeCanary(t *testing.T) {
+	timeRef := func(hours int, minutes int) *metav1.Time {
+		t := metav1.Date(2021, 2, 1, hours, minutes, 0, 0, time.UTC)
+		return &t
+	}
+	tests := []struct {
+		name       string
+		canary     CanarySpec
+		now        metav1.Time
+		wantCanary CanarySpec
+		wantErr    error
+	}{
+		{
+			name: "paused canary",
+			canary: CanarySpec{
+				Active:            true,
+				Paused:            true,
+				StepTimeInteval:   10 * time.Minute,
+				NextScheduledTime: timeRef(10, 0),
+			},
+			now: *timeRef(11, 0),
+			wantCanary: CanarySpec{
+				Active:            true,
+				StepTimeInteval:   10 * time.Minute,
+				NextScheduledTime: timeRef(11, 10),
+			},
+		},
+		{
+			name: "canary is not paused",
+			canary: CanarySpec{
+				Active:            true,
+				StepTimeInteval:   10 * time.Minute,
+				NextScheduledTime: timeRef(10, 0),
+			},
+			now: *timeRef(11, 0),
+			wantCanary: CanarySpec{
+				Active:            true,
+				StepTimeInteval:   10 * time.Minute,
+				NextScheduledTime: timeRef(10, 0),
+			},
+		},
+		{
+			name:    "canary is not active",
+			canary:  CanarySpec{Paused: true},
+			now:     *timeRef(11, 0),
+			wantErr: ErrCanaryNotActive,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := App{Spec: AppSpec{Canary: tt.canary}}
+			err := app.ResumeCanary(tt.now)
+			if tt.wantErr != nil {
+				require.Equal(t, tt.wantErr, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.wantCanary, app.Spec.Canary)
+		})
+	}
+}
+
+func TestApp_PromoteCanary(t *testing.T) {
+	timeRef := func(hours int, minutes int) *metav1.Time {
+		t := metav1.Date(2021, 2, 1, hours, minutes, 0, 0, time.UTC)
+		return &t
+	}
+	tests := []struct {
+		name     string
+		spec     AppSpec
+		wantSpec AppSpec
+		wantErr  error
+	}{
+		{
+			name: "promote canary",
+			spec: AppSpec{
+				Canary: CanarySpec{
+					Steps:             5,
+					StepWeight:        20,
+					CurrentStep:       2,
+					Active:            true,
+					NextScheduledTime: timeRef(10, 0),
+					Target:            map[string]uint16{"web": 3},
+				},
+				Deployments: []AppDeploymentSpec{
+					{Version: 1, RoutingSettings: RoutingSettings{Weight: 80}, Processes: []ProcessSpec{{Name: "web", Units: intRef(3)}}},
+					{Version: 2, RoutingSettings: RoutingSettings{Weight: 20, Match: []RouteMatch{{Type: RouteMatchHeader, Name: "X-Canary", Value: "true"}}}, Processes: []ProcessSpec{{Name: "web", Units: intRef(1)}, {Name: "worker", Units: intRef(1)}}},
+				},
+			},
+			wantSpec: AppSpec{
+				Canary: CanarySpec{
+					Steps:             5,
+					StepWeight:        20,
+					CurrentStep:       2,
+					Active:            true,
+					Promoted:          true,
+					NextScheduledTime: timeRef(10, 0),
+					Target:            map[string]uint16{"web": 3},
+				},
+				Deployments: []AppDeploymentSpec{
+					{Version: 1, RoutingSettings: RoutingSettings{Weight: 80}, Processes: []ProcessSpec{{Name: "web", Units: intRef(3)}}},
+					{Version: 2, RoutingSettings: RoutingSettings{Weight: 20, Match: []RouteMatch{{Type: RouteMatchHeader, Name: "X-Canary", Value: "true"}}}, Processes: []ProcessSpec{{Name: "web", Units: intRef(1)}, {Name: "worker", Units: intRef(1)}}},
+				},
+			},
+		},
+		{
+			name:    "canary is not active",
+			spec:    AppSpec{Deployments: []AppDeploymentSpec{{Version: 1}}},
+			wantErr: ErrCanaryNotActive,
+		},
+		{
+			name: "no canary deployment",
+			spec: AppSpec{
+				Canary:      CanarySpec{Active: true},
+				Deployments: []AppDeploymentSpec{{Version: 1}},
+			},
+			wantErr: ErrDeploymentNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := App{Spec: tt.spec}
+			err := app.PromoteCanary()
+			if tt.wantErr != nil {
+				require.Equal(t, tt.wantErr, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.wantSpec, app.Spec)
+		})
+	}
+}
+
+func TestApp_DoPromote(t *testing.T) {
+	app := App{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+		Spec: AppSpec{
+			Canary: CanarySpec{Active: true, Paused: true, Steps: 5, CurrentStep: 2, Target: map[string]uint16{"web": 3}},
+			Deployments: []AppDeploymentSpec{
+				{Version: 1, RoutingSettings: RoutingSettings{Weight: 80}, Processes: []ProcessSpec{{Name: "web", Units: intRef(3)}}},
+				{Version: 2, RoutingSettings: RoutingSettings{Weight: 20}, Processes: []ProcessSpec{{Name: "web", Units: intRef(1)}}},
+			},
+		},
+	}
+	require.Nil(t, app.PromoteCanary())
+
+	recorder := record.NewFakeRecorder(1)
+	app.DoPromote(recorder)
+	require.Equal(t, CanarySpec{Steps: 5, CurrentStep: 5, Target: map[string]uint16{"web": 3}}, app.Spec.Canary)
+	require.Equal(t, []AppDeploymentSpec{
+		{Version: 2, RoutingSettings: RoutingSettings{Weight: 100}, Processes: []ProcessSpec{{Name: "web", Units: intRef(3)}}},
+	}, app.Spec.Deployments)
+	require.Equal(t, "Normal CanaryFinished CanaryFinished - Canary for app dashboard | version 2 - finished", <-recorder.Events)
+}
+
+func TestApp_AbortCanary(t *testing.T) {
+	next := metav1.Date(2021, 2, 1, 10, 30, 0, 0, time.UTC)
+	app := App{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+		Spec: AppSpec{
+			Canary: CanarySpec{Active: true, Paused: true, NextScheduledTime: &next, Target: map[string]uint16{"web": 4}},
+			Deployments: []AppDeploymentSpec{
+				{Version: 1, RoutingSettings: RoutingSettings{Weight: 60}, Processes: []ProcessSpec{{Name: "web", Units: intRef(2)}}},
+				{Version: 2, RoutingSettings: RoutingSettings{Weight: 40, Match: []RouteMatch{{Type: RouteMatchCookie, Name: "canary", Value: "always"}}}, Processes: []ProcessSpec{{Name: "web", Units: intRef(2)}}},
+			},
+		},
+		Status: AppStatus{
+			History: []AppDeploymentSpec{
+				{Version: 1, RoutingSettings: RoutingSettings{Weight: 100}, Processes: []ProcessSpec{{Name: "web", Units: intRef(4)}}},
+			},
+		},
+	}
+	require.Nil(t, app.AbortCanary())
+	require.True(t, app.Spec.Canary.Aborted)
+
+	recorder := record.NewFakeRecorder(1)
+	app.DoAbort(recorder)
+	require.Equal(t, CanarySpec{Target: map[string]uint16{"web": 4}}, app.Spec.Canary)
+	require.Equal(t, []AppDeploymentSpec{
+		{Version: 1, RoutingSettings: RoutingSettings{Weight: 100}, Processes: []ProcessSpec{{Name: "web", Units: intRef(4)}}},
+	}, app.Spec.Deployments)
+	require.Equal(t, "Warning CanaryAborted CanaryAborted - Canary for app dashboard | version 2 - aborted", <-recorder.Events)
+
+	require.Equal(t, ErrCanaryNotActive, app.AbortCanary())
+}
+
+func TestApp_DoAnalysisFailure(t *testing.T) {
+	next := metav1.Date(2021, 2, 1, 10, 30, 0, 0, time.UTC)
+	now := metav1.Date(2021, 2, 1, 10, 31, 0, 0, time.UTC)
+	app := App{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+		Spec: AppSpec{
+			Canary: CanarySpec{
+				Active:            true,
+				StepTimeInteval:   10 * time.Minute,
+				NextScheduledTime: &next,
+				Analysis:          &CanaryAnalysisSpec{FailureLimit: 2},
+			},
+			Deployments: []AppDeploymentSpec{
+				{Version: 1, RoutingSettings: RoutingSettings{Weight: 80}},
+				{Version: 2, RoutingSettings: RoutingSettings{Weight: 20}},
+			},
+		},
+	}
+	recorder := record.NewFakeRecorder(2)
+
+	app.DoAnalysisFailure(errors.New("connection refused"), now, recorder)
+	require.True(t, app.Spec.Canary.Active)
+	require.Equal(t, 1, app.Spec.Canary.AnalysisFailures)
+	require.Equal(t, metav1.NewTime(now.Add(10*time.Minute)), *app.Spec.Canary.NextScheduledTime)
+	require.False(t, app.IsCanaryStepDue(now))
+	require.Equal(t, "Warning CanaryAnalysisError CanaryAnalysisError - Canary for app dashboard | version 2 - analysis failed (1 of 2): connection refused", <-recorder.Events)
+
+	app.DoAnalysisFailure(errors.New("connection refused"), now, recorder)
+	require.False(t, app.Spec.Canary.Active)
+	require.Len(t, app.Spec.Deployments, 1)
+	require.Equal(t, DeploymentVersion(1), app.Spec.Deployments[0].Version)
+	require.Equal(t, "Warning CanaryAnalysisFailed CanaryAnalysisFailed - Canary for app dashboard | version 2 - analysis failed 2 times in a row: connection refused", <-recorder.Events)
+}
+
+func TestApp_IsCanaryStepDue(t *testing.T) {
+	next := metav1.Date(2021, 2, 1, 10, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		canary CanarySpec
+		now    metav1.Time
+		want   bool
+	}{
+		{
+			name:   "step is due",
+			canary: CanarySpec{Active: true, NextScheduledTime: &next},
+			now:    metav1.Date(2021, 2, 1, 10, 30, 0, 0, time.UTC),
+			want:   true,
+		},
+		{
+			name:   "step is not due yet",
+			canary: CanarySpec{Active: true, NextScheduledTime: &next},
+			now:    metav1.Date(2021, 2, 1, 10, 29, 0, 0, time.UTC),
+		},
+		{
+			name:   "canary is paused",
+			canary: CanarySpec{Active: true, Paused: true, NextScheduledTime: &next},
+			now:    metav1.Date(2021, 2, 1, 10, 31, 0, 0, time.UTC),
+		},
+		{
+			name:   "canary is not active",
+			canary: CanarySpec{NextScheduledTime: &next},
+			now:    metav1.Date(2021, 2, 1, 10, 31, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := App{Spec: AppSpec{Canary: tt.canary}}
+			require.Equal(t, tt.want, app.IsCanaryStepDue(tt.now))
+		})
+	}
+}
+
+func TestApp_PromoteBlueGreen(t *testing.T) {
+	now := metav1.Date(2021, 2, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		spec        AppSpec
+		wantWeights []uint8
+		wantErr     error
+	}{
+		{
+			name: "promote new deployment",
+			spec: AppSpec{
+				BlueGreen: BlueGreenSpec{Active: true, GracePeriod: 10 * time.Minute},
+				Deployments: []AppDeploymentSpec{
+					{Version: 1, RoutingSettings: RoutingSettings{Weight: 100}},
+					{Version: 2, RoutingSettings: RoutingSettings{Weight: 0}},
+				},
+			},
+			wantWeights: []uint8{0, 100},
+		},
+		{
+			name: "switch back to previous deployment",
+			spec: AppSpec{
+				BlueGreen: BlueGreenSpec{Active: true, GracePeriod: 10 * time.Minute, PromotedAt: &now},
+				Deployments: []AppDeploymentSpec{
+					{Version: 1, RoutingSettings: RoutingSettings{Weight: 0}},
+					{Version: 2, RoutingSettings: RoutingSettings{Weight: 100}},
+				},
+			},
+			wantWeights: []uint8{100, 0},
+		},
+		{
+			name:    "blue-green is not active",
+			spec:    AppSpec{Deployments: []AppDeploymentSpec{{Version: 1}}},
+			wantErr: ErrBlueGreenNotActive,
+		},
+		{
+			name: "no preview deployment",
+			spec: AppSpec{
+				BlueGreen:   BlueGreenSpec{Active: true},
+				Deployments: []AppDeploymentSpec{{Version: 1, RoutingSettings: RoutingSettings{Weight: 100}}},
+			},
+			wantErr: ErrDeploymentNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := App{Spec: tt.spec}
+			err := app.PromoteBlueGreen(now)
+			if tt.wantErr != nil {
+				require.Equal(t, tt.wantErr, err)
+				return
+			}
+			require.Nil(t, err)
+			var weights []uint8
+			for _, deployment := range app.Spec.Deployments {
+				weights = append(weights, deployment.RoutingSettings.Weight)
+			}
+			require.Equal(t, tt.wantWeights, weights)
+			require.Equal(t, &now, app.Spec.BlueGreen.PromotedAt)
+		})
+	}
+}
+
+func TestApp_FinishBlueGreen(t *testing.T) {
+	promotedAt := metav1.Date(2021, 2, 1, 10, 0, 0, 0, time.UTC)
+	app := App{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+		Spec: AppSpec{
+			BlueGreen: BlueGreenSpec{Active: true, GracePeriod: 10 * time.Minute, PromotedAt: &promotedAt},
+			Deployments: []AppDeploymentSpec{
+				{Version: 1, RoutingSettings: RoutingSettings{Weight: 0}},
+				{Version: 2, RoutingSettings: RoutingSettings{Weight: 100}},
+			},
+		},
+	}
+	require.Equal(t, 4*time.Minute, app.BlueGreenGracePeriodLeft(metav1.NewTime(promotedAt.Add(6*time.Minute))))
+	require.False(t, app.IsBlueGreenFinished(metav1.NewTime(promotedAt.Add(6*time.Minute))))
+	require.True(t, app.IsBlueGreenFinished(metav1.NewTime(promotedAt.Add(10*time.Minute))))
+
+	recorder := record.NewFakeRecorder(1)
+	app.FinishBlueGreen(recorder)
+	require.Equal(t, BlueGreenSpec{}, app.Spec.BlueGreen)
+	require.Equal(t, []AppDeploymentSpec{{Version: 2, RoutingSettings: RoutingSettings{Weight: 100}}}, app.Spec.Deployments)
+	require.Equal(t, "Normal BlueGreenFinished BlueGreenFinished - Blue-green deployment for app dashboard | version 2 - finished", <-recorder.Events)
+}
+
+func TestApp_SleepAndWake(t *testing.T) {
+	wokenAt := metav1.Date(2021, 2, 1, 10, 0, 0, 0, time.UTC)
+	app := App{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+		Spec: AppSpec{
+			IdlePolicy: &IdlePolicySpec{IdleMinutes: 30, PrometheusURL: "http://prometheus", LastTransitionTime: &wokenAt},
+			Deployments: []AppDeploymentSpec{
+				{Version: 1, Processes: []ProcessSpec{{Name: "web", Units: intRef(3)}, {Name: "worker"}}},
+			},
+		},
+	}
+	left, checked := app.IdleCheckLeft(metav1.NewTime(wokenAt.Add(10 * time.Minute)))
+	require.True(t, checked)
+	require.Equal(t, 20*time.Minute, left)
+	left, checked = app.IdleCheckLeft(metav1.NewTime(wokenAt.Add(40 * time.Minute)))
+	require.True(t, checked)
+	require.Equal(t, time.Duration(0), left)
+
+	// the next check happens a window after the last one.
+	checkedAt := metav1.NewTime(wokenAt.Add(40 * time.Minute))
+	app.Status.LastIdleCheckTime = &checkedAt
+	left, checked = app.IdleCheckLeft(metav1.NewTime(wokenAt.Add(50 * time.Minute)))
+	require.True(t, checked)
+	require.Equal(t, 20*time.Minute, left)
+	left, checked = app.IdleCheckLeft(metav1.NewTime(wokenAt.Add(70 * time.Minute)))
+	require.True(t, checked)
+	require.Equal(t, time.Duration(0), left)
+
+	recorder := record.NewFakeRecorder(1)
+	sleptAt := metav1.NewTime(wokenAt.Add(40 * time.Minute))
+	app.Sleep(sleptAt, recorder)
+	require.Equal(t, "Normal AppSleep AppSleep - App dashboard received no traffic for 30 minutes - scaled to 0", <-recorder.Events)
+	require.True(t, app.Spec.IdlePolicy.Asleep)
+	require.Equal(t, &sleptAt, app.Spec.IdlePolicy.LastTransitionTime)
+	require.Equal(t, []ProcessUnits{{Version: 1, Process: "web", Units: intRef(3)}, {Version: 1, Process: "worker"}}, app.Spec.IdlePolicy.Units)
+	require.Equal(t, intRef(0), app.Spec.Deployments[0].Processes[0].Units)
+	require.Equal(t, intRef(0), app.Spec.Deployments[0].Processes[1].Units)
+	_, checked = app.IdleCheckLeft(metav1.NewTime(wokenAt.Add(80 * time.Minute)))
+	require.False(t, checked)
+
+	// a deployment added while the app is asleep is started with one unit.
+	app.Spec.Deployments = append(app.Spec.Deployments, AppDeploymentSpec{Version: 2, Processes: []ProcessSpec{{Name: "web", Units: intRef(0)}}})
+	require.Nil(t, app.Wake(metav1.NewTime(wokenAt.Add(90*time.Minute))))
+	require.False(t, app.Spec.IdlePolicy.Asleep)
+	require.Nil(t, app.Spec.IdlePolicy.Units)
+	require.Equal(t, intRef(3), app.Spec.Deployments[0].Processes[0].Units)
+	require.Nil(t, app.Spec.Deployments[0].Processes[1].Units)
+	require.Equal(t, intRef(1), app.Spec.Deployments[1].Processes[0].Units)
+
+	// an app which was just woken up gets the whole window regardless of the last check.
+	left, checked = app.IdleCheckLeft(metav1.NewTime(wokenAt.Add(100 * time.Minute)))
+	require.True(t, checked)
+	require.Equal(t, 20*time.Minute, left)
+
+	require.Equal(t, ErrAppNotAsleep, app.Wake(metav1.NewTime(wokenAt.Add(90*time.Minute))))
+}
+
+func TestApp_StartAsleep(t *testing.T) {
+	app := App{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+		Spec: AppSpec{
+			Deployments: []AppDeploymentSpec{
+				{Version: 1, Processes: []ProcessSpec{{Name: "web", Units: intRef(3)}}},
+			},
+			IdlePolicy: &IdlePolicySpec{IdleMinutes: 30, PrometheusURL: "http://prometheus"},
+		},
+	}
+	app.Sleep(metav1.Now(), record.NewFakeRecorder(1))
+	require.True(t, app.IsAsleep())
+
+	require.Nil(t, app.Start(Selector{}))
+	require.False(t, app.IsAsleep())
+	require.Nil(t, app.Spec.IdlePolicy.Units)
+	require.Equal(t, intRef(1), app.Spec.Deployments[0].Processes[0].Units)
+	_, checked := app.IdleCheckLeft(metav1.Now())
+	require.True(t, checked)
+}
+
+func TestApp_IsReconciled(t *testing.T) {
+	app := App{ObjectMeta: metav1.ObjectMeta{Name: "dashboard", Generation: 2}}
+	require.False(t, app.IsReconciled())
+
+	app.SetCondition(Scheduled, v1.ConditionTrue, "", metav1.Now())
+	require.False(t, app.IsReconciled())
+
+	app.Status.ObservedGeneration = 2
+	require.True(t, app.IsReconciled())
+
+	app.SetCondition(Scheduled, v1.ConditionFalse, "failed to update helm chart", metav1.Now())
+	require.False(t, app.IsReconciled())
+}
+
+func TestApp_RecordDeploymentHistory(t *testing.T) {
+	deployment := func(version DeploymentVersion, image string) AppDeploymentSpec {
+		return AppDeploymentSpec{
+			Version:         version,
+			Image:           image,
+			RoutingSettings: RoutingSettings{Weight: 100},
+		}
+	}
+	tests := []struct {
+		name        string
+		deployments []AppDeploymentSpec
+		canary      CanarySpec
+		blueGreen   BlueGreenSpec
+		idlePolicy  *IdlePolicySpec
+		history     []AppDeploymentSpec
+
+		wantHistory []AppDeploymentSpec
+	}{
+		{
+			name:        "empty history",
+			deployments: []AppDeploymentSpec{deployment(1, "ketch:v1")},
+			wantHistory: []AppDeploymentSpec{deployment(1, "ketch:v1")},
+		},
+		{
+			name:        "new deployment",
+			deployments: []AppDeploymentSpec{deployment(2, "ketch:v2")},
+			history:     []AppDeploymentSpec{deployment(1, "ketch:v1")},
+			wantHistory: []AppDeploymentSpec{deployment(1, "ketch:v1"), deployment(2, "ketch:v2")},
+		},
+		{
+			name:        "recorded deployment isn't overwritten",
+			deployments: []AppDeploymentSpec{deployment(2, "ketch:v2-updated")},
+			history:     []AppDeploymentSpec{deployment(1, "ketch:v1"), deployment(2, "ketch:v2")},
+			wantHistory: []AppDeploymentSpec{deployment(1, "ketch:v1"), deployment(2, "ketch:v2")},
+		},
+		{
+			name:        "canary deployment in progress",
+			deployments: []AppDeploymentSpec{deployment(1, "ketch:v1"), deployment(2, "ketch:v2")},
+			canary:      CanarySpec{Active: true},
+			wantHistory: nil,
+		},
+		{
+			name:        "blue-green deployment in progress",
+			deployments: []AppDeploymentSpec{deployment(2, "ketch:v2")},
+			blueGreen:   BlueGreenSpec{Active: true},
+			history:     []AppDeploymentSpec{deployment(1, "ketch:v1")},
+			wantHistory: []AppDeploymentSpec{deployment(1, "ketch:v1")},
+		},
+		{
+			name:        "app is asleep",
+			deployments: []AppDeploymentSpec{deployment(2, "ketch:v2")},
+			idlePolicy:  &IdlePolicySpec{Asleep: true},
+			history:     []AppDeploymentSpec{deployment(1, "ketch:v1")},
+			wantHistory: []AppDeploymentSpec{deployment(1, "ketch:v1")},
+		},
+		{
+			name:        "history is limited",
+			deployments: []AppDeploymentSpec{deployment(12, "ketch:v12")},
+			history: []AppDeploymentSpec{
+				deployment(1, "ketch:v1"), deployment(2, "ketch:v2"), deployment(3, "ketch:v3"), deployment(4, "ketch:v4"),
+				deployment(5, "ketch:v5"), deployment(6, "ketch:v6"), deployment(7, "ketch:v7"), deployment(8, "ketch:v8"),
+				deployment(9, "ketch:v9"), deployment(10, "ketch:v10"),
+			},
+			wantHistory: []AppDeploymentSpec{
+				deployment(2, "ketch:v2"), deployment(3, "ketch:v3"), deployment(4, "ketch:v4"), deployment(5, "ketch:v5"),
+				deployment(6, "ketch:v6"), deployment(7, "ketch:v7"), deployment(8, "ketch:v8"), deployment(9, "ketch:v9"),
+				deployment(10, "ketch:v10"), deployment(12, "ketch:v12"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := &App{
+				Spec: AppSpec{
+					Deployments: tt.deployments,
+					Canary:      tt.canary,
+					BlueGreen:   tt.blueGreen,
+					IdlePolicy:  tt.idlePolicy,
+				},
+				Status: AppStatus{History: tt.history},
+			}
+			app.RecordDeploymentHistory()
+			if diff := cmp.Diff(tt.wantHistory, app.Status.History); diff != "" {
+				t.Errorf("History mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestApp_RecordDeploymentHistory_canaryRollback(t *testing.T) {
+	now := metav1.Date(2021, 2, 1, 10, 0, 0, 0, time.UTC)
+	app := &App{
+		Spec: AppSpec{
+			DeploymentsCount: 1,
+			Deployments: []AppDeploymentSpec{
+				{Version: 1, Image: "ketch:v1", RoutingSettings: RoutingSettings{Weight: 100}, Processes: []ProcessSpec{{Name: "web", Units: intRef(4)}}},
+			},
+		},
+	}
+	app.RecordDeploymentHistory()
+
+	app.Spec.DeploymentsCount = 2
+	app.Spec.Deployments = append(app.Spec.Deployments, AppDeploymentSpec{
+		Version: 2, Image: "ketch:v2", RoutingSettings: RoutingSettings{Weight: 0}, Processes: []ProcessSpec{{Name: "web", Units: intRef(1)}},
+	})
+	app.Spec.Canary = CanarySpec{
+		Steps:             2,
+		StepWeight:        50,
+		StepTimeInteval:   10 * time.Minute,
+		NextScheduledTime: &now,
+		CurrentStep:       1,
+		Active:            true,
+		Target:            map[string]uint16{"web": 4},
+	}
+	for app.Spec.Canary.Active {
+		require.Nil(t, app.DoCanary(*app.Spec.Canary.NextScheduledTime, logr.Discard(), &record.FakeRecorder{}))
+		app.RecordDeploymentHistory()
+	}
+
+	require.Nil(t, app.Rollback(0))
+	require.Equal(t, []AppDeploymentSpec{
+		{Version: 3, Image: "ketch:v1", RoutingSettings: RoutingSettings{Weight: 100}, Processes: []ProcessSpec{{Name: "web", Units: intRef(4)}}},
+	}, app.Spec.Deployments)
+}
+
+func TestApp_Rollback(t *testing.T) {
+	history := []AppDeploymentSpec{
+		{Version: 1, Image: "ketch:v1", RoutingSettings: RoutingSettings{Weight: 100}, Processes: []ProcessSpec{{Name: "web", Units: intRef(1)}}},
+		{Version: 2, Image: "ketch:v2", RoutingSettings: RoutingSettings{Weight: 100}, Processes: []ProcessSpec{{Name: "web", Units: intRef(2)}}},
+		{Version: 3, Image: "ketch:v3", RoutingSettings: RoutingSettings{Weight: 100}, Processes: []ProcessSpec{{Name: "web", Units: intRef(3)}}},
+	}
+	tests := []struct {
+		name    string
+		spec    AppSpec
+		version DeploymentVersion
+
+		wantSpec AppSpec
+		wantErr  error
+	}{
+		{
+			name: "previous deployment",
+			spec: AppSpec{
+				Deployments:      []AppDeploymentSpec{history[2]},
+				DeploymentsCount: 3,
+			},
+			wantSpec: AppSpec{
+				Deployments: []AppDeploymentSpec{
+					{Version: 4, Image: "ketch:v2", RoutingSettings: RoutingSettings{Weight: 100}, Processes: []ProcessSpec{{Name: "web", Units: intRef(2)}}},
+				},
+				DeploymentsCount: 4,
+			},
+		},
+		{
+			name: "specific version",
+			spec: AppSpec{
+				Deployments:      []AppDeploymentSpec{history[2]},
+				DeploymentsCount: 3,
+			},
+			version: 1,
+			wantSpec: AppSpec{
+				Deployments: []AppDeploymentSpec{
+					{Version: 4, Image: "ketch:v1", RoutingSettings: RoutingSettings{Weight: 100}, Processes: []ProcessSpec{{Name: "web", Units: intRef(1)}}},
+				},
+				DeploymentsCount: 4,
+			},
+		},
+		{
+			name: "no previous deployment",
+			spec: AppSpec{
+				Deployments:      []AppDeploymentSpec{history[0]},
+				DeploymentsCount: 1,
+			},
+			wantErr: ErrNoPreviousDeployment,
+		},
+		{
+			name: "unknown version",
+			spec: AppSpec{
+				Deployments:      []AppDeploymentSpec{history[2]},
+				DeploymentsCount: 3,
+			},
+			version: 10,
+			wantErr: ErrDeploymentNotFound,
+		},
+		{
+			name: "canary in progress",
+			spec: AppSpec{
+				Deployments:      []AppDeploymentSpec{history[1], history[2]},
+				DeploymentsCount: 3,
+				Canary:           CanarySpec{Active: true},
+			},
+			wantErr: ErrCanaryInProgress,
+		},
+		{
+			name: "blue-green in progress",
+			spec: AppSpec{
+				Deployments:      []AppDeploymentSpec{history[1], history[2]},
+				DeploymentsCount: 3,
+				BlueGreen:        BlueGreenSpec{Active: true},
+			},
+			wantErr: ErrBlueGreenInProgress,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := &App{
+				Spec:   tt.spec,
+				Status: AppStatus{History: history},
+			}
+			err := app.Rollback(tt.version)
+			if tt.wantErr != nil {
+				require.Equal(t, tt.wantErr, err)
+				return
+			}
+			require.Nil(t, err)
+			if diff := cmp.Diff(tt.wantSpec, app.Spec); diff != "" {
+				t.Errorf("AppSpec mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestValidateMetadataItem(t *testing.T) {
 	tests := []struct {
 		description  string
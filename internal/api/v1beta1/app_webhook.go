@@ -0,0 +1,218 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// applog is for logging in this package.
+var applog = logf.Log.WithName("app-resource")
+
+var appmgr manager = nil
+
+func (r *App) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	appmgr = mgr
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-theketch-io-v1beta1-app,mutating=true,failurePolicy=fail,groups=theketch.io,resources=apps,verbs=create;update,versions=v1beta1,name=mapp.kb.io,sideEffects=none,admissionReviewVersions=v1beta1
+
+var _ webhook.Defaulter = &App{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type
+func (r *App) Default() {
+	applog.Info("default", "name", r.Name)
+
+	// a single deployment receives all traffic unless it is a part of a canary or blue-green deployment.
+	if len(r.Spec.Deployments) == 1 && r.Spec.Deployments[0].RoutingSettings.Weight == 0 && !r.Spec.Canary.Active && !r.Spec.BlueGreen.Active {
+		r.Spec.Deployments[0].RoutingSettings.Weight = 100
+	}
+	// DeploymentsCount is used as a version of a new deployment, so it can't be less than a version of any existing deployment.
+	for _, deployment := range r.Spec.Deployments {
+		if int(deployment.Version) > r.Spec.DeploymentsCount {
+			r.Spec.DeploymentsCount = int(deployment.Version)
+		}
+	}
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-theketch-io-v1beta1-app,mutating=false,failurePolicy=fail,groups=theketch.io,resources=apps,versions=v1beta1,name=vapp.kb.io,sideEffects=none,admissionReviewVersions=v1beta1
+
+var _ webhook.Validator = &App{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *App) ValidateCreate() error {
+	applog.Info("validate create", "name", r.Name)
+	return r.Validate(context.Background(), appmgr.GetClient(), nil)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *App) ValidateUpdate(old runtime.Object) error {
+	applog.Info("validate update", "name", r.Name)
+	oldApp, ok := old.(*App)
+	if !ok {
+		return fmt.Errorf("can't validate app update")
+	}
+	if r.DeletionTimestamp != nil {
+		return nil
+	}
+	return r.Validate(context.Background(), appmgr.GetClient(), oldApp)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *App) ValidateDelete() error {
+	applog.Info("validate delete", "name", r.Name)
+	return nil
+}
+
+// Validate checks that the app can be deployed to its framework.
+// oldApp is the current state of the app when it is being updated and nil when it is being created,
+// only changed settings of an updated app are checked against the framework and other apps.
+// Besides the admission webhook, it is used by ketch CLI to validate an app before deploying it.
+func (r *App) Validate(ctx context.Context, c client.Reader, oldApp *App) error {
+	if err := r.validateSpec(); err != nil {
+		return err
+	}
+
+	if oldApp == nil || oldApp.Spec.Framework != r.Spec.Framework {
+		framework := Framework{}
+		if err := c.Get(ctx, types.NamespacedName{Name: r.Spec.Framework}, &framework); err != nil {
+			if apierrors.IsNotFound(err) {
+				return fmt.Errorf("%w: %s", ErrFrameworkNotFound, r.Spec.Framework)
+			}
+			return err
+		}
+		if !framework.HasApp(r.Name) && framework.Spec.AppQuotaLimit != nil && *framework.Spec.AppQuotaLimit != -1 && len(framework.Status.Apps) >= *framework.Spec.AppQuotaLimit {
+			return ErrAppQuotaExceeded
+		}
+	}
+
+	var cnames []string
+	for _, cname := range r.Spec.Ingress.Cnames {
+		if oldApp == nil || !oldApp.hasCname(cname.Name) {
+			cnames = append(cnames, cname.Name)
+		}
+	}
+	if len(cnames) == 0 {
+		return nil
+	}
+	apps := AppList{}
+	if err := c.List(ctx, &apps); err != nil {
+		return err
+	}
+	for _, app := range apps.Items {
+		if app.Name == r.Name {
+			continue
+		}
+		for _, cname := range cnames {
+			if app.hasCname(cname) {
+				return fmt.Errorf("%w: %s is used by %s app", ErrCnameInUse, cname, app.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// validateSpec checks the rules which don't depend on the state of the cluster.
+func (r *App) validateSpec() error {
+	if len(r.Spec.Deployments) > 2 {
+		return ErrTooManyDeployments
+	}
+	if len(r.Spec.Deployments) > 0 {
+		weight := 0
+		for _, deployment := range r.Spec.Deployments {
+			weight += int(deployment.RoutingSettings.Weight)
+		}
+		if weight != 100 {
+			return fmt.Errorf("%w, got %d", ErrInvalidRoutingWeights, weight)
+		}
+	}
+	for _, item := range r.Spec.Labels {
+		if err := item.Validate(); err != nil {
+			return fmt.Errorf("invalid label: %w", err)
+		}
+	}
+	for _, item := range r.Spec.Annotations {
+		if err := item.Validate(); err != nil {
+			return fmt.Errorf("invalid annotation: %w", err)
+		}
+	}
+	envs := append([]Env{}, r.Spec.Env...)
+	for _, deployment := range r.Spec.Deployments {
+		for _, process := range deployment.Processes {
+			envs = append(envs, process.Env...)
+			if err := validateAutoscaling(process); err != nil {
+				return err
+			}
+		}
+	}
+	return validateEnvs(envs)
+}
+
+func validateEnvs(envs []Env) error {
+	for _, env := range envs {
+		if msgs := validation.IsEnvVarName(env.Name); len(msgs) > 0 {
+			return fmt.Errorf("%w %q: %s", ErrInvalidEnvName, env.Name, strings.Join(msgs, ", "))
+		}
+		if env.ValueFrom == nil {
+			continue
+		}
+		if len(env.Value) > 0 {
+			return fmt.Errorf("%w %q: value must be empty when valueFrom is set", ErrInvalidEnvValueFrom, env.Name)
+		}
+		if ref := env.ValueFrom.SecretKeyRef; ref == nil || len(ref.Name) == 0 || len(ref.Key) == 0 {
+			return fmt.Errorf("%w %q: secretKeyRef must have a name and a key", ErrInvalidEnvValueFrom, env.Name)
+		}
+	}
+	return nil
+}
+
+func validateAutoscaling(process ProcessSpec) error {
+	autoscaling := process.Autoscaling
+	if autoscaling == nil {
+		return nil
+	}
+	if autoscaling.MinUnits < 1 || autoscaling.MaxUnits < autoscaling.MinUnits {
+		return fmt.Errorf("%w of process %q: maxUnits must be greater than or equal to minUnits", ErrInvalidAutoscaling, process.Name)
+	}
+	if autoscaling.TargetCPUUtilization == nil && autoscaling.TargetMemoryUtilization == nil && autoscaling.CustomMetric == nil {
+		return fmt.Errorf("%w of process %q: at least one target is required", ErrInvalidAutoscaling, process.Name)
+	}
+	return nil
+}
+
+func (r *App) hasCname(name string) bool {
+	for _, cname := range r.Spec.Ingress.Cnames {
+		if cname.Name == name {
+			return true
+		}
+	}
+	return false
+}
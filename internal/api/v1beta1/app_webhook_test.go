@@ -0,0 +1,266 @@
+package v1beta1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/theketchio/ketch/internal/api/v1beta1/mocks"
+)
+
+func newAppWebhookClient(frameworks []Framework, apps []App) *mocks.MockClient {
+	return &mocks.MockClient{
+		OnGet: func(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+			for _, framework := range frameworks {
+				if framework.Name == key.Name {
+					*obj.(*Framework) = framework
+					return nil
+				}
+			}
+			return apierrors.NewNotFound(schema.GroupResource{Group: "theketch.io", Resource: "frameworks"}, key.Name)
+		},
+		OnList: func(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
+			list.(*AppList).Items = apps
+			return nil
+		},
+	}
+}
+
+func TestApp_Default(t *testing.T) {
+	app := App{
+		Spec: AppSpec{
+			Deployments: []AppDeploymentSpec{{Version: 3}},
+		},
+	}
+	app.Default()
+	require.Equal(t, uint8(100), app.Spec.Deployments[0].RoutingSettings.Weight)
+	require.Equal(t, 3, app.Spec.DeploymentsCount)
+
+	canary := App{
+		Spec: AppSpec{
+			Canary:           CanarySpec{Active: true},
+			Deployments:      []AppDeploymentSpec{{Version: 3}},
+			DeploymentsCount: 5,
+		},
+	}
+	canary.Default()
+	require.Equal(t, uint8(0), canary.Spec.Deployments[0].RoutingSettings.Weight)
+	require.Equal(t, 5, canary.Spec.DeploymentsCount)
+}
+
+func TestApp_ValidateCreate(t *testing.T) {
+	quota := 1
+	frameworks := []Framework{
+		{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "full"},
+			Spec:       FrameworkSpec{AppQuotaLimit: &quota},
+			Status:     FrameworkStatus{Apps: []string{"another-app"}},
+		},
+	}
+	apps := []App{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "another-app"},
+			Spec:       AppSpec{Ingress: IngressSpec{Cnames: CnameList{{Name: "theketch.io"}}}},
+		},
+	}
+	deployment := func(version DeploymentVersion, weight uint8) AppDeploymentSpec {
+		return AppDeploymentSpec{Version: version, RoutingSettings: RoutingSettings{Weight: weight}}
+	}
+	tests := []struct {
+		name    string
+		spec    AppSpec
+		wantErr string
+	}{
+		{
+			name: "valid app",
+			spec: AppSpec{
+				Framework:   "default",
+				Deployments: []AppDeploymentSpec{deployment(1, 70), deployment(2, 30)},
+				Env:         []Env{{Name: "PORT", Value: "8080"}},
+				Ingress:     IngressSpec{Cnames: CnameList{{Name: "app.theketch.io"}}},
+			},
+		},
+		{
+			name:    "unknown framework",
+			spec:    AppSpec{Framework: "unknown"},
+			wantErr: "framework not found: unknown",
+		},
+		{
+			name:    "quota overflow",
+			spec:    AppSpec{Framework: "full"},
+			wantErr: "you have reached the limit of apps",
+		},
+		{
+			name: "more than two deployments",
+			spec: AppSpec{
+				Framework:   "default",
+				Deployments: []AppDeploymentSpec{deployment(1, 50), deployment(2, 30), deployment(3, 20)},
+			},
+			wantErr: "an app can't have more than two deployments",
+		},
+		{
+			name: "weights don't sum up to 100",
+			spec: AppSpec{
+				Framework:   "default",
+				Deployments: []AppDeploymentSpec{deployment(1, 70), deployment(2, 20)},
+			},
+			wantErr: "routing weights of deployments must sum up to 100, got 90",
+		},
+		{
+			name: "invalid label",
+			spec: AppSpec{
+				Framework: "default",
+				Labels:    []MetadataItem{{Apply: map[string]string{"-invalid": "value"}}},
+			},
+			wantErr: "invalid label: malformed metadata key",
+		},
+		{
+			name: "invalid env name",
+			spec: AppSpec{
+				Framework: "default",
+				Env:       []Env{{Name: "1PORT", Value: "8080"}},
+			},
+			wantErr: `invalid environment variable name "1PORT": a valid environment variable name must consist of alphabetic characters, digits, '_', '-', or '.', and must not start with a digit (e.g. 'my.env-name',  or 'MY_ENV.NAME',  or 'MyEnvName1', regex used for validation is '[-._a-zA-Z][-._a-zA-Z0-9]*')`,
+		},
+		{
+			name: "env with a value and a secret",
+			spec: AppSpec{
+				Framework: "default",
+				Env: []Env{{Name: "TOKEN", Value: "plain", ValueFrom: &EnvVarSource{
+					SecretKeyRef: &SecretKeySelector{Name: "tokens", Key: "token"},
+				}}},
+			},
+			wantErr: `invalid environment variable source "TOKEN": value must be empty when valueFrom is set`,
+		},
+		{
+			name: "env source without secret key",
+			spec: AppSpec{
+				Framework: "default",
+				Env:       []Env{{Name: "TOKEN", ValueFrom: &EnvVarSource{}}},
+			},
+			wantErr: `invalid environment variable source "TOKEN": secretKeyRef must have a name and a key`,
+		},
+		{
+			name: "autoscaling with max units below min units",
+			spec: AppSpec{
+				Framework: "default",
+				Deployments: []AppDeploymentSpec{{
+					Version:         1,
+					RoutingSettings: RoutingSettings{Weight: 100},
+					Processes:       []ProcessSpec{{Name: "web", Autoscaling: &AutoscalingSpec{MinUnits: 3, MaxUnits: 2}}},
+				}},
+			},
+			wantErr: `invalid autoscaling of process "web": maxUnits must be greater than or equal to minUnits`,
+		},
+		{
+			name: "autoscaling without targets",
+			spec: AppSpec{
+				Framework: "default",
+				Deployments: []AppDeploymentSpec{{
+					Version:         1,
+					RoutingSettings: RoutingSettings{Weight: 100},
+					Processes:       []ProcessSpec{{Name: "web", Autoscaling: &AutoscalingSpec{MinUnits: 1, MaxUnits: 2}}},
+				}},
+			},
+			wantErr: `invalid autoscaling of process "web": at least one target is required`,
+		},
+		{
+			name: "cname is used by another app",
+			spec: AppSpec{
+				Framework: "default",
+				Ingress:   IngressSpec{Cnames: CnameList{{Name: "theketch.io"}}},
+			},
+			wantErr: "cname is already in use: theketch.io is used by another-app app",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appmgr = &mockManager{client: newAppWebhookClient(frameworks, apps)}
+			app := App{ObjectMeta: metav1.ObjectMeta{Name: "app"}, Spec: tt.spec}
+			err := app.ValidateCreate()
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.Nil(t, err)
+		})
+	}
+}
+
+func TestApp_ValidateUpdate(t *testing.T) {
+	quota := 1
+	frameworks := []Framework{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "full"},
+			Spec:       FrameworkSpec{AppQuotaLimit: &quota},
+			Status:     FrameworkStatus{Apps: []string{"app"}},
+		},
+	}
+	apps := []App{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "another-app"},
+			Spec:       AppSpec{Ingress: IngressSpec{Cnames: CnameList{{Name: "theketch.io"}, {Name: "used.theketch.io"}}}},
+		},
+	}
+	oldApp := &App{
+		ObjectMeta: metav1.ObjectMeta{Name: "app"},
+		Spec: AppSpec{
+			Framework: "full",
+			Ingress:   IngressSpec{Cnames: CnameList{{Name: "theketch.io"}}},
+		},
+	}
+	tests := []struct {
+		name    string
+		spec    AppSpec
+		wantErr string
+	}{
+		{
+			name: "unchanged settings are not checked against the cluster",
+			spec: AppSpec{
+				Framework: "full",
+				Ingress:   IngressSpec{Cnames: CnameList{{Name: "theketch.io"}}},
+			},
+		},
+		{
+			name: "new cname is not used by other apps",
+			spec: AppSpec{
+				Framework: "full",
+				Ingress:   IngressSpec{Cnames: CnameList{{Name: "theketch.io"}, {Name: "app.theketch.io"}}},
+			},
+		},
+		{
+			name: "new cname is used by another app",
+			spec: AppSpec{
+				Framework: "full",
+				Ingress:   IngressSpec{Cnames: CnameList{{Name: "theketch.io"}, {Name: "used.theketch.io"}}},
+			},
+			wantErr: "cname is already in use: used.theketch.io is used by another-app app",
+		},
+		{
+			name:    "new framework doesn't exist",
+			spec:    AppSpec{Framework: "unknown"},
+			wantErr: "framework not found: unknown",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appmgr = &mockManager{client: newAppWebhookClient(frameworks, apps)}
+			app := App{ObjectMeta: metav1.ObjectMeta{Name: "app"}, Spec: tt.spec}
+			err := app.ValidateUpdate(oldApp)
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.Nil(t, err)
+		})
+	}
+}
@@ -24,6 +24,53 @@ const (
 	// ErrDecreaseQuota is returned when a new quota is too small.
 	ErrDecreaseQuota Error = "failed to decrease quota because the framework has more running apps than the new quota permits"
 
+	// ErrNoPreviousDeployment is returned when an app can not be rolled back because its history has no previous deployment.
+	ErrNoPreviousDeployment Error = "no previous deployment found in the app history"
+
+	// ErrCanaryInProgress is returned when an operation can not be completed because a canary deployment is in progress.
+	ErrCanaryInProgress Error = "canary deployment is in progress"
+
+	// ErrCanaryNotActive is returned when an operation can not be completed because there is no active canary deployment.
+	ErrCanaryNotActive Error = "canary deployment is not active"
+
+	// ErrBlueGreenInProgress is returned when an operation can not be completed because a blue-green deployment is in progress.
+	ErrBlueGreenInProgress Error = "blue-green deployment is in progress"
+
+	// ErrBlueGreenNotActive is returned when an operation can not be completed because there is no active blue-green deployment.
+	ErrBlueGreenNotActive Error = "blue-green deployment is not active"
+
+	// ErrFrameworkNotFound is returned when an app refers to a framework which doesn't exist.
+	ErrFrameworkNotFound Error = "framework not found"
+
+	// ErrAppQuotaExceeded is returned when a framework can't run one more app because of its quota.
+	ErrAppQuotaExceeded Error = "you have reached the limit of apps"
+
+	// ErrCnameInUse is returned when a cname of an app is already used by another app.
+	ErrCnameInUse Error = "cname is already in use"
+
+	// ErrTooManyDeployments is returned when an app has more than two deployments.
+	ErrTooManyDeployments Error = "an app can't have more than two deployments"
+
+	// ErrInvalidRoutingWeights is returned when routing weights of app's deployments don't sum up to 100.
+	ErrInvalidRoutingWeights Error = "routing weights of deployments must sum up to 100"
+
+	// ErrInvalidEnvName is returned when a name of an environment variable is not a valid C identifier.
+	ErrInvalidEnvName Error = "invalid environment variable name"
+
+	// ErrInvalidEnvValueFrom is returned when an environment variable has both a value and a source of the value
+	// or the source doesn't select a key of a secret.
+	ErrInvalidEnvValueFrom Error = "invalid environment variable source"
+
+	// ErrInvalidAutoscaling is returned when autoscaling of a process has invalid bounds or no metrics.
+	ErrInvalidAutoscaling Error = "invalid autoscaling"
+
+	// ErrAppNotAsleep is returned when an app is woken up but it isn't put to sleep.
+	ErrAppNotAsleep Error = "app is not asleep"
+
+	// ErrInvalidJobContainer is returned when a container of a job has no image, invalid resources
+	// or mounts a volume the job doesn't have.
+	ErrInvalidJobContainer Error = "invalid job container"
+
 	// ErrJobExists
 	ErrJobExists Error = "failed to create job because the job already exists"
 )
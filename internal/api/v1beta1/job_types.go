@@ -33,18 +33,97 @@ type JobSpec struct {
 	BackoffLimit int         `json:"backoffLimit,omitempty"`
 	Containers   []Container `json:"containers,omitempty"`
 	Policy       Policy      `json:"policy,omitempty"`
+
+	// Schedule is a cron expression to run the job periodically, e.g. "0 3 * * *".
+	// A job with a schedule is run by a CronJob.
+	Schedule string `json:"schedule,omitempty"`
+	// ConcurrencyPolicy specifies how to treat concurrent runs of a scheduled job.
+	ConcurrencyPolicy ConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
+	// SuccessfulJobsHistoryLimit is the number of successful finished runs of a scheduled job to keep.
+	// +kubebuilder:validation:Minimum=0
+	SuccessfulJobsHistoryLimit *int `json:"successfulJobsHistoryLimit,omitempty"`
+	// FailedJobsHistoryLimit is the number of failed finished runs of a scheduled job to keep.
+	// +kubebuilder:validation:Minimum=0
+	FailedJobsHistoryLimit *int `json:"failedJobsHistoryLimit,omitempty"`
+	// StartingDeadlineSeconds is the deadline for starting a run of a scheduled job if it misses its scheduled time.
+	// +kubebuilder:validation:Minimum=0
+	StartingDeadlineSeconds *int `json:"startingDeadlineSeconds,omitempty"`
+	// RunHistoryLimit is the number of finished on-demand runs of the job to keep, 5 by default.
+	// +kubebuilder:validation:Minimum=0
+	RunHistoryLimit *int `json:"runHistoryLimit,omitempty"`
+
+	// ActiveDeadlineSeconds is how long a run of the job may be active before it is terminated.
+	// +kubebuilder:validation:Minimum=1
+	ActiveDeadlineSeconds *int `json:"activeDeadlineSeconds,omitempty"`
+	// TTLSecondsAfterFinished is how long a finished run of the job is kept before it is deleted.
+	// +kubebuilder:validation:Minimum=0
+	TTLSecondsAfterFinished *int `json:"ttlSecondsAfterFinished,omitempty"`
+
+	// ServiceAccountName is the name of the service account to run the job's pods with.
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+	// NodeSelector selects nodes to run the job's pods on.
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Tolerations of the job's pods.
+	Tolerations []v1.Toleration `json:"tolerations,omitempty"`
+	// ImagePullSecrets are secrets used to pull images of the job's containers.
+	ImagePullSecrets []v1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+	// Volumes can be mounted by the job's containers.
+	Volumes []v1.Volume `json:"volumes,omitempty"`
 }
 
 // JobStatus defines the observed state of Job
 type JobStatus struct {
 	Conditions []Condition         `json:"conditions,omitempty"`
 	Framework  *v1.ObjectReference `json:"framework,omitempty"`
+
+	// ObservedGeneration is the generation of the job whose chart was installed last.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Active is the number of running pods of the last run.
+	Active int32 `json:"active,omitempty"`
+	// Succeeded is the number of pods of the last run which reached phase Succeeded.
+	Succeeded int32 `json:"succeeded,omitempty"`
+	// Failed is the number of pods of the last run which reached phase Failed.
+	Failed int32 `json:"failed,omitempty"`
+	// StartTime is the time when the last run was started.
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime is the time when the last run was completed.
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// LastScheduleTime is the time when a scheduled job was last started by its schedule.
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+	// Runs are on-demand runs of the job started by "ketch job run", the most recent first.
+	Runs []JobRun `json:"runs,omitempty"`
 }
 
+// JobRun describes an on-demand run of a job.
+type JobRun struct {
+	// Name is the name of the kubernetes Job of the run.
+	Name           string       `json:"name"`
+	Phase          JobRunPhase  `json:"phase"`
+	StartTime      *metav1.Time `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	Succeeded      int32        `json:"succeeded,omitempty"`
+	Failed         int32        `json:"failed,omitempty"`
+}
+
+// JobRunPhase is the outcome of a run of a job.
+type JobRunPhase string
+
+const (
+	JobRunRunning   JobRunPhase = "Running"
+	JobRunSucceeded JobRunPhase = "Succeeded"
+	JobRunFailed    JobRunPhase = "Failed"
+)
+
+// DefaultRunHistoryLimit is the number of finished on-demand runs of a job kept if the job doesn't specify it.
+const DefaultRunHistoryLimit = 5
+
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Cluster
 // +kubebuilder:printcolumn:name="Framework",type=string,JSONPath=`.spec.Framework`
+// +kubebuilder:printcolumn:name="Active",type=integer,JSONPath=`.status.active`
+// +kubebuilder:printcolumn:name="Succeeded",type=integer,JSONPath=`.status.succeeded`
+// +kubebuilder:printcolumn:name="Failed",type=integer,JSONPath=`.status.failed`
 
 // Job is the Schema for the jobs API
 type Job struct {
@@ -74,8 +153,31 @@ type Container struct {
 	Name    string   `json:"name"`
 	Image   string   `json:"image"`
 	Command []string `json:"command"`
+	// Args are arguments of the command.
+	Args []string `json:"args,omitempty"`
+	// WorkingDir is the working directory of the command.
+	WorkingDir string `json:"workingDir,omitempty"`
+	// Env is a list of environment variables of the container.
+	Env []Env `json:"env,omitempty"`
+	// Resources are compute resources required by the container.
+	Resources *v1.ResourceRequirements `json:"resources,omitempty"`
+	// VolumeMounts are volumes of the job mounted into the container.
+	VolumeMounts []v1.VolumeMount `json:"volumeMounts,omitempty"`
 }
 
+// ConcurrencyPolicy describes how concurrent runs of a scheduled job are treated.
+// +kubebuilder:validation:Enum=Allow;Forbid;Replace
+type ConcurrencyPolicy string
+
+const (
+	// AllowConcurrent allows runs of a scheduled job to run concurrently.
+	AllowConcurrent ConcurrencyPolicy = "Allow"
+	// ForbidConcurrent skips a new run if the previous one hasn't finished yet.
+	ForbidConcurrent ConcurrencyPolicy = "Forbid"
+	// ReplaceConcurrent replaces the currently running run with a new one.
+	ReplaceConcurrent ConcurrencyPolicy = "Replace"
+)
+
 type RestartPolicy string
 
 const (
@@ -85,6 +187,19 @@ const (
 	OnFailure RestartPolicy = "OnFailure"
 )
 
+// IsScheduled returns true if the job is run periodically.
+func (s JobSpec) IsScheduled() bool {
+	return len(s.Schedule) > 0
+}
+
+// RunHistoryLimitOrDefault returns the number of finished on-demand runs of the job to keep.
+func (s JobSpec) RunHistoryLimitOrDefault() int {
+	if s.RunHistoryLimit == nil {
+		return DefaultRunHistoryLimit
+	}
+	return *s.RunHistoryLimit
+}
+
 // Condition looks for a condition with the provided type in the condition list and returns it.
 func (s JobStatus) Condition(t ConditionType) *Condition {
 	for _, c := range s.Conditions {
@@ -45,6 +45,9 @@ var _ webhook.Validator = &Job{}
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type
 func (r *Job) ValidateCreate() error {
 	joblog.Info("validate create", "name", r.Name)
+	if err := r.validateSpec(); err != nil {
+		return err
+	}
 	client := jobmgr.GetClient()
 	jobs := JobList{}
 	if err := client.List(context.Background(), &jobs); err != nil {
@@ -58,6 +61,35 @@ func (r *Job) ValidateCreate() error {
 	return nil
 }
 
+func (r *Job) validateSpec() error {
+	volumes := make(map[string]struct{}, len(r.Spec.Volumes))
+	for _, volume := range r.Spec.Volumes {
+		volumes[volume.Name] = struct{}{}
+	}
+	for _, container := range r.Spec.Containers {
+		if len(container.Image) == 0 {
+			return fmt.Errorf("%w %q: image is required", ErrInvalidJobContainer, container.Name)
+		}
+		if err := validateEnvs(container.Env); err != nil {
+			return err
+		}
+		for _, mount := range container.VolumeMounts {
+			if _, ok := volumes[mount.Name]; !ok {
+				return fmt.Errorf("%w %q: volume %q is not found", ErrInvalidJobContainer, container.Name, mount.Name)
+			}
+		}
+		if container.Resources == nil {
+			continue
+		}
+		for name, request := range container.Resources.Requests {
+			if limit, ok := container.Resources.Limits[name]; ok && request.Cmp(limit) > 0 {
+				return fmt.Errorf("%w %q: %s request must be less than or equal to %s limit", ErrInvalidJobContainer, container.Name, name, name)
+			}
+		}
+	}
+	return nil
+}
+
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (r *Job) ValidateUpdate(old runtime.Object) error {
 	joblog.Info("validate update", "name", r.Name)
@@ -65,6 +97,9 @@ func (r *Job) ValidateUpdate(old runtime.Object) error {
 	if !ok {
 		return fmt.Errorf("can't validate job update")
 	}
+	if err := r.validateSpec(); err != nil {
+		return err
+	}
 	client := jobmgr.GetClient()
 	jobs := JobList{}
 	if err := client.List(context.Background(), &jobs); err != nil {
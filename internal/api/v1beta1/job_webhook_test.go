@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -97,6 +99,74 @@ func TestJob_ValidateCreate(t *testing.T) {
 	}
 }
 
+func TestJob_validateSpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    JobSpec
+		wantErr string
+	}{
+		{
+			name: "valid job",
+			spec: JobSpec{
+				Volumes: []v1.Volume{{Name: "reports"}},
+				Containers: []Container{{
+					Name:         "report",
+					Image:        "ubuntu",
+					Env:          []Env{{Name: "FORMAT", Value: "csv"}},
+					VolumeMounts: []v1.VolumeMount{{Name: "reports", MountPath: "/reports"}},
+					Resources: &v1.ResourceRequirements{
+						Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("500m")},
+						Limits:   v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")},
+					},
+				}},
+			},
+		},
+		{
+			name:    "container without image",
+			spec:    JobSpec{Containers: []Container{{Name: "report"}}},
+			wantErr: `invalid job container "report": image is required`,
+		},
+		{
+			name:    "invalid env",
+			spec:    JobSpec{Containers: []Container{{Name: "report", Image: "ubuntu", Env: []Env{{Name: "TOKEN", ValueFrom: &EnvVarSource{}}}}}},
+			wantErr: `invalid environment variable source "TOKEN": secretKeyRef must have a name and a key`,
+		},
+		{
+			name: "unknown volume",
+			spec: JobSpec{Containers: []Container{{
+				Name:         "report",
+				Image:        "ubuntu",
+				VolumeMounts: []v1.VolumeMount{{Name: "reports", MountPath: "/reports"}},
+			}}},
+			wantErr: `invalid job container "report": volume "reports" is not found`,
+		},
+		{
+			name: "request exceeds limit",
+			spec: JobSpec{Containers: []Container{{
+				Name:  "report",
+				Image: "ubuntu",
+				Resources: &v1.ResourceRequirements{
+					Requests: v1.ResourceList{v1.ResourceMemory: resource.MustParse("1Gi")},
+					Limits:   v1.ResourceList{v1.ResourceMemory: resource.MustParse("512Mi")},
+				},
+			}}},
+			wantErr: `invalid job container "report": memory request must be less than or equal to memory limit`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := Job{Spec: tt.spec}
+			err := job.validateSpec()
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.Nil(t, err)
+		})
+	}
+}
+
 func TestJob_ValidateUpdate(t *testing.T) {
 
 	const listError Error = "error"
@@ -0,0 +1,78 @@
+// Package canary evaluates metrics of canary deployments.
+package canary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/prometheus"
+)
+
+// Querier runs an instant query against a Prometheus compatible HTTP API.
+type Querier interface {
+	Query(ctx context.Context, prometheusURL, query string) (float64, error)
+}
+
+// Analyzer checks metrics of a canary deployment using a Prometheus compatible HTTP API.
+type Analyzer struct {
+	Querier Querier
+}
+
+// NewAnalyzer returns an Analyzer.
+func NewAnalyzer(querier Querier) *Analyzer {
+	return &Analyzer{Querier: querier}
+}
+
+// Breach describes a metric which value is out of its bounds.
+type Breach struct {
+	Metric string
+	Value  float64
+	// NoData is true if the query of the metric returned no data and the analysis doesn't accept it.
+	NoData bool
+}
+
+// Reason returns a description of the breach to be used in events.
+func (b Breach) Reason() string {
+	if b.NoData {
+		return fmt.Sprintf("metric %s returned no data", b.Metric)
+	}
+	return fmt.Sprintf("metric %s is out of bounds: %v", b.Metric, b.Value)
+}
+
+// Analyze evaluates all metrics of the canary analysis of the app.
+// It returns the first metric which value is out of its bounds or nil if all metrics are fine.
+// A metric which query returns no data is fine unless the analysis' NoData policy is fail.
+func (a *Analyzer) Analyze(ctx context.Context, app *ketchv1.App) (*Breach, error) {
+	analysis := app.Spec.Canary.Analysis
+	if analysis == nil || len(app.Spec.Deployments) < 2 {
+		return nil, nil
+	}
+	values := prometheus.NewQueryValues(app)
+	values.Version = int(app.Spec.Deployments[1].Version)
+	values.Interval = fmt.Sprintf("%ds", int(app.Spec.Canary.StepTimeInteval.Seconds()))
+	for _, metric := range analysis.Metrics {
+		query, err := prometheus.RenderQuery(metric.Query, values)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render query of metric %s: %w", metric.Name, err)
+		}
+		value, err := a.Querier.Query(ctx, analysis.PrometheusURL, query)
+		if errors.Is(err, prometheus.ErrNoData) {
+			if analysis.NoData == ketchv1.CanaryNoDataFail {
+				return &Breach{Metric: metric.Name, NoData: true}, nil
+			}
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to query metric %s: %w", metric.Name, err)
+		}
+		if metric.Min != nil && value < metric.Min.AsApproximateFloat64() {
+			return &Breach{Metric: metric.Name, Value: value}, nil
+		}
+		if metric.Max != nil && value > metric.Max.AsApproximateFloat64() {
+			return &Breach{Metric: metric.Name, Value: value}, nil
+		}
+	}
+	return nil, nil
+}
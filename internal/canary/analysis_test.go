@@ -0,0 +1,148 @@
+package canary
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/prometheus"
+)
+
+func quantityRef(s string) *resource.Quantity {
+	q := resource.MustParse(s)
+	return &q
+}
+
+func newFakePrometheus(t *testing.T, results map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/v1/query", r.URL.Path)
+		result, ok := results[r.URL.Query().Get("query")]
+		if !ok {
+			fmt.Fprint(w, `{"status":"error","error":"unknown query"}`)
+			return
+		}
+		fmt.Fprint(w, result)
+	}))
+}
+
+func TestAnalyzer_Analyze(t *testing.T) {
+	server := newFakePrometheus(t, map[string]string{
+		`error_rate{destination_app="app-id",destination_version="2"}[300s]`: `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1435781451.781,"0.2"]}]}}`,
+		`latency{destination_app="app-id"}`:                                  `{"status":"success","data":{"resultType":"scalar","result":[1435781451.781,"120"]}}`,
+		`empty{destination_app="app-id"}`:                                    `{"status":"success","data":{"resultType":"vector","result":[]}}`,
+	})
+	defer server.Close()
+
+	app := func(noData ketchv1.CanaryNoDataPolicy, metrics ...ketchv1.CanaryMetric) *ketchv1.App {
+		return &ketchv1.App{
+			ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+			Spec: ketchv1.AppSpec{
+				ID: "app-id",
+				Canary: ketchv1.CanarySpec{
+					Active:          true,
+					StepTimeInteval: 5 * time.Minute,
+					Analysis: &ketchv1.CanaryAnalysisSpec{
+						PrometheusURL: server.URL,
+						Metrics:       metrics,
+						NoData:        noData,
+					},
+				},
+				Deployments: []ketchv1.AppDeploymentSpec{
+					{Version: 1},
+					{Version: 2},
+				},
+			},
+		}
+	}
+	tests := []struct {
+		name       string
+		app        *ketchv1.App
+		wantBreach *Breach
+		wantErr    bool
+	}{
+		{
+			name: "metrics within bounds",
+			app: app(
+				"",
+				ketchv1.CanaryMetric{
+					Name:  "error-rate",
+					Query: `error_rate{destination_app="{{ .App }}",destination_version="{{ .Version }}"}[{{ .Interval }}]`,
+					Max:   quantityRef("0.5"),
+				},
+				ketchv1.CanaryMetric{
+					Name:  "latency",
+					Query: `latency{destination_app="{{ .App }}"}`,
+					Min:   quantityRef("100"),
+					Max:   quantityRef("500"),
+				},
+			),
+		},
+		{
+			name: "max breached",
+			app: app("", ketchv1.CanaryMetric{
+				Name:  "error-rate",
+				Query: `error_rate{destination_app="{{ .App }}",destination_version="{{ .Version }}"}[{{ .Interval }}]`,
+				Max:   quantityRef("0.1"),
+			}),
+			wantBreach: &Breach{Metric: "error-rate", Value: 0.2},
+		},
+		{
+			name: "min breached",
+			app: app("", ketchv1.CanaryMetric{
+				Name:  "latency",
+				Query: `latency{destination_app="{{ .App }}"}`,
+				Min:   quantityRef("200"),
+			}),
+			wantBreach: &Breach{Metric: "latency", Value: 120},
+		},
+		{
+			name: "no data passes",
+			app: app("", ketchv1.CanaryMetric{
+				Name:  "empty",
+				Query: `empty{destination_app="{{ .App }}"}`,
+				Max:   quantityRef("1"),
+			}),
+		},
+		{
+			name: "no data fails",
+			app: app(ketchv1.CanaryNoDataFail, ketchv1.CanaryMetric{
+				Name:  "empty",
+				Query: `empty{destination_app="{{ .App }}"}`,
+				Max:   quantityRef("1"),
+			}),
+			wantBreach: &Breach{Metric: "empty", NoData: true},
+		},
+		{
+			name: "query error",
+			app: app("", ketchv1.CanaryMetric{
+				Name:  "unknown",
+				Query: `unknown`,
+				Max:   quantityRef("1"),
+			}),
+			wantErr: true,
+		},
+		{
+			name: "no analysis",
+			app:  &ketchv1.App{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			breach, err := NewAnalyzer(prometheus.NewClient()).Analyze(context.Background(), tt.app)
+			if tt.wantErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.wantBreach, breach)
+		})
+	}
+}
@@ -43,7 +43,9 @@ type app struct {
 	Name        string        `json:"name"`
 	Deployments []deployment  `json:"deployments"`
 	Env         []ketchv1.Env `json:"env"`
-	Ingress     ingress       `json:"ingress"`
+	// EnvFrom is a list of sources to populate environment variables of the app's containers.
+	EnvFrom []ketchv1.EnvFromSource `json:"envFrom,omitempty"`
+	Ingress ingress                 `json:"ingress"`
 	// IsAccessible if not set, ketch won't create kubernetes objects like Ingress/Gateway to handle incoming request.
 	// These objects could be broken without valid routes to the application.
 	// For example, "spec.rules" of an Ingress object must contain at least one rule.
@@ -66,6 +68,8 @@ type deployment struct {
 	Processes        []process                 `json:"processes"`
 	Labels           []ketchv1.Label           `json:"labels"`
 	RoutingSettings  ketchv1.RoutingSettings   `json:"routingSettings"`
+	// PreviewCname is set for the preview deployment of a blue-green deployment.
+	PreviewCname string `json:"previewCname,omitempty"`
 }
 
 type Option func(opts *Options)
@@ -127,6 +131,7 @@ func New(application *ketchv1.App, framework *ketchv1.Framework, opts ...Option)
 			Name:                application.Name,
 			Ingress:             *ingress,
 			Env:                 application.Spec.Env,
+			EnvFrom:             application.Spec.EnvFrom,
 			Group:               ketchv1.Group,
 			MetadataLabels:      application.Spec.Labels,
 			MetadataAnnotations: application.Spec.Annotations,
@@ -135,6 +140,9 @@ func New(application *ketchv1.App, framework *ketchv1.Framework, opts ...Option)
 		IngressController: &framework.Spec.IngressController,
 	}
 
+	previewCname := application.PreviewCname(framework)
+	previewDeployment := application.PreviewDeployment()
+
 	for _, deploymentSpec := range application.Spec.Deployments {
 		deployment := deployment{
 			Image:   deploymentSpec.Image,
@@ -142,9 +150,13 @@ func New(application *ketchv1.App, framework *ketchv1.Framework, opts ...Option)
 			Labels:  deploymentSpec.Labels,
 			RoutingSettings: ketchv1.RoutingSettings{
 				Weight: deploymentSpec.RoutingSettings.Weight,
+				Match:  deploymentSpec.RoutingSettings.Match,
 			},
 			ImagePullSecrets: imagePullSecrets(deploymentSpec.ImagePullSecrets, application.Spec.DockerRegistry),
 		}
+		if previewCname != nil && previewDeployment != nil && previewDeployment.Version == deploymentSpec.Version {
+			deployment.PreviewCname = *previewCname
+		}
 		procfile, err := ProcfileFromProcesses(deploymentSpec.Processes)
 		if err != nil {
 			return nil, err
@@ -157,11 +169,12 @@ func New(application *ketchv1.App, framework *ketchv1.Framework, opts ...Option)
 			process, err := newProcess(name, isRoutable,
 				withCmd(c.procfile.Processes[name]),
 				withUnits(processSpec.Units),
-				withEnvs(processSpec.Env),
+				withEnvs(application.Spec.Env, processSpec.Env),
 				withPortsAndProbes(c),
 				withLifecycle(c.Lifecycle()),
 				withSecurityContext(processSpec.SecurityContext),
 				withResourceRequirements(processSpec.Resources),
+				withAutoscaling(processSpec.Autoscaling),
 				withVolumes(processSpec.Volumes),
 				withVolumeMounts(processSpec.VolumeMounts),
 				withLabels(application.Spec.Labels, deployment.Version),
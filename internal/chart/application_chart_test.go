@@ -205,6 +205,59 @@ func TestNewApplicationChart(t *testing.T) {
 		return &out
 	}
 
+	// setRouteMatch returns a copy of app with match rules for the last deployment
+	setRouteMatch := func(app *ketchv1.App) *ketchv1.App {
+		out := app.DeepCopy()
+		last := len(out.Spec.Deployments) - 1
+		out.Spec.Deployments[last].RoutingSettings.Match = []ketchv1.RouteMatch{
+			{Type: ketchv1.RouteMatchHeader, Name: "X-Canary", Value: "true"},
+			{Type: ketchv1.RouteMatchCookie, Name: "canary", Value: "always"},
+			{Type: ketchv1.RouteMatchQuery, Name: "canary", Value: "true"},
+		}
+		return out
+	}
+
+	// setBlueGreen returns a copy of app with an active blue-green deployment previewing the last deployment
+	setBlueGreen := func(app *ketchv1.App) *ketchv1.App {
+		out := app.DeepCopy()
+		out.Spec.BlueGreen = ketchv1.BlueGreenSpec{Active: true}
+		out.Spec.Deployments[0].RoutingSettings.Weight = 100
+		out.Spec.Deployments[1].RoutingSettings.Weight = 0
+		return out
+	}
+
+	setSecretEnv := func(app *ketchv1.App) *ketchv1.App {
+		out := app.DeepCopy()
+		out.Spec.Env = append(out.Spec.Env, ketchv1.Env{
+			Name: "DB_PASSWORD",
+			ValueFrom: &ketchv1.EnvVarSource{
+				SecretKeyRef: &ketchv1.SecretKeySelector{Name: "dashboard-env", Key: "DB_PASSWORD"},
+			},
+		})
+		return out
+	}
+
+	setEnvFrom := func(app *ketchv1.App) *ketchv1.App {
+		out := app.DeepCopy()
+		out.AddEnvFromConfigMap("dashboard-config")
+		return out
+	}
+
+	setAutoscaling := func(app *ketchv1.App) *ketchv1.App {
+		out := app.DeepCopy()
+		cpu := int32(70)
+		out.Spec.Deployments[0].Processes[0].Autoscaling = &ketchv1.AutoscalingSpec{
+			MinUnits:             2,
+			MaxUnits:             10,
+			TargetCPUUtilization: &cpu,
+			CustomMetric: &ketchv1.CustomMetricSpec{
+				Name:               "requests_per_second",
+				TargetAverageValue: resource.MustParse("100"),
+			},
+		}
+		return out
+	}
+
 	tests := []struct {
 		name        string
 		application *ketchv1.App
@@ -296,6 +349,86 @@ func TestNewApplicationChart(t *testing.T) {
 			group:             "shipa.io",
 			wantYamlsFilename: "dashboard-traefik-cluster-issuer-shipa",
 		},
+		{
+			name: "istio templates with route match rules",
+			opts: []Option{
+				WithTemplates(templates.IstioDefaultTemplates),
+				WithExposedPorts(exportedPorts),
+			},
+			application:       setRouteMatch(dashboard),
+			framework:         frameworkWithClusterIssuer,
+			wantYamlsFilename: "dashboard-istio-route-match",
+		},
+		{
+			name: "traefik templates with route match rules",
+			opts: []Option{
+				WithTemplates(templates.TraefikDefaultTemplates),
+				WithExposedPorts(exportedPorts),
+			},
+			application:       setRouteMatch(dashboard),
+			framework:         frameworkWithClusterIssuer,
+			wantYamlsFilename: "dashboard-traefik-route-match",
+		},
+		{
+			name: "nginx templates with blue-green deployment",
+			opts: []Option{
+				WithTemplates(templates.NginxDefaultTemplates),
+				WithExposedPorts(exportedPorts),
+			},
+			application:       setBlueGreen(dashboard),
+			framework:         frameworkWithClusterIssuer,
+			wantYamlsFilename: "dashboard-nginx-blue-green",
+		},
+		{
+			name: "istio templates with blue-green deployment",
+			opts: []Option{
+				WithTemplates(templates.IstioDefaultTemplates),
+				WithExposedPorts(exportedPorts),
+			},
+			application:       setBlueGreen(dashboard),
+			framework:         frameworkWithClusterIssuer,
+			wantYamlsFilename: "dashboard-istio-blue-green",
+		},
+		{
+			name: "traefik templates with blue-green deployment",
+			opts: []Option{
+				WithTemplates(templates.TraefikDefaultTemplates),
+				WithExposedPorts(exportedPorts),
+			},
+			application:       setBlueGreen(dashboard),
+			framework:         frameworkWithClusterIssuer,
+			wantYamlsFilename: "dashboard-traefik-blue-green",
+		},
+		{
+			name: "nginx templates with secret env",
+			opts: []Option{
+				WithTemplates(templates.NginxDefaultTemplates),
+				WithExposedPorts(exportedPorts),
+			},
+			application:       setSecretEnv(dashboard),
+			framework:         frameworkWithClusterIssuer,
+			wantYamlsFilename: "dashboard-nginx-secret-env",
+		},
+		{
+			name: "nginx templates with env from a config map",
+			opts: []Option{
+				WithTemplates(templates.NginxDefaultTemplates),
+				WithExposedPorts(exportedPorts),
+			},
+			application:       setEnvFrom(dashboard),
+			framework:         frameworkWithClusterIssuer,
+			wantYamlsFilename: "dashboard-nginx-env-from",
+		},
+		{
+			name: "nginx templates with autoscaling",
+			opts: []Option{
+				WithTemplates(templates.NginxDefaultTemplates),
+				WithExposedPorts(exportedPorts),
+			},
+			application:       setAutoscaling(dashboard),
+			framework:         frameworkWithClusterIssuer,
+			wantYamlsFilename: "dashboard-nginx-autoscaling",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
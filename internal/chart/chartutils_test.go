@@ -154,6 +154,7 @@ func TestGetValuesMap(t *testing.T) {
 					"version":         0.,
 				}},
 				"canary":         map[string]interface{}{},
+				"blueGreen":      map[string]interface{}{},
 				"dockerRegistry": map[string]interface{}{},
 				"ingress":        map[string]interface{}{"generateDefaultCname": false},
 			},
@@ -35,6 +35,17 @@ func NewHelmClient(namespace string, c client.Client) (*HelmClient, error) {
 	return &HelmClient{cfg: cfg, namespace: namespace, c: c}, nil
 }
 
+// NewHelmClientForConfigFlags returns a HelmClient instance which accesses the cluster with the given kubeconfig flags.
+// It is used by the CLI where the kubeconfig of the user is used instead of the in-cluster config.
+func NewHelmClientForConfigFlags(namespace string, kubeConfig *genericclioptions.ConfigFlags, c client.Client) (*HelmClient, error) {
+	kubeConfig.Namespace = &namespace
+	actionConfig := new(action.Configuration)
+	if err := actionConfig.Init(kubeConfig, namespace, os.Getenv("HELM_DRIVER"), log.Printf); err != nil {
+		return nil, err
+	}
+	return &HelmClient{cfg: actionConfig, namespace: namespace, c: c}, nil
+}
+
 func getActionConfig(namespace string) (*action.Configuration, error) {
 	actionConfig := new(action.Configuration)
 
@@ -107,3 +118,48 @@ func (c HelmClient) DeleteChart(appName string) error {
 	}
 	return err
 }
+
+// RenderChart renders the chart and runs the postrender kustomizations without installing anything to the cluster.
+// It returns the resulting manifests.
+func (c HelmClient) RenderChart(tv TemplateValuer, config ChartConfig) (string, error) {
+	files, err := bufferedFiles(config, tv.GetTemplates(), tv.GetValues())
+	if err != nil {
+		return "", err
+	}
+	chrt, err := loader.LoadFiles(files)
+	if err != nil {
+		return "", err
+	}
+	vals, err := getValuesMap(tv.GetValues())
+	if err != nil {
+		return "", err
+	}
+	// client only installation replaces the kube client and the release storage of its configuration,
+	// so it gets its own configuration to keep the client usable afterwards.
+	clientInstall := action.NewInstall(&action.Configuration{Log: log.Printf})
+	clientInstall.ReleaseName = tv.GetName()
+	clientInstall.Namespace = c.namespace
+	clientInstall.DryRun = true
+	clientInstall.ClientOnly = true
+	clientInstall.PostRenderer = &postRender{
+		namespace: c.namespace,
+		cli:       c.c,
+	}
+	rel, err := clientInstall.Run(chrt, vals)
+	if err != nil {
+		return "", err
+	}
+	return rel.Manifest, nil
+}
+
+// GetManifest returns the manifests of the app's helm release. It returns an empty string if the release is not found.
+func (c HelmClient) GetManifest(appName string) (string, error) {
+	rel, err := action.NewGet(c.cfg).Run(appName)
+	if err != nil && err.Error() == "release: not found" {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return rel.Manifest, nil
+}
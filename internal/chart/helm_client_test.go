@@ -0,0 +1,85 @@
+package chart
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/kube/fake"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/templates"
+	"github.com/theketchio/ketch/internal/utils/conversions"
+)
+
+func TestHelmClient_RenderChart(t *testing.T) {
+	framework := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "framework"},
+		Spec: ketchv1.FrameworkSpec{
+			NamespaceName: "ketch-gke",
+			IngressController: ketchv1.IngressControllerSpec{
+				ClassName:       "gke",
+				ServiceEndpoint: "20.20.20.20",
+			},
+		},
+	}
+	app := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+		Spec: ketchv1.AppSpec{
+			Deployments: []ketchv1.AppDeploymentSpec{
+				{
+					Image:   "shipasoftware/go-app:v1",
+					Version: 1,
+					Processes: []ketchv1.ProcessSpec{
+						{Name: "web", Units: conversions.IntPtr(1), Cmd: []string{"python"}},
+					},
+					RoutingSettings: ketchv1.RoutingSettings{Weight: 100},
+				},
+			},
+			Framework: "framework",
+			Ingress:   ketchv1.IngressSpec{GenerateDefaultCname: true},
+		},
+	}
+	appChart, err := New(app, framework, WithTemplates(templates.NginxDefaultTemplates), WithExposedPorts(map[ketchv1.DeploymentVersion][]ketchv1.ExposedPort{
+		1: {{Port: 9090, Protocol: "TCP"}},
+	}))
+	require.Nil(t, err)
+	chartConfig := ChartConfig{Version: "0.0.1", AppName: app.Name}
+
+	client := HelmClient{
+		cfg: &action.Configuration{
+			KubeClient:   &fake.PrintingKubeClient{},
+			Releases:     storage.Init(driver.NewMemory()),
+			Capabilities: chartutil.DefaultCapabilities,
+		},
+		namespace: framework.Spec.NamespaceName,
+		c:         clientfake.NewClientBuilder().Build(),
+	}
+
+	manifest, err := client.GetManifest(app.Name)
+	require.Nil(t, err)
+	require.Equal(t, "", manifest)
+
+	rendered, err := client.RenderChart(*appChart, chartConfig)
+	require.Nil(t, err)
+	require.True(t, strings.Contains(rendered, "kind: Deployment"))
+	require.True(t, strings.Contains(rendered, "shipasoftware/go-app:v1"))
+
+	// rendering doesn't install a release.
+	manifest, err = client.GetManifest(app.Name)
+	require.Nil(t, err)
+	require.Equal(t, "", manifest)
+
+	_, err = client.UpdateChart(*appChart, chartConfig)
+	require.Nil(t, err)
+
+	manifest, err = client.GetManifest(app.Name)
+	require.Nil(t, err)
+	require.Equal(t, rendered, manifest)
+}
@@ -2,13 +2,25 @@ package chart
 
 import (
 	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/kube/fake"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	v1core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
 	"github.com/theketchio/ketch/internal/templates"
+	"github.com/theketchio/ketch/internal/utils/conversions"
 )
 
 var (
@@ -66,3 +78,85 @@ func TestNewJobChartConfig(t *testing.T) {
 	chartConfig := NewJobChartConfig(*testJob)
 	require.Equal(t, expected, chartConfig)
 }
+
+func TestJobChart_Render(t *testing.T) {
+	const chartDirectory = "./testdata/charts/"
+
+	cronJob := testJob.DeepCopy()
+	cronJob.Spec.Schedule = "0 3 * * *"
+	cronJob.Spec.ConcurrencyPolicy = ketchv1.ForbidConcurrent
+	cronJob.Spec.SuccessfulJobsHistoryLimit = conversions.IntPtr(0)
+	cronJob.Spec.FailedJobsHistoryLimit = conversions.IntPtr(3)
+	cronJob.Spec.StartingDeadlineSeconds = conversions.IntPtr(120)
+
+	extendedJob := testJob.DeepCopy()
+	extendedJob.Spec.ActiveDeadlineSeconds = conversions.IntPtr(600)
+	extendedJob.Spec.TTLSecondsAfterFinished = conversions.IntPtr(0)
+	extendedJob.Spec.ServiceAccountName = "reporter"
+	extendedJob.Spec.NodeSelector = map[string]string{"pool": "batch"}
+	extendedJob.Spec.Tolerations = []v1core.Toleration{{Key: "batch", Operator: v1core.TolerationOpExists, Effect: v1core.TaintEffectNoSchedule}}
+	extendedJob.Spec.ImagePullSecrets = []v1core.LocalObjectReference{{Name: "registry"}}
+	extendedJob.Spec.Volumes = []v1core.Volume{{Name: "reports", VolumeSource: v1core.VolumeSource{EmptyDir: &v1core.EmptyDirVolumeSource{}}}}
+	extendedJob.Spec.Containers[0].Args = []string{"-P"}
+	extendedJob.Spec.Containers[0].WorkingDir = "/reports"
+	extendedJob.Spec.Containers[0].Env = []ketchv1.Env{
+		{Name: "FORMAT", Value: "csv"},
+		{Name: "DB_PASSWORD", ValueFrom: &ketchv1.EnvVarSource{SecretKeyRef: &ketchv1.SecretKeySelector{Name: "db", Key: "password"}}},
+	}
+	extendedJob.Spec.Containers[0].Resources = &v1core.ResourceRequirements{
+		Limits: v1core.ResourceList{v1core.ResourceMemory: resource.MustParse("256Mi")},
+	}
+	extendedJob.Spec.Containers[0].VolumeMounts = []v1core.VolumeMount{{Name: "reports", MountPath: "/reports"}}
+
+	tests := []struct {
+		name              string
+		job               *ketchv1.Job
+		apiVersions       chartutil.VersionSet
+		wantYamlsFilename string
+	}{
+		{
+			name:              "job",
+			job:               testJob,
+			wantYamlsFilename: "job",
+		},
+		{
+			name:              "job with pod settings",
+			job:               extendedJob,
+			wantYamlsFilename: "job-extended",
+		},
+		{
+			name:              "scheduled job",
+			job:               cronJob,
+			wantYamlsFilename: "job-cron",
+		},
+		{
+			name:              "scheduled job without batch/v1 cron jobs",
+			job:               cronJob,
+			apiVersions:       chartutil.VersionSet{"batch/v1", "batch/v1/Job", "batch/v1beta1", "batch/v1beta1/CronJob"},
+			wantYamlsFilename: "job-cron-v1beta1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobChart := NewJobChart(tt.job, WithTemplates(templates.JobTemplates))
+
+			expectedFilename := filepath.Join(chartDirectory, fmt.Sprintf("%s.yaml", tt.wantYamlsFilename))
+			actualFilename := filepath.Join(chartDirectory, fmt.Sprintf("%s.output.yaml", tt.wantYamlsFilename))
+
+			client := HelmClient{cfg: &action.Configuration{KubeClient: &fake.PrintingKubeClient{}, Releases: storage.Init(driver.NewMemory())}, namespace: "ketch-myframework", c: clientfake.NewClientBuilder().Build()}
+			release, err := client.UpdateChart(jobChart, NewJobChartConfig(*tt.job), func(install *action.Install) {
+				install.DryRun = true
+				install.ClientOnly = true
+				install.APIVersions = tt.apiVersions
+			})
+			require.Nil(t, err, "error = %v", err)
+
+			actualManifests := strings.TrimSpace(release.Manifest)
+			err = ioutil.WriteFile(actualFilename, []byte(actualManifests), 0755)
+			require.Nil(t, err)
+			expected, err := ioutil.ReadFile(expectedFilename)
+			require.Nil(t, err)
+			require.Equal(t, string(expected), actualManifests)
+		})
+	}
+}
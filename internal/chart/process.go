@@ -23,6 +23,10 @@ type process struct {
 	ServicePorts      []v1.ServicePort   `json:"servicePorts"`
 	PublicServicePort int32              `json:"publicServicePort,omitempty"`
 	Env               []ketchv1.Env      `json:"env"`
+	// AppEnv contains env variables of the app that aren't overridden by the process.
+	AppEnv []ketchv1.Env `json:"appEnv,omitempty"`
+	// Autoscaling if set, a HorizontalPodAutoscaler manages the number of replicas of the process.
+	Autoscaling *ketchv1.AutoscalingSpec `json:"autoscaling,omitempty"`
 
 	PodExtra podExtra `json:"extra"`
 }
@@ -57,14 +61,34 @@ func withUnits(units *int) processOption {
 }
 
 // withEnvs configures env variables of a process.
+// Env variables of the process take precedence over env variables of the app with the same name.
 // Additionally, the process will have port-related envs like "PORT". Check out "portEnvVariables" below.
-func withEnvs(envs []ketchv1.Env) processOption {
+func withEnvs(appEnvs []ketchv1.Env, processEnvs []ketchv1.Env) processOption {
 	return func(p *process) error {
-		p.Env = envs
+		p.Env = processEnvs
+		p.AppEnv = withoutEnvs(appEnvs, processEnvs)
 		return nil
 	}
 }
 
+// withoutEnvs returns env variables of envs that have no env variable with the same name in overrides.
+func withoutEnvs(envs []ketchv1.Env, overrides []ketchv1.Env) []ketchv1.Env {
+	if len(overrides) == 0 {
+		return envs
+	}
+	names := make(map[string]struct{}, len(overrides))
+	for _, env := range overrides {
+		names[env.Name] = struct{}{}
+	}
+	var result []ketchv1.Env
+	for _, env := range envs {
+		if _, ok := names[env.Name]; !ok {
+			result = append(result, env)
+		}
+	}
+	return result
+}
+
 func withCmd(cmd []string) processOption {
 	return func(p *process) error {
 		p.Cmd = cmd
@@ -118,6 +142,18 @@ func withResourceRequirements(rr *v1.ResourceRequirements) processOption {
 	}
 }
 
+// withAutoscaling must be applied after withUnits,
+// a stopped process is not autoscaled because the autoscaler would start it again.
+func withAutoscaling(autoscaling *ketchv1.AutoscalingSpec) processOption {
+	return func(p *process) error {
+		if p.Units == 0 {
+			return nil
+		}
+		p.Autoscaling = autoscaling
+		return nil
+	}
+}
+
 func withVolumes(volumes []v1.Volume) processOption {
 	return func(p *process) error {
 		p.PodExtra.Volumes = volumes
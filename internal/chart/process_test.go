@@ -147,6 +147,23 @@ func TestNewProcess(t *testing.T) {
 			},
 			wantErr: ErrPortsNotFound,
 		},
+		{
+			name:        "process envs override app envs",
+			processName: "worker",
+			isRoutable:  false,
+			options: []processOption{
+				withEnvs(
+					[]ketchv1.Env{{Name: "VAR", Value: "app"}, {Name: "APP_VAR", Value: "app"}},
+					[]ketchv1.Env{{Name: "VAR", Value: "process"}},
+				),
+			},
+			want: &process{
+				Name:   "worker",
+				Units:  ketchv1.DefaultNumberOfUnits,
+				Env:    []ketchv1.Env{{Name: "VAR", Value: "process"}},
+				AppEnv: []ketchv1.Env{{Name: "APP_VAR", Value: "app"}},
+			},
+		},
 		{
 			name:        "no withPortsAndProbes() - no container port",
 			processName: "worker",
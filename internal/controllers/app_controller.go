@@ -39,9 +39,12 @@ import (
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/tools/reference"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/canary"
 	"github.com/theketchio/ketch/internal/chart"
 	"github.com/theketchio/ketch/internal/templates"
 )
@@ -58,6 +61,10 @@ type AppReconciler struct {
 	// Group stands for k8s group of Ketch App CRD.
 	Group  string
 	Config *rest.Config
+	// CanaryAnalyzer checks metrics of canary deployments before each step.
+	CanaryAnalyzer CanaryAnalyzer
+	// IdleDetector checks traffic of applications with an idle policy.
+	IdleDetector IdleDetector
 }
 
 // timeNowFn knows how to get the current time.
@@ -72,6 +79,16 @@ type Helm interface {
 	DeleteChart(appName string) error
 }
 
+// CanaryAnalyzer checks metrics of a canary deployment.
+type CanaryAnalyzer interface {
+	Analyze(ctx context.Context, app *ketchv1.App) (*canary.Breach, error)
+}
+
+// IdleDetector checks if an application receives no traffic.
+type IdleDetector interface {
+	IsIdle(ctx context.Context, app *ketchv1.App, framework *ketchv1.Framework) (bool, error)
+}
+
 const (
 	replicaDepRevision            = "deployment.kubernetes.io/revision"
 	DeploymentProgressing         = "Progressing"
@@ -87,6 +104,7 @@ const (
 // +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="apps",resources=deployments,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="autoscaling",resources=horizontalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="networking.k8s.io",resources=ingresses,verbs=get;list;watch;create;update;patch;delete
@@ -118,6 +136,11 @@ func (r *AppReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	// checking traffic of an application which spec is reconciled already doesn't require updating its chart.
+	if left, checked := app.IdleCheckLeft(metav1.NewTime(r.Now())); checked && left == 0 && r.IdleDetector != nil && app.IsReconciled() {
+		return r.checkIdle(ctx, &app, logger)
+	}
+
 	var (
 		err    error
 		result ctrl.Result
@@ -130,6 +153,8 @@ func (r *AppReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		r.Recorder.Event(&app, v1.EventTypeWarning, ketchv1.AppReconcileOutcomeReason, outcome.String(err))
 	} else {
 		app.Status.Framework = scheduleResult.framework
+		app.Status.ObservedGeneration = app.Generation
+		app.RecordDeploymentHistory()
 		outcome := ketchv1.AppReconcileOutcome{AppName: app.Name, DeploymentCount: app.Spec.DeploymentsCount}
 		r.Recorder.Event(&app, v1.EventTypeNormal, ketchv1.AppReconcileOutcomeReason, outcome.String())
 	}
@@ -145,10 +170,25 @@ func (r *AppReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		result = ctrl.Result{RequeueAfter: app.Spec.Canary.StepTimeInteval}
 	}
 
+	// requeue once the grace period of a promoted blue-green deployment is over
+	if left := app.BlueGreenGracePeriodLeft(metav1.NewTime(r.Now())); left > 0 {
+		result = ctrl.Result{RequeueAfter: left}
+	}
+
 	if scheduleResult.useTimeout {
 		// set default timeout
 		result = ctrl.Result{RequeueAfter: reconcileTimeout}
 	}
+
+	// requeue when traffic of an application with an idle policy has to be checked
+	if left, checked := app.IdleCheckLeft(metav1.NewTime(r.Now())); checked && r.IdleDetector != nil {
+		if left < idleCheckInterval {
+			left = idleCheckInterval
+		}
+		if result.RequeueAfter == 0 || left < result.RequeueAfter {
+			result = ctrl.Result{RequeueAfter: left}
+		}
+	}
 	return result, err
 }
 
@@ -227,6 +267,39 @@ func (r *AppReconciler) reconcile(ctx context.Context, app *ketchv1.App, logger
 		}
 	}
 
+	// finish the canary deployment if it has been promoted by a user
+	if app.Spec.Canary.Active && app.Spec.Canary.Promoted && len(app.Spec.Deployments) > 1 {
+		app.DoPromote(r.Recorder)
+		if err := r.Update(ctx, app); err != nil {
+			return reconcileResult{
+				status:  v1.ConditionFalse,
+				message: fmt.Sprintf("failed to update app crd: %v", err),
+			}
+		}
+	}
+
+	// roll back the canary deployment if it has been aborted by a user
+	if app.Spec.Canary.Active && app.Spec.Canary.Aborted && len(app.Spec.Deployments) > 1 {
+		app.DoAbort(r.Recorder)
+		if err := r.Update(ctx, app); err != nil {
+			return reconcileResult{
+				status:  v1.ConditionFalse,
+				message: fmt.Sprintf("failed to update app crd: %v", err),
+			}
+		}
+	}
+
+	// remove the previous deployment once the grace period of a promoted blue-green deployment is over
+	if app.IsBlueGreenFinished(metav1.NewTime(r.Now())) {
+		app.FinishBlueGreen(r.Recorder)
+		if err := r.Update(ctx, app); err != nil {
+			return reconcileResult{
+				status:  v1.ConditionFalse,
+				message: fmt.Sprintf("failed to update app crd: %v", err),
+			}
+		}
+	}
+
 	// check for canary deployment
 	if app.Spec.Canary.Active {
 		// ensures that the canary deployment exists
@@ -263,6 +336,19 @@ func (r *AppReconciler) reconcile(ctx context.Context, app *ketchv1.App, logger
 			}
 		}
 
+		// check canary metrics before the next step and roll back if any of them is out of bounds.
+		if app.Spec.Canary.Analysis != nil && r.CanaryAnalyzer != nil && app.IsCanaryStepDue(metav1.NewTime(r.Now())) {
+			breach, err := r.CanaryAnalyzer.Analyze(ctx, app)
+			switch {
+			case err != nil:
+				app.DoAnalysisFailure(err, metav1.NewTime(r.Now()), r.Recorder)
+			case breach != nil:
+				app.DoAnalysisRollback(breach.Reason(), r.Recorder)
+			default:
+				app.Spec.Canary.AnalysisFailures = 0
+			}
+		}
+
 		// Once all pods are running then Perform canary deployment.
 		if err = app.DoCanary(metav1.NewTime(r.Now()), logger, r.Recorder); err != nil {
 			return reconcileResult{
@@ -383,13 +469,13 @@ func (r *AppReconciler) watchFunc(ctx context.Context, app *ketchv1.App, namespa
 		for i := range dep.Status.Conditions {
 			c := dep.Status.Conditions[i]
 			if c.Type == DeploymentProgressing && c.Reason == deadlineExeceededProgressCond {
-				deadlineExceededEvent := newAppDeploymentEvent(app, ketchv1.AppReconcileError, fmt.Sprintf("deployment %q exceeded its progress deadline", dep.Name), processName)
+				deadlineExceededEvent := newAppDeploymentEvent(app, ketchv1.AppReconcileError, fmt.Sprintf("deployment %q %s", dep.Name, ketchv1.AppReconcileProgressDeadlineError), processName)
 				recorder.AnnotatedEventf(app, deadlineExceededEvent.Annotations, v1.EventTypeWarning, deadlineExceededEvent.Reason, deadlineExceededEvent.Description)
 				return errors.Errorf("deployment %q exceeded its progress deadline", dep.Name)
 			}
 		}
 		if oldUpdatedReplicas != dep.Status.UpdatedReplicas {
-			unitsCreatedEvent := newAppDeploymentEvent(app, ketchv1.AppReconcileUpdate, fmt.Sprintf("%d of %d new units created", dep.Status.UpdatedReplicas, specReplicas), processName)
+			unitsCreatedEvent := newAppDeploymentEvent(app, ketchv1.AppReconcileUpdate, fmt.Sprintf(ketchv1.AppReconcileUnitsCreatedFormat, dep.Status.UpdatedReplicas, specReplicas), processName)
 			recorder.AnnotatedEventf(app, unitsCreatedEvent.Annotations, v1.EventTypeNormal, unitsCreatedEvent.Reason, unitsCreatedEvent.Description)
 		}
 
@@ -397,20 +483,20 @@ func (r *AppReconciler) watchFunc(ctx context.Context, app *ketchv1.App, namespa
 			err := checkPodStatus(r.Group, r.Client, app.Name, app.Spec.Deployments[len(app.Spec.Deployments)-1].Version)
 			if err == nil {
 				healthcheckTimeout = time.After(maxWaitTimeDuration)
-				healthcheckEvent := newAppDeploymentEvent(app, ketchv1.AppReconcileUpdate, fmt.Sprintf("waiting healthcheck on %d created units", specReplicas), processName)
+				healthcheckEvent := newAppDeploymentEvent(app, ketchv1.AppReconcileUpdate, fmt.Sprintf(ketchv1.AppReconcileHealthcheckWaitFormat, specReplicas), processName)
 				recorder.AnnotatedEventf(app, healthcheckEvent.Annotations, v1.EventTypeNormal, healthcheckEvent.Reason, healthcheckEvent.Description)
 			}
 		}
 
 		readyUnits := dep.Status.UpdatedReplicas - dep.Status.UnavailableReplicas
 		if oldReadyUnits != readyUnits && readyUnits >= 0 {
-			unitsReadyEvent := newAppDeploymentEvent(app, ketchv1.AppReconcileUpdate, fmt.Sprintf("%d of %d new units ready", readyUnits, specReplicas), processName)
+			unitsReadyEvent := newAppDeploymentEvent(app, ketchv1.AppReconcileUpdate, fmt.Sprintf(ketchv1.AppReconcileUnitsReadyFormat, readyUnits, specReplicas), processName)
 			recorder.AnnotatedEventf(app, unitsReadyEvent.Annotations, v1.EventTypeNormal, unitsReadyEvent.Reason, unitsReadyEvent.Description)
 		}
 
 		pendingTermination := dep.Status.Replicas - dep.Status.UpdatedReplicas
 		if oldPendingTermination != pendingTermination && pendingTermination > 0 {
-			pendingTerminationEvent := newAppDeploymentEvent(app, ketchv1.AppReconcileUpdate, fmt.Sprintf("%d old units pending termination", pendingTermination), processName)
+			pendingTerminationEvent := newAppDeploymentEvent(app, ketchv1.AppReconcileUpdate, fmt.Sprintf(ketchv1.AppReconcileUnitsPendingTerminationFormat, pendingTermination), processName)
 			recorder.AnnotatedEventf(app, pendingTerminationEvent.Annotations, v1.EventTypeNormal, pendingTerminationEvent.Reason, pendingTerminationEvent.Description)
 		}
 
@@ -430,16 +516,21 @@ func (r *AppReconciler) watchFunc(ctx context.Context, app *ketchv1.App, namespa
 			}
 			if isDeploymentEvent(msg, dep) {
 				appDeploymentEvent := appDeploymentEventFromWatchEvent(msg, app, processName)
-				recorder.AnnotatedEventf(app, appDeploymentEvent.Annotations, v1.EventTypeNormal, ketchv1.AppReconcileUpdate, appDeploymentEvent.Description)
+				// pod warnings like failed probes or image pull errors are recorded as warnings.
+				eventType := v1.EventTypeNormal
+				if msg.Object.(*v1.Event).Type == v1.EventTypeWarning {
+					eventType = v1.EventTypeWarning
+				}
+				recorder.AnnotatedEventf(app, appDeploymentEvent.Annotations, eventType, ketchv1.AppReconcileUpdate, appDeploymentEvent.Description)
 			}
 		case <-healthcheckTimeout:
 			err = createDeployTimeoutError(ctx, cli, app, time.Since(now), namespace, string(app.GroupVersionKind().Group), "healthcheck")
-			healthcheckTimeoutEvent := newAppDeploymentEvent(app, ketchv1.AppReconcileError, fmt.Sprintf("error waiting for healthcheck: %s", err.Error()), processName)
+			healthcheckTimeoutEvent := newAppDeploymentEvent(app, ketchv1.AppReconcileError, fmt.Sprintf("%s: %s", ketchv1.AppReconcileHealthcheckError, err.Error()), processName)
 			recorder.AnnotatedEventf(app, healthcheckTimeoutEvent.Annotations, v1.EventTypeWarning, healthcheckTimeoutEvent.Reason, healthcheckTimeoutEvent.Description)
 			return err
 		case <-timeout:
 			err = createDeployTimeoutError(ctx, cli, app, time.Since(now), namespace, string(app.GroupVersionKind().Group), "full rollout")
-			timeoutEvent := newAppDeploymentEvent(app, ketchv1.AppReconcileError, fmt.Sprintf("deployment timeout: %s", err.Error()), processName)
+			timeoutEvent := newAppDeploymentEvent(app, ketchv1.AppReconcileError, fmt.Sprintf("%s: %s", ketchv1.AppReconcileTimeoutError, err.Error()), processName)
 			recorder.AnnotatedEventf(app, timeoutEvent.Annotations, v1.EventTypeWarning, timeoutEvent.Reason, timeoutEvent.Description)
 			return err
 		case <-ctx.Done():
@@ -640,6 +731,9 @@ func (r *AppReconciler) deleteChart(ctx context.Context, appName string) error {
 		if err != nil {
 			return err
 		}
+		if err = r.deleteEnvSecret(ctx, appName, framework.Spec.NamespaceName); err != nil {
+			return err
+		}
 		patchedFramework := framework
 
 		patchedFramework.Status.Apps = make([]string, 0, len(patchedFramework.Status.Apps))
@@ -656,8 +750,42 @@ func (r *AppReconciler) deleteChart(ctx context.Context, appName string) error {
 
 }
 
+// checkIdle puts the application to sleep once it has received no traffic for its idle window.
+// Otherwise, it records the time of the check so that the next one happens a window later.
+func (r *AppReconciler) checkIdle(ctx context.Context, app *ketchv1.App, logger logr.Logger) (ctrl.Result, error) {
+	framework := ketchv1.Framework{}
+	if err := r.Get(ctx, types.NamespacedName{Name: app.Spec.Framework}, &framework); err != nil {
+		return ctrl.Result{}, err
+	}
+	idle, err := r.IdleDetector.IsIdle(ctx, app, &framework)
+	if err != nil {
+		logger.Error(err, "failed to check traffic of the app")
+		return ctrl.Result{RequeueAfter: idleCheckInterval}, nil
+	}
+	now := metav1.NewTime(r.Now())
+	if idle {
+		// the chart is updated by the reconciliation of the updated spec.
+		app.Sleep(now, r.Recorder)
+		return ctrl.Result{}, r.Update(ctx, app)
+	}
+	app.Status.LastIdleCheckTime = &now
+	if err := r.Status().Update(ctx, app); err != nil {
+		return ctrl.Result{}, err
+	}
+	left, _ := app.IdleCheckLeft(now)
+	return ctrl.Result{RequeueAfter: left}, nil
+}
+
+// deleteEnvSecret deletes the secret storing values of the app's secret env variables.
+func (r *AppReconciler) deleteEnvSecret(ctx context.Context, appName string, namespace string) error {
+	app := ketchv1.App{ObjectMeta: metav1.ObjectMeta{Name: appName}}
+	secret := v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: app.EnvSecretName(), Namespace: namespace}}
+	return client.IgnoreNotFound(r.Delete(ctx, &secret))
+}
+
 func (r *AppReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&ketchv1.App{}).
+		// status updates, like the time of the last idle check, don't require a reconciliation.
+		For(&ketchv1.App{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
 		Complete(r)
 }
@@ -12,6 +12,7 @@ import (
 	"helm.sh/helm/v3/pkg/release"
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -508,3 +509,115 @@ func TestIsDeploymentEvent(t *testing.T) {
 		})
 	}
 }
+
+func TestAppReconciler_deleteChart(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.Nil(t, clientgoscheme.AddToScheme(scheme))
+	require.Nil(t, ketchv1.AddToScheme()(scheme))
+	framework := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "myframework"},
+		Spec:       ketchv1.FrameworkSpec{NamespaceName: "ketch-myframework"},
+		Status:     ketchv1.FrameworkStatus{Apps: []string{"dashboard", "go-app"}},
+	}
+	envSecret := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "dashboard-env", Namespace: "ketch-myframework"}}
+	otherSecret := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "go-app-env", Namespace: "ketch-myframework"}}
+	cli := ctrlFake.NewClientBuilder().WithScheme(scheme).WithObjects(framework, envSecret, otherSecret).Build()
+	helmMock := &helm{}
+	r := AppReconciler{
+		Client: cli,
+		HelmFactoryFn: func(namespace string) (Helm, error) {
+			return helmMock, nil
+		},
+	}
+	require.Nil(t, r.deleteChart(context.Background(), "dashboard"))
+	require.Equal(t, []string{"dashboard"}, helmMock.deleteChartCalled)
+
+	err := cli.Get(context.Background(), client.ObjectKeyFromObject(envSecret), &v1.Secret{})
+	require.True(t, apierrors.IsNotFound(err))
+	require.Nil(t, cli.Get(context.Background(), client.ObjectKeyFromObject(otherSecret), &v1.Secret{}))
+
+	gotFramework := ketchv1.Framework{}
+	require.Nil(t, cli.Get(context.Background(), client.ObjectKeyFromObject(framework), &gotFramework))
+	require.Equal(t, []string{"go-app"}, gotFramework.Status.Apps)
+
+	// the env secret may not exist if the app had no secret env variables.
+	gotFramework.Status.Apps = append(gotFramework.Status.Apps, "dashboard")
+	require.Nil(t, cli.Status().Update(context.Background(), &gotFramework))
+	require.Nil(t, r.deleteChart(context.Background(), "dashboard"))
+}
+
+type idleDetector struct {
+	idle bool
+	err  error
+}
+
+func (d *idleDetector) IsIdle(ctx context.Context, app *ketchv1.App, framework *ketchv1.Framework) (bool, error) {
+	return d.idle, d.err
+}
+
+func TestAppReconciler_checkIdle(t *testing.T) {
+	now := time.Date(2021, 2, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		detector    *idleDetector
+		want        ctrl.Result
+		wantAsleep  bool
+		wantChecked bool
+	}{
+		{
+			name:        "app with traffic is checked again a window later",
+			detector:    &idleDetector{},
+			want:        ctrl.Result{RequeueAfter: 30 * time.Minute},
+			wantChecked: true,
+		},
+		{
+			name:       "idle app is put to sleep",
+			detector:   &idleDetector{idle: true},
+			wantAsleep: true,
+		},
+		{
+			name:     "failed check is retried",
+			detector: &idleDetector{err: errors.New("query returned no data")},
+			want:     ctrl.Result{RequeueAfter: idleCheckInterval},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			require.Nil(t, clientgoscheme.AddToScheme(scheme))
+			require.Nil(t, ketchv1.AddToScheme()(scheme))
+			app := &ketchv1.App{
+				ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+				Spec: ketchv1.AppSpec{
+					Framework:  "myframework",
+					IdlePolicy: &ketchv1.IdlePolicySpec{IdleMinutes: 30, PrometheusURL: "http://prometheus"},
+					Deployments: []ketchv1.AppDeploymentSpec{
+						{Version: 1, Processes: []ketchv1.ProcessSpec{{Name: "web", Units: conversions.IntPtr(2)}}},
+					},
+				},
+			}
+			framework := &ketchv1.Framework{ObjectMeta: metav1.ObjectMeta{Name: "myframework"}}
+			cli := ctrlFake.NewClientBuilder().WithScheme(scheme).WithObjects(app, framework).Build()
+			r := AppReconciler{
+				Client:       cli,
+				Recorder:     record.NewFakeRecorder(1),
+				IdleDetector: tt.detector,
+				Now:          func() time.Time { return now },
+			}
+			require.Nil(t, cli.Get(context.Background(), client.ObjectKeyFromObject(app), app))
+			result, err := r.checkIdle(context.Background(), app, ctrl.Log)
+			require.Nil(t, err)
+			require.Equal(t, tt.want, result)
+
+			gotApp := ketchv1.App{}
+			require.Nil(t, cli.Get(context.Background(), client.ObjectKeyFromObject(app), &gotApp))
+			require.Equal(t, tt.wantAsleep, gotApp.Spec.IdlePolicy.Asleep)
+			if tt.wantChecked {
+				require.NotNil(t, gotApp.Status.LastIdleCheckTime)
+				require.True(t, now.Equal(gotApp.Status.LastIdleCheckTime.Time))
+			} else {
+				require.Nil(t, gotApp.Status.LastIdleCheckTime)
+			}
+		})
+	}
+}
@@ -6,4 +6,6 @@ const (
 	KetchNamespace = "ketch-system"
 	// reconcileTimeout is the default timeout to trigger Operator reconcile
 	reconcileTimeout = 10 * time.Minute
+	// idleCheckInterval is how often traffic of an application with an idle policy is checked
+	idleCheckInterval = time.Minute
 )
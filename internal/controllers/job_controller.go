@@ -19,22 +19,32 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/tools/reference"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
 	"github.com/theketchio/ketch/internal/chart"
 	"github.com/theketchio/ketch/internal/templates"
+	"github.com/theketchio/ketch/internal/utils"
 )
 
 // JobReconciler reconciles a Job object
@@ -46,6 +56,9 @@ type JobReconciler struct {
 	HelmFactoryFn  helmFactoryFn
 	Recorder       record.EventRecorder
 	TemplateReader templates.Reader
+
+	// cronJobV1beta1 is true if the cluster doesn't serve batch/v1 CronJobs, they were added in kubernetes 1.21.
+	cronJobV1beta1 bool
 }
 
 // JobReconcileReason contains information about job reconcile
@@ -59,6 +72,9 @@ type JobReconcileReason struct {
 // +kubebuilder:rbac:groups=theketch.io,resources=jobs,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=theketch.io,resources=jobs/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=batch,resources=jobs/status,verbs=get
+// +kubebuilder:rbac:groups=batch,resources=cronjobs/status,verbs=get
 
 // Reconcile fetches a Job by name and updates helm charts with differences
 func (r *JobReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -74,6 +90,18 @@ func (r *JobReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	if job.Status.ObservedGeneration == job.Generation && isJobScheduled(job) {
+		// the chart is up to date, only the state of the job's runs has changed.
+		status := job.Status.DeepCopy()
+		if err := r.updateRunStatus(ctx, &job); err != nil {
+			return ctrl.Result{}, err
+		}
+		if equality.Semantic.DeepEqual(*status, job.Status) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, r.Status().Update(ctx, &job)
+	}
+
 	scheduleResult := r.reconcile(ctx, &job)
 	if scheduleResult.status == v1.ConditionFalse {
 		// we have to return an error to run reconcile again.
@@ -82,6 +110,10 @@ func (r *JobReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		r.Recorder.Event(&job, v1.EventTypeWarning, reason.String(), err.Error())
 	} else {
 		job.Status.Framework = scheduleResult.framework
+		job.Status.ObservedGeneration = job.Generation
+		if err := r.updateRunStatus(ctx, &job); err != nil {
+			return ctrl.Result{}, err
+		}
 		reason := JobReconcileReason{JobName: job.Name}
 		r.Recorder.Event(&job, v1.EventTypeNormal, reason.String(), "success")
 	}
@@ -94,11 +126,111 @@ func (r *JobReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *JobReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	_, err := mgr.GetRESTMapper().RESTMapping(schema.GroupKind{Group: batchv1.GroupName, Kind: "CronJob"}, batchv1.SchemeGroupVersion.Version)
+	if err != nil && !meta.IsNoMatchError(err) {
+		return err
+	}
+	r.cronJobV1beta1 = err != nil
+	var cronJob client.Object = &batchv1.CronJob{}
+	if r.cronJobV1beta1 {
+		cronJob = &batchv1beta1.CronJob{}
+	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&ketchv1.Job{}).
+		Watches(&source.Kind{Type: &batchv1.Job{}}, handler.EnqueueRequestsFromMapFunc(jobRequests)).
+		Watches(&source.Kind{Type: cronJob}, handler.EnqueueRequestsFromMapFunc(jobRequests)).
 		Complete(r)
 }
 
+// jobRequests maps a kubernetes Job or CronJob created by ketch to a request to reconcile its ketch Job.
+func jobRequests(obj client.Object) []reconcile.Request {
+	name, ok := obj.GetLabels()[utils.KetchAppNameLabel]
+	if !ok {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: name}}}
+}
+
+func isJobScheduled(job ketchv1.Job) bool {
+	c := job.Status.Condition(ketchv1.Scheduled)
+	return c != nil && c.Status == v1.ConditionTrue
+}
+
+// updateRunStatus copies the state of the last run of the job from the kubernetes Jobs created for it.
+func (r *JobReconciler) updateRunStatus(ctx context.Context, job *ketchv1.Job) error {
+	framework := ketchv1.Framework{}
+	if err := r.Get(ctx, types.NamespacedName{Name: job.Spec.Framework}, &framework); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if framework.Status.Namespace == nil {
+		return nil
+	}
+	namespace := framework.Status.Namespace.Name
+	jobs := batchv1.JobList{}
+	if err := r.List(ctx, &jobs, client.InNamespace(namespace), client.MatchingLabels{utils.KetchAppNameLabel: job.Name}); err != nil {
+		return err
+	}
+	var lastScheduleTime *metav1.Time
+	if job.Spec.IsScheduled() {
+		var err error
+		if lastScheduleTime, err = r.lastScheduleTime(ctx, types.NamespacedName{Namespace: namespace, Name: job.Name}); err != nil {
+			return err
+		}
+	}
+	setRunStatus(&job.Status, jobs.Items, lastScheduleTime)
+
+	var runs []batchv1.Job
+	for _, item := range jobs.Items {
+		if item.Labels[utils.KetchJobRunLabel] == "true" {
+			runs = append(runs, item)
+		}
+	}
+	expired := setRuns(&job.Status, runs, job.Spec.RunHistoryLimitOrDefault())
+	for i := range expired {
+		if err := r.Delete(ctx, &expired[i], client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lastScheduleTime returns the last time the CronJob was scheduled, nil if it doesn't exist.
+func (r *JobReconciler) lastScheduleTime(ctx context.Context, name types.NamespacedName) (*metav1.Time, error) {
+	if r.cronJobV1beta1 {
+		cronJob := batchv1beta1.CronJob{}
+		if err := r.Get(ctx, name, &cronJob); err != nil {
+			return nil, client.IgnoreNotFound(err)
+		}
+		return cronJob.Status.LastScheduleTime, nil
+	}
+	cronJob := batchv1.CronJob{}
+	if err := r.Get(ctx, name, &cronJob); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	return cronJob.Status.LastScheduleTime, nil
+}
+
+// setRunStatus sets the run fields of the status to the state of the most recently created job.
+func setRunStatus(status *ketchv1.JobStatus, jobs []batchv1.Job, lastScheduleTime *metav1.Time) {
+	status.LastScheduleTime = lastScheduleTime
+	var last *batchv1.Job
+	for i := range jobs {
+		if last == nil || last.CreationTimestamp.Before(&jobs[i].CreationTimestamp) {
+			last = &jobs[i]
+		}
+	}
+	if last == nil {
+		status.Active, status.Succeeded, status.Failed = 0, 0, 0
+		status.StartTime, status.CompletionTime = nil, nil
+		return
+	}
+	status.Active = last.Status.Active
+	status.Succeeded = last.Status.Succeeded
+	status.Failed = last.Status.Failed
+	status.StartTime = last.Status.StartTime
+	status.CompletionTime = last.Status.CompletionTime
+}
+
 func (r *JobReconciler) reconcile(ctx context.Context, job *ketchv1.Job) reconcileResult {
 	framework := ketchv1.Framework{}
 	if err := r.Get(ctx, types.NamespacedName{Name: job.Spec.Framework}, &framework); err != nil {
@@ -208,3 +340,48 @@ func (r *JobReconciler) deleteChart(ctx context.Context, jobName string) error {
 func (r *JobReconcileReason) String() string {
 	return r.JobName
 }
+
+// setRuns sets the runs of the status to the given on-demand runs, the most recent first.
+// setRuns returns finished runs exceeding the history limit, they are expected to be deleted.
+func setRuns(status *ketchv1.JobStatus, runs []batchv1.Job, historyLimit int) []batchv1.Job {
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[j].CreationTimestamp.Before(&runs[i].CreationTimestamp)
+	})
+	var expired []batchv1.Job
+	status.Runs = nil
+	finished := 0
+	for _, run := range runs {
+		phase := jobRunPhase(run)
+		if phase != ketchv1.JobRunRunning {
+			finished++
+			if finished > historyLimit {
+				expired = append(expired, run)
+				continue
+			}
+		}
+		status.Runs = append(status.Runs, ketchv1.JobRun{
+			Name:           run.Name,
+			Phase:          phase,
+			StartTime:      run.Status.StartTime,
+			CompletionTime: run.Status.CompletionTime,
+			Succeeded:      run.Status.Succeeded,
+			Failed:         run.Status.Failed,
+		})
+	}
+	return expired
+}
+
+func jobRunPhase(job batchv1.Job) ketchv1.JobRunPhase {
+	for _, c := range job.Status.Conditions {
+		if c.Status != v1.ConditionTrue {
+			continue
+		}
+		switch c.Type {
+		case batchv1.JobComplete:
+			return ketchv1.JobRunSucceeded
+		case batchv1.JobFailed:
+			return ketchv1.JobRunFailed
+		}
+	}
+	return ketchv1.JobRunRunning
+}
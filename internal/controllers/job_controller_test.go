@@ -8,11 +8,15 @@ import (
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlFake "sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
 	"github.com/theketchio/ketch/internal/utils/conversions"
@@ -102,3 +106,113 @@ func TestJobReconciler_Reconcile(t *testing.T) {
 		})
 	}
 }
+
+func Test_setRunStatus(t *testing.T) {
+	first := metav1.NewTime(time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC))
+	second := metav1.NewTime(time.Date(2021, 10, 1, 13, 0, 0, 0, time.UTC))
+	completed := metav1.NewTime(time.Date(2021, 10, 1, 13, 5, 0, 0, time.UTC))
+	tests := []struct {
+		name             string
+		status           ketchv1.JobStatus
+		jobs             []batchv1.Job
+		lastScheduleTime *metav1.Time
+		want             ketchv1.JobStatus
+	}{
+		{
+			name: "most recent run",
+			jobs: []batchv1.Job{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "backup-1", CreationTimestamp: second},
+					Status:     batchv1.JobStatus{Succeeded: 1, StartTime: &second, CompletionTime: &completed},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "backup-0", CreationTimestamp: first},
+					Status:     batchv1.JobStatus{Failed: 3, StartTime: &first},
+				},
+			},
+			lastScheduleTime: &second,
+			want: ketchv1.JobStatus{
+				Succeeded:        1,
+				StartTime:        &second,
+				CompletionTime:   &completed,
+				LastScheduleTime: &second,
+			},
+		},
+		{
+			name: "no runs",
+			status: ketchv1.JobStatus{
+				ObservedGeneration: 2,
+				Active:             1,
+				StartTime:          &first,
+			},
+			want: ketchv1.JobStatus{ObservedGeneration: 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRunStatus(&tt.status, tt.jobs, tt.lastScheduleTime)
+			require.Equal(t, tt.want, tt.status)
+		})
+	}
+}
+
+func TestJobReconciler_lastScheduleTime(t *testing.T) {
+	scheduled := metav1.NewTime(time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC))
+	name := types.NamespacedName{Namespace: "ketch-myframework", Name: "backup"}
+	cli := ctrlFake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(
+		&batchv1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{Namespace: name.Namespace, Name: name.Name},
+			Status:     batchv1.CronJobStatus{LastScheduleTime: &scheduled},
+		},
+		&batchv1beta1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{Namespace: name.Namespace, Name: "report"},
+			Status:     batchv1beta1.CronJobStatus{LastScheduleTime: &scheduled},
+		},
+	).Build()
+
+	r := JobReconciler{Client: cli}
+	got, err := r.lastScheduleTime(context.Background(), name)
+	require.Nil(t, err)
+	require.Equal(t, scheduled.Unix(), got.Unix())
+
+	r.cronJobV1beta1 = true
+	got, err = r.lastScheduleTime(context.Background(), types.NamespacedName{Namespace: name.Namespace, Name: "report"})
+	require.Nil(t, err)
+	require.Equal(t, scheduled.Unix(), got.Unix())
+
+	got, err = r.lastScheduleTime(context.Background(), types.NamespacedName{Namespace: name.Namespace, Name: "missing"})
+	require.Nil(t, err)
+	require.Nil(t, got)
+}
+
+func Test_setRuns(t *testing.T) {
+	at := func(hour int) metav1.Time {
+		return metav1.NewTime(time.Date(2021, 10, 1, hour, 0, 0, 0, time.UTC))
+	}
+	run := func(name string, hour int, condition batchv1.JobConditionType) batchv1.Job {
+		job := batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: at(hour)}}
+		if len(condition) > 0 {
+			job.Status.Conditions = []batchv1.JobCondition{{Type: condition, Status: v1.ConditionTrue}}
+		}
+		return job
+	}
+	runs := []batchv1.Job{
+		run("backup-run-a", 1, batchv1.JobComplete),
+		run("backup-run-d", 4, ""),
+		run("backup-run-b", 2, batchv1.JobFailed),
+		run("backup-run-c", 3, batchv1.JobComplete),
+	}
+	status := ketchv1.JobStatus{}
+	expired := setRuns(&status, runs, 2)
+	require.Equal(t, []ketchv1.JobRun{
+		{Name: "backup-run-d", Phase: ketchv1.JobRunRunning},
+		{Name: "backup-run-c", Phase: ketchv1.JobRunSucceeded},
+		{Name: "backup-run-b", Phase: ketchv1.JobRunFailed},
+	}, status.Runs)
+	require.Len(t, expired, 1)
+	require.Equal(t, "backup-run-a", expired[0].Name)
+
+	expired = setRuns(&status, nil, 2)
+	require.Nil(t, status.Runs)
+	require.Nil(t, expired)
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	registryv1 "github.com/google/go-containerregistry/pkg/v1"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -26,6 +27,7 @@ const (
 	minimumSteps         = 2
 	maximumSteps         = 100
 	defaultProcFile      = "Procfile"
+	defaultGracePeriod   = 10 * time.Minute
 )
 
 // Client represents go sdk k8s client operations that we need.
@@ -60,6 +62,30 @@ func (r Runner) Run(ctx context.Context, svc *Services) error {
 	return deployImage(ctx, svc, app, r.params)
 }
 
+// UpdateProcesses applies changes of the settings of the app's processes, like env variables and compute resources,
+// to the existing deployments without deploying a new image.
+func (r Runner) UpdateProcesses(ctx context.Context, svc *Services) error {
+	if !r.params.hasProcessSettings() {
+		return errors.New("no process settings to update")
+	}
+	var args updateAppCRDRequest
+	var err error
+	if args.version, err = r.params.getVersion(); err != nil {
+		return err
+	}
+	if args.process, err = r.params.getProcess(); err != nil {
+		return err
+	}
+	if envs, err := r.params.getProcessEnvs(); err == nil {
+		args.processEnvs = envs
+	}
+	if resources, err := r.params.getProcessResources(); err == nil {
+		args.processResources = resources
+	}
+	_, err = updateAppCRD(ctx, svc, r.params.appName, args)
+	return err
+}
+
 type appUpdater func(ctx context.Context, app *ketchv1.App, changed bool) error
 
 func getAppWithUpdater(ctx context.Context, client Client, cs *ChangeSet) (*ketchv1.App, appUpdater, error) {
@@ -155,7 +181,11 @@ func getUpdatedApp(ctx context.Context, client Client, cs *ChangeSet) (*ketchv1.
 
 		envs, err := cs.getEnvironments()
 		if err := assign(err, func() error {
-			app.Spec.Env = envs
+			merged, err := mergeSecretEnvs(app.Spec.Env, envs)
+			if err != nil {
+				return err
+			}
+			app.Spec.Env = merged
 			changed = true
 			return nil
 		}); err != nil {
@@ -176,6 +206,30 @@ func getUpdatedApp(ctx context.Context, client Client, cs *ChangeSet) (*ketchv1.
 	return app, err
 }
 
+// mergeSecretEnvs replaces the plaintext environment variables of an app with envs and keeps the ones backed by a secret,
+// so their secret keys aren't orphaned. Overriding a secret-backed variable with a plaintext value is rejected.
+func mergeSecretEnvs(current, envs []ketchv1.Env) ([]ketchv1.Env, error) {
+	secretEnvs := make(map[string]ketchv1.Env)
+	for _, env := range current {
+		if env.ValueFrom != nil {
+			secretEnvs[env.Name] = env
+		}
+	}
+	merged := make([]ketchv1.Env, 0, len(envs)+len(secretEnvs))
+	for _, env := range envs {
+		if _, ok := secretEnvs[env.Name]; ok {
+			return nil, fmt.Errorf("environment variable %q is stored in a secret, use \"ketch env unset\" before setting it with --%s", env.Name, FlagEnvironment)
+		}
+		merged = append(merged, env)
+	}
+	for _, env := range current {
+		if env.ValueFrom != nil {
+			merged = append(merged, env)
+		}
+	}
+	return merged, nil
+}
+
 func buildFromSource(ctx context.Context, svc *Services, app *ketchv1.App, appName, image, sourcePath string) error {
 	return svc.Builder(
 		ctx,
@@ -204,6 +258,14 @@ func deployImage(ctx context.Context, svc *Services, app *ketchv1.App, params *C
 		return errors.New("secure cnames require a framework.Ingress.ClusterIssuer to be specified")
 	}
 
+	if match, _ := params.getCanaryHeader(); match != nil {
+		switch framework.Spec.IngressController.IngressType {
+		case ketchv1.IstioIngressControllerType, ketchv1.TraefikIngressControllerType:
+		default:
+			return errors.New("--%s requires the istio or traefik ingress controller, framework %q uses %q", FlagCanaryHeader, app.Spec.Framework, framework.Spec.IngressController.IngressType)
+		}
+	}
+
 	image, _ := params.getImage()
 
 	fromSource := params.sourcePath != nil
@@ -243,8 +305,20 @@ func deployImage(ctx context.Context, svc *Services, app *ketchv1.App, params *C
 	updateRequest.configFile = imgConfig
 	interval, _ := params.getStepInterval()
 	updateRequest.stepTimeInterval = interval
+	analysis, _ := params.getCanaryAnalysis()
+	updateRequest.canaryAnalysis = analysis
+	if match, _ := params.getCanaryHeader(); match != nil {
+		updateRequest.canaryMatch = []ketchv1.RouteMatch{*match}
+	}
 	updateRequest.nextScheduledTime = time.Now().Add(interval)
 	updateRequest.started = time.Now()
+	if strategy, _ := params.getStrategy(); strategy == StrategyBlueGreen {
+		updateRequest.blueGreen = true
+		updateRequest.gracePeriod = defaultGracePeriod
+		if gracePeriod, err := params.getGracePeriod(); err == nil {
+			updateRequest.gracePeriod = gracePeriod
+		}
+	}
 	units, _ := params.getUnits()
 	updateRequest.units = units
 	version, _ := params.getVersion()
@@ -301,10 +375,38 @@ type updateAppCRDRequest struct {
 	nextScheduledTime time.Time
 	started           time.Time
 	stepTimeInterval  time.Duration
+	canaryAnalysis    *ketchv1.CanaryAnalysisSpec
+	canaryMatch       []ketchv1.RouteMatch
+	blueGreen         bool
+	gracePeriod       time.Duration
 	units             int
 	version           int
 	process           string
 	processes         *[]ketchv1.ProcessSpec
+	processEnvs       []ketchv1.Env
+	processResources  *corev1.ResourceRequirements
+}
+
+// setProcessSettings applies units, env variables and compute resources of the request
+// to the processes selected by its version and process.
+func setProcessSettings(app *ketchv1.App, args updateAppCRDRequest) error {
+	s := ketchv1.NewSelector(args.version, args.process)
+	if args.units > 0 {
+		if err := app.SetUnits(s, args.units); err != nil {
+			return err
+		}
+	}
+	if args.processEnvs != nil {
+		if err := app.SetProcessEnvs(s, args.processEnvs); err != nil {
+			return err
+		}
+	}
+	if args.processResources != nil {
+		if err := app.SetResources(s, args.processResources.Requests, args.processResources.Limits); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func updateAppCRD(ctx context.Context, svc *Services, appName string, args updateAppCRDRequest) (*ketchv1.App, error) {
@@ -313,33 +415,43 @@ func updateAppCRD(ctx context.Context, svc *Services, appName string, args updat
 		if err := svc.Client.Get(ctx, types.NamespacedName{Name: appName}, &updated); err != nil {
 			return errors.Wrap(err, "could not get app to deploy %q", appName)
 		}
-		updated.Spec.Version = args.appVersion
+		// a request without an image only updates settings of the existing deployments.
+		settingsOnly := len(args.image) == 0
+		if !settingsOnly {
+			updated.Spec.Version = args.appVersion
+		}
 
-		if len(updated.Spec.Deployments) > 1 && !updated.Spec.Canary.Active {
-			return errors.New("cannot have more than one deployment per app, unless canary")
+		if len(updated.Spec.Deployments) > 1 && !updated.Spec.Canary.Active && !updated.Spec.BlueGreen.Active {
+			return errors.New("cannot have more than one deployment per app, unless canary or blue-green")
 		}
 
-		// allow user to update units on canary deployments
-		if updated.Spec.Canary.Active {
-			if args.units > 0 {
-				s := ketchv1.NewSelector(args.version, args.process)
-				if err := updated.SetUnits(s, args.units); err != nil {
-					return err
-				}
+		// allow user to update units on canary and blue-green deployments
+		if updated.Spec.Canary.Active || updated.Spec.BlueGreen.Active || settingsOnly {
+			if err := setProcessSettings(&updated, args); err != nil {
+				return err
 			}
 
 			return svc.Client.Update(ctx, &updated)
 		}
 
 		// if the previous deployment's image is the same as the user provided image we want to reuse
-		// certain details like the number of units per process
+		// certain details like the number of units per process.
+		// A blue-green deployment always comes up with the units of the previous deployment.
 		var usePreviousDeploymentSpecs bool
 		if len(updated.Spec.Deployments) == 1 {
-			if updated.Spec.Deployments[0].Image == args.image {
+			if updated.Spec.Deployments[0].Image == args.image || args.blueGreen {
 				usePreviousDeploymentSpecs = true
 			}
 		}
 
+		// application.yaml is authoritative for env variables, resources and autoscaling of the processes it declares.
+		declaredProcesses := map[string]bool{}
+		if args.processes != nil {
+			for _, process := range *args.processes {
+				declaredProcesses[process.Name] = true
+			}
+		}
+
 		processes := make([]ketchv1.ProcessSpec, 0, len(args.procFile.Processes))
 		for _, processName := range args.procFile.SortedNames() {
 			cmd := args.procFile.Processes[processName]
@@ -357,6 +469,23 @@ func updateAppCRD(ctx context.Context, svc *Services, appName string, args updat
 					}
 				}
 			}
+			// per-process settings like env variables, resources and autoscaling are kept across deployments.
+			if len(updated.Spec.Deployments) > 0 {
+				latest := updated.Spec.Deployments[len(updated.Spec.Deployments)-1]
+				for _, previousProcess := range latest.Processes {
+					if previousProcess.Name == processName {
+						previous := previousProcess.DeepCopy()
+						ps.Volumes = previous.Volumes
+						ps.VolumeMounts = previous.VolumeMounts
+						ps.SecurityContext = previous.SecurityContext
+						if !declaredProcesses[processName] {
+							ps.Env = previous.Env
+							ps.Resources = previous.Resources
+							ps.Autoscaling = previous.Autoscaling
+						}
+					}
+				}
+			}
 
 			processes = append(processes, ps)
 		}
@@ -383,8 +512,8 @@ func updateAppCRD(ctx context.Context, svc *Services, appName string, args updat
 			ExposedPorts: exposedPorts,
 		}
 
-		// update deployment and version only for canary deployment or a new deployment
-		if !usePreviousDeploymentSpecs || args.steps > 1 {
+		// update deployment and version only for canary, blue-green deployment or a new deployment
+		if !usePreviousDeploymentSpecs || args.steps > 1 || args.blueGreen {
 			deploymentSpec.Version += 1
 			updated.Spec.DeploymentsCount += 1
 		}
@@ -392,6 +521,7 @@ func updateAppCRD(ctx context.Context, svc *Services, appName string, args updat
 		if args.steps > 1 {
 			nextScheduledTime := metav1.NewTime(args.nextScheduledTime)
 			started := metav1.NewTime(args.started)
+			// only the analysis provided with this deployment is used, the previous one is dropped with its canary spec.
 			updated.Spec.Canary = ketchv1.CanarySpec{
 				Steps:             args.steps,
 				StepWeight:        args.stepWeight,
@@ -400,30 +530,62 @@ func updateAppCRD(ctx context.Context, svc *Services, appName string, args updat
 				CurrentStep:       1,
 				Active:            true,
 				Started:           &started,
+				Analysis:          args.canaryAnalysis,
 			}
 
 			// set initial weight for canary deployment to zero.
 			// App controller will update the weight once all pods for canary will be on running state.
 			deploymentSpec.RoutingSettings.Weight = 0
+			// requests matching these rules are always routed to the canary deployment regardless of its weight.
+			deploymentSpec.RoutingSettings.Match = args.canaryMatch
 
 			// For a canary deployment, canary should be enabled by adding another deployment to the deployment list.
 			updated.Spec.Deployments = append(updated.Spec.Deployments, deploymentSpec)
+		} else if args.blueGreen {
+			updated.Spec.BlueGreen = ketchv1.BlueGreenSpec{
+				Active:      true,
+				GracePeriod: args.gracePeriod,
+			}
+
+			// the new deployment comes up with the same units as the current one but without any traffic,
+			// it is accessible via the preview cname until it is promoted.
+			deploymentSpec.RoutingSettings.Weight = 0
+			updated.Spec.Deployments = append(updated.Spec.Deployments, deploymentSpec)
 		} else {
 			updated.Spec.Deployments = []ketchv1.AppDeploymentSpec{deploymentSpec}
 		}
 
-		if args.units > 0 {
-			s := ketchv1.NewSelector(args.version, args.process)
-			if err := updated.SetUnits(s, args.units); err != nil {
+		// a deployment wakes up a sleeping app, the processes get their units back before units of the request are set.
+		if updated.IsAsleep() {
+			if err := updated.Wake(metav1.Now()); err != nil {
 				return err
 			}
 		}
+		if err := setProcessSettings(&updated, args); err != nil {
+			return err
+		}
 		if args.processes != nil {
 			for _, process := range *args.processes {
-				s := ketchv1.NewSelector(1, process.Name) // no process versions other than 1 w/ app.yaml (potentially multiple args.processes)
+				// processes of application.yaml are applied to the new deployment.
+				s := ketchv1.NewSelector(int(deploymentSpec.Version), process.Name)
 				if err := updated.SetUnits(s, *process.Units); err != nil {
 					return err
 				}
+				if len(process.Env) > 0 {
+					if err := updated.SetProcessEnvs(s, process.Env); err != nil {
+						return err
+					}
+				}
+				if process.Resources != nil {
+					if err := updated.SetResources(s, process.Resources.Requests, process.Resources.Limits); err != nil {
+						return err
+					}
+				}
+				if process.Autoscaling != nil {
+					if err := updated.SetAutoscaling(s, process.Autoscaling); err != nil {
+						return err
+					}
+				}
 			}
 		}
 		return svc.Client.Update(ctx, &updated)
@@ -3,12 +3,16 @@ package deploy
 import (
 	"context"
 	"testing"
+	"time"
 
 	registryv1 "github.com/google/go-containerregistry/pkg/v1"
 	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
 	"github.com/theketchio/ketch/internal/chart"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/stretchr/testify/require"
@@ -163,6 +167,56 @@ func Test_updateAppCRD(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "deploy after a canary deployment is aborted",
+			args: args{
+				ctx:     context.Background(),
+				appName: "test-app",
+				args: updateAppCRDRequest{
+					image: "shipa/go-sample:v3",
+					procFile: &chart.Procfile{
+						Processes:           map[string][]string{"web": {"/cnb/process/web"}},
+						RoutableProcessName: "web",
+					},
+					configFile: &registryv1.ConfigFile{
+						Config: registryv1.Config{
+							ExposedPorts: make(map[string]struct{}),
+						},
+					},
+				},
+				svc: &Services{
+					Client: func() *mockClient {
+						m := newMockClient()
+						m.app.Spec.DeploymentsCount = 2
+						m.app.Spec.Canary = ketchv1.CanarySpec{Active: true, Paused: true, Steps: 2, StepWeight: 50, CurrentStep: 1}
+						m.app.Spec.Deployments = []ketchv1.AppDeploymentSpec{
+							{
+								Image:           "shipa/go-sample:v1",
+								Version:         1,
+								RoutingSettings: ketchv1.RoutingSettings{Weight: 50},
+								Processes:       []ketchv1.ProcessSpec{{Name: "web", Cmd: []string{"/cnb/process/web"}}},
+							},
+							{
+								Image:           "shipa/go-sample:v2",
+								Version:         2,
+								RoutingSettings: ketchv1.RoutingSettings{Weight: 50},
+								Processes:       []ketchv1.ProcessSpec{{Name: "web", Cmd: []string{"/cnb/process/web"}}},
+							},
+						}
+						require.Nil(t, m.app.AbortCanary())
+						m.app.DoAbort(&record.FakeRecorder{})
+						return m
+					}(),
+				},
+			},
+			validate: func(t *testing.T, mock *mockClient) {
+				require.Len(t, mock.app.Spec.Deployments, 1)
+				require.Equal(t, "shipa/go-sample:v3", mock.app.Spec.Deployments[0].Image)
+				require.Equal(t, ketchv1.DeploymentVersion(3), mock.app.Spec.Deployments[0].Version)
+				require.False(t, mock.app.Spec.Canary.Paused)
+				require.False(t, mock.app.Spec.Canary.Aborted)
+			},
+		},
 		{
 			name: "multiple deployments, canary is true, and return updated units",
 			args: args{
@@ -307,6 +361,318 @@ func Test_updateAppCRD(t *testing.T) {
 				require.Equal(t, mock.app.Spec.Deployments[0].Version, ketchv1.DeploymentVersion(1))
 			},
 		},
+		{
+			name: "canary, analysis of the previous canary deployment isn't kept",
+			args: args{
+				ctx:     context.Background(),
+				appName: "test-app",
+				args: updateAppCRDRequest{
+					image:      "test/pack-test:v2",
+					steps:      2,
+					stepWeight: 50,
+					procFile: &chart.Procfile{
+						Processes:           map[string][]string{"worker": {"worker"}},
+						RoutableProcessName: "worker",
+					},
+					configFile: &registryv1.ConfigFile{
+						Config: registryv1.Config{
+							ExposedPorts: make(map[string]struct{}),
+						},
+					},
+				},
+				svc: &Services{
+					Client: func() *mockClient {
+						m := newMockClient()
+						m.app.Spec.DeploymentsCount = 1
+						m.app.Spec.Canary = ketchv1.CanarySpec{
+							Steps: 2,
+							Analysis: &ketchv1.CanaryAnalysisSpec{
+								PrometheusURL: "http://prometheus:9090",
+								Metrics:       []ketchv1.CanaryMetric{{Name: "error-rate", Query: "error_rate"}},
+							},
+						}
+						m.app.Spec.Deployments = []ketchv1.AppDeploymentSpec{
+							{
+								Image:           "test/pack-test:v1",
+								Version:         1,
+								RoutingSettings: ketchv1.RoutingSettings{Weight: 100},
+								Processes:       []ketchv1.ProcessSpec{{Name: "worker", Cmd: []string{"worker"}}},
+							},
+						}
+						return m
+					}(),
+				},
+			},
+			validate: func(t *testing.T, mock *mockClient) {
+				require.True(t, mock.app.Spec.Canary.Active)
+				require.Nil(t, mock.app.Spec.Canary.Analysis)
+				require.Len(t, mock.app.Spec.Deployments, 2)
+			},
+		},
+		{
+			name: "blue-green, add preview deployment with previous units",
+			args: args{
+				ctx:     context.Background(),
+				appName: "test-app",
+				args: updateAppCRDRequest{
+					image:       "test/pack-test:v2",
+					blueGreen:   true,
+					gracePeriod: 5 * time.Minute,
+					procFile: &chart.Procfile{
+						Processes:           map[string][]string{"worker": []string{"worker"}},
+						RoutableProcessName: "worker",
+					},
+					configFile: &registryv1.ConfigFile{
+						Config: registryv1.Config{
+							ExposedPorts: make(map[string]struct{}),
+						},
+					},
+				},
+				svc: &Services{
+					Client: func() *mockClient {
+						m := newMockClient()
+						m.app.Spec.DeploymentsCount = 1
+						m.app.Spec.Deployments = []ketchv1.AppDeploymentSpec{
+							{
+								Image:           "test/pack-test:v1",
+								Version:         1,
+								RoutingSettings: ketchv1.RoutingSettings{Weight: 100},
+								Processes: []ketchv1.ProcessSpec{
+									{
+										Name:  "worker",
+										Cmd:   []string{"worker"},
+										Units: intRef(3),
+									},
+								},
+							},
+						}
+						return m
+					}(),
+				},
+			},
+			validate: func(t *testing.T, mock *mockClient) {
+				require.Equal(t, ketchv1.BlueGreenSpec{Active: true, GracePeriod: 5 * time.Minute}, mock.app.Spec.BlueGreen)
+				require.Len(t, mock.app.Spec.Deployments, 2)
+				preview := mock.app.Spec.Deployments[1]
+				require.Equal(t, ketchv1.DeploymentVersion(2), preview.Version)
+				require.Equal(t, uint8(0), preview.RoutingSettings.Weight)
+				require.Equal(t, intRef(3), preview.Processes[0].Units)
+				require.Equal(t, uint8(100), mock.app.Spec.Deployments[0].RoutingSettings.Weight)
+			},
+		},
+		{
+			name: "new image keeps process settings and applies application.yaml processes",
+			args: args{
+				ctx:     context.Background(),
+				appName: "test-app",
+				args: updateAppCRDRequest{
+					image: "test/pack-test:v2",
+					procFile: &chart.Procfile{
+						Processes:           map[string][]string{"web": {"web"}, "worker": {"worker"}},
+						RoutableProcessName: "web",
+					},
+					configFile: &registryv1.ConfigFile{
+						Config: registryv1.Config{
+							ExposedPorts: make(map[string]struct{}),
+						},
+					},
+					processes: &[]ketchv1.ProcessSpec{
+						{
+							Name:  "worker",
+							Units: intRef(2),
+							Env:   []ketchv1.Env{{Name: "QUEUE", Value: "jobs"}},
+							Resources: &corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m")},
+							},
+						},
+					},
+				},
+				svc: &Services{
+					Client: func() *mockClient {
+						m := newMockClient()
+						m.app.Spec.DeploymentsCount = 1
+						m.app.Spec.Deployments = []ketchv1.AppDeploymentSpec{
+							{
+								Image:   "test/pack-test:v1",
+								Version: 1,
+								Processes: []ketchv1.ProcessSpec{
+									{
+										Name: "web",
+										Cmd:  []string{"web"},
+										Env:  []ketchv1.Env{{Name: "DEBUG", Value: "true"}},
+										Resources: &corev1.ResourceRequirements{
+											Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+										},
+										Autoscaling: &ketchv1.AutoscalingSpec{MinUnits: 2, MaxUnits: 5, TargetCPUUtilization: intRef32(80)},
+									},
+								},
+							},
+						}
+						return m
+					}(),
+				},
+			},
+			validate: func(t *testing.T, mock *mockClient) {
+				require.Len(t, mock.app.Spec.Deployments, 1)
+				deployment := mock.app.Spec.Deployments[0]
+				require.Equal(t, ketchv1.DeploymentVersion(2), deployment.Version)
+				web, worker := deployment.Processes[0], deployment.Processes[1]
+				require.Equal(t, []ketchv1.Env{{Name: "DEBUG", Value: "true"}}, web.Env)
+				require.Equal(t, corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")}, web.Resources.Limits)
+				require.Equal(t, &ketchv1.AutoscalingSpec{MinUnits: 2, MaxUnits: 5, TargetCPUUtilization: intRef32(80)}, web.Autoscaling)
+				require.Equal(t, intRef(2), worker.Units)
+				require.Equal(t, []ketchv1.Env{{Name: "QUEUE", Value: "jobs"}}, worker.Env)
+				require.Equal(t, corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m")}, worker.Resources.Requests)
+			},
+		},
+		{
+			name: "application.yaml replaces env, resources and autoscaling of the processes it declares",
+			args: args{
+				ctx:     context.Background(),
+				appName: "test-app",
+				args: updateAppCRDRequest{
+					image: "test/pack-test:v2",
+					procFile: &chart.Procfile{
+						Processes:           map[string][]string{"web": {"web"}},
+						RoutableProcessName: "web",
+					},
+					configFile: &registryv1.ConfigFile{
+						Config: registryv1.Config{
+							ExposedPorts: make(map[string]struct{}),
+						},
+					},
+					processes: &[]ketchv1.ProcessSpec{
+						{
+							Name:  "web",
+							Units: intRef(3),
+							Env:   []ketchv1.Env{{Name: "PORT", Value: "8080"}},
+							Resources: &corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m")},
+							},
+						},
+					},
+				},
+				svc: &Services{
+					Client: func() *mockClient {
+						m := newMockClient()
+						m.app.Spec.DeploymentsCount = 1
+						m.app.Spec.Deployments = []ketchv1.AppDeploymentSpec{
+							{
+								Image:   "test/pack-test:v1",
+								Version: 1,
+								Processes: []ketchv1.ProcessSpec{
+									{
+										Name: "web",
+										Cmd:  []string{"web"},
+										Env:  []ketchv1.Env{{Name: "DEBUG", Value: "true"}},
+										Resources: &corev1.ResourceRequirements{
+											Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+										},
+										Autoscaling: &ketchv1.AutoscalingSpec{MinUnits: 2, MaxUnits: 5, TargetCPUUtilization: intRef32(80)},
+									},
+								},
+							},
+						}
+						return m
+					}(),
+				},
+			},
+			validate: func(t *testing.T, mock *mockClient) {
+				require.Len(t, mock.app.Spec.Deployments, 1)
+				web := mock.app.Spec.Deployments[0].Processes[0]
+				require.Equal(t, intRef(3), web.Units)
+				require.Equal(t, []ketchv1.Env{{Name: "PORT", Value: "8080"}}, web.Env)
+				require.Equal(t, &corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m")},
+				}, web.Resources)
+				require.Nil(t, web.Autoscaling)
+			},
+		},
+		{
+			name: "deploy wakes up a sleeping app",
+			args: args{
+				ctx:     context.Background(),
+				appName: "test-app",
+				args: updateAppCRDRequest{
+					image: "shipa/go-sample:v1",
+					procFile: &chart.Procfile{
+						Processes:           map[string][]string{"web": {"/cnb/process/web"}},
+						RoutableProcessName: "web",
+					},
+					configFile: &registryv1.ConfigFile{
+						Config: registryv1.Config{
+							ExposedPorts: make(map[string]struct{}),
+						},
+					},
+				},
+				svc: &Services{
+					Client: func() *mockClient {
+						m := newMockClient()
+						m.app.Spec.DeploymentsCount = 1
+						m.app.Spec.Deployments = []ketchv1.AppDeploymentSpec{
+							{
+								Image:     "shipa/go-sample:v1",
+								Version:   1,
+								Processes: []ketchv1.ProcessSpec{{Name: "web", Cmd: []string{"/cnb/process/web"}, Units: intRef(0)}},
+							},
+						}
+						m.app.Spec.IdlePolicy = &ketchv1.IdlePolicySpec{
+							IdleMinutes: 30,
+							Asleep:      true,
+							Units:       []ketchv1.ProcessUnits{{Version: 1, Process: "web", Units: intRef(3)}},
+						}
+						return m
+					}(),
+				},
+			},
+			validate: func(t *testing.T, mock *mockClient) {
+				require.False(t, mock.app.IsAsleep())
+				require.Nil(t, mock.app.Spec.IdlePolicy.Units)
+				require.Len(t, mock.app.Spec.Deployments, 1)
+				require.Equal(t, intRef(3), mock.app.Spec.Deployments[0].Processes[0].Units)
+			},
+		},
+		{
+			name: "process settings without an image",
+			args: args{
+				ctx:     context.Background(),
+				appName: "test-app",
+				args: updateAppCRDRequest{
+					process:     "worker",
+					processEnvs: []ketchv1.Env{{Name: "QUEUE", Value: "jobs"}},
+					processResources: &corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+					},
+				},
+				svc: &Services{
+					Client: func() *mockClient {
+						m := newMockClient()
+						version := "v1"
+						m.app.Spec.Version = &version
+						m.app.Spec.DeploymentsCount = 1
+						m.app.Spec.Deployments = []ketchv1.AppDeploymentSpec{
+							{
+								Image:     "shipa/go-sample:v1",
+								Version:   1,
+								Processes: []ketchv1.ProcessSpec{{Name: "web"}, {Name: "worker"}},
+							},
+						}
+						return m
+					}(),
+				},
+			},
+			validate: func(t *testing.T, mock *mockClient) {
+				require.Equal(t, "v1", *mock.app.Spec.Version)
+				require.Len(t, mock.app.Spec.Deployments, 1)
+				require.Equal(t, ketchv1.DeploymentVersion(1), mock.app.Spec.Deployments[0].Version)
+				require.Equal(t, ketchv1.ProcessSpec{Name: "web"}, mock.app.Spec.Deployments[0].Processes[0])
+				worker := mock.app.Spec.Deployments[0].Processes[1]
+				require.Equal(t, []ketchv1.Env{{Name: "QUEUE", Value: "jobs"}}, worker.Env)
+				require.Equal(t, &corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+				}, worker.Resources)
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -399,3 +765,47 @@ func Test_makeProcfile(t *testing.T) {
 		})
 	}
 }
+
+func Test_mergeSecretEnvs(t *testing.T) {
+	secretEnv := ketchv1.Env{
+		Name:      "TOKEN",
+		ValueFrom: &ketchv1.EnvVarSource{SecretKeyRef: &ketchv1.SecretKeySelector{Name: "dashboard-env", Key: "TOKEN"}},
+	}
+	tests := []struct {
+		name    string
+		current []ketchv1.Env
+		envs    []ketchv1.Env
+		want    []ketchv1.Env
+		wantErr bool
+	}{
+		{
+			name:    "plaintext envs are replaced",
+			current: []ketchv1.Env{{Name: "FOO", Value: "1"}, {Name: "BAR", Value: "2"}},
+			envs:    []ketchv1.Env{{Name: "FOO", Value: "3"}},
+			want:    []ketchv1.Env{{Name: "FOO", Value: "3"}},
+		},
+		{
+			name:    "secret envs are kept",
+			current: []ketchv1.Env{{Name: "FOO", Value: "1"}, secretEnv},
+			envs:    []ketchv1.Env{{Name: "BAR", Value: "2"}},
+			want:    []ketchv1.Env{{Name: "BAR", Value: "2"}, secretEnv},
+		},
+		{
+			name:    "plaintext override of a secret env",
+			current: []ketchv1.Env{secretEnv},
+			envs:    []ketchv1.Env{{Name: "TOKEN", Value: "plain"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := mergeSecretEnvs(tt.current, tt.envs)
+			if tt.wantErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package deploy
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+)
+
+// FieldDrift describes a field of an application.yaml whose value differs from the app running in the cluster.
+type FieldDrift struct {
+	Field string `json:"field" yaml:"field"`
+	Local string `json:"local" yaml:"local"`
+	Live  string `json:"live" yaml:"live"`
+}
+
+const driftNoValue = "<none>"
+
+// AppName returns the name of the app the ChangeSet is applied to.
+func (c *ChangeSet) AppName() string {
+	return c.appName
+}
+
+// GetDrift compares the ChangeSet with the app running in the cluster and returns the fields that differ.
+// Fields which are not set in the ChangeSet are skipped because a deployment keeps their live values.
+func (c *ChangeSet) GetDrift(app ketchv1.App) ([]FieldDrift, error) {
+	var drift []FieldDrift
+	add := func(field, local, live string) {
+		if local != live {
+			drift = append(drift, FieldDrift{Field: field, Local: local, Live: live})
+		}
+	}
+
+	liveImage := driftNoValue
+	liveProcesses := map[string]ketchv1.ProcessSpec{}
+	if deployment := getLatestDeployment(app.Spec.Deployments); deployment != nil {
+		liveImage = deployment.Image
+		for _, process := range deployment.Processes {
+			liveProcesses[process.Name] = process
+		}
+	}
+	if image, err := c.getImage(); err == nil {
+		add("image", image, liveImage)
+	}
+	if c.framework != nil {
+		add("framework", *c.framework, app.Spec.Framework)
+	}
+	if c.builder != nil {
+		add("builder", *c.builder, app.Spec.Builder)
+	}
+	if c.cname != nil {
+		add("cname", formatCnames(*c.cname), formatCnames(app.Spec.Ingress.Cnames))
+	}
+
+	envs, err := c.getEnvironments()
+	if err := assign(err, func() error {
+		localEnvs, liveEnvs := envValues(envs), envValues(app.Spec.Env)
+		for _, name := range sortedKeys(localEnvs, liveEnvs) {
+			add(fmt.Sprintf("env %s", name), valueOrNone(localEnvs, name), valueOrNone(liveEnvs, name))
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if c.processes != nil {
+		localUnits := make(map[string]string, len(*c.processes))
+		for _, process := range *c.processes {
+			localUnits[process.Name] = processUnits(process)
+		}
+		liveUnits := make(map[string]string, len(liveProcesses))
+		for name, process := range liveProcesses {
+			liveUnits[name] = processUnits(process)
+		}
+		for _, name := range sortedKeys(localUnits, liveUnits) {
+			add(fmt.Sprintf("process %s units", name), valueOrNone(localUnits, name), valueOrNone(liveUnits, name))
+		}
+		// env variables and resources of the processes declared in the file replace the live ones on deployment.
+		for _, process := range *c.processes {
+			live := liveProcesses[process.Name]
+			localEnvs, liveEnvs := envValues(process.Env), envValues(live.Env)
+			for _, name := range sortedKeys(localEnvs, liveEnvs) {
+				add(fmt.Sprintf("process %s env %s", process.Name, name), valueOrNone(localEnvs, name), valueOrNone(liveEnvs, name))
+			}
+			add(fmt.Sprintf("process %s resources", process.Name), formatResources(process.Resources), formatResources(live.Resources))
+		}
+	}
+	return drift, nil
+}
+
+func processUnits(process ketchv1.ProcessSpec) string {
+	if process.Units == nil {
+		return strconv.Itoa(defaultAppUnit)
+	}
+	return strconv.Itoa(*process.Units)
+}
+
+// envValues returns values of env variables by their names, a value stored in a secret is described by its reference.
+func envValues(envs []ketchv1.Env) map[string]string {
+	values := make(map[string]string, len(envs))
+	for _, env := range envs {
+		values[env.Name] = env.Value
+		if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil {
+			values[env.Name] = fmt.Sprintf("secret %s/%s", env.ValueFrom.SecretKeyRef.Name, env.ValueFrom.SecretKeyRef.Key)
+		}
+	}
+	return values
+}
+
+// formatResources returns requests and limits in the "requests cpu=100m,memory=128Mi limits cpu=500m" format.
+func formatResources(resources *v1.ResourceRequirements) string {
+	if resources == nil {
+		return driftNoValue
+	}
+	var parts []string
+	for _, item := range []struct {
+		name string
+		list v1.ResourceList
+	}{{"requests", resources.Requests}, {"limits", resources.Limits}} {
+		if len(item.list) == 0 {
+			continue
+		}
+		values := make([]string, 0, len(item.list))
+		for name, quantity := range item.list {
+			values = append(values, fmt.Sprintf("%s=%s", name, quantity.String()))
+		}
+		sort.Strings(values)
+		parts = append(parts, fmt.Sprintf("%s %s", item.name, strings.Join(values, ",")))
+	}
+	if len(parts) == 0 {
+		return driftNoValue
+	}
+	return strings.Join(parts, " ")
+}
+
+func formatCnames(cnames ketchv1.CnameList) string {
+	if len(cnames) == 0 {
+		return driftNoValue
+	}
+	names := make([]string, 0, len(cnames))
+	for _, cname := range cnames {
+		if cname.Secure {
+			names = append(names, fmt.Sprintf("https://%s", cname.Name))
+			continue
+		}
+		names = append(names, fmt.Sprintf("http://%s", cname.Name))
+	}
+	return strings.Join(names, ",")
+}
+
+func sortedKeys(maps ...map[string]string) []string {
+	seen := map[string]struct{}{}
+	var keys []string
+	for _, m := range maps {
+		for key := range m {
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func valueOrNone(values map[string]string, key string) string {
+	if value, ok := values[key]; ok {
+		return value
+	}
+	return driftNoValue
+}
@@ -0,0 +1,145 @@
+package deploy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/utils/conversions"
+)
+
+func TestChangeSet_GetDrift(t *testing.T) {
+	app := ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "test"},
+		Spec: ketchv1.AppSpec{
+			Framework: "myframework",
+			Builder:   "heroku/buildpacks:20",
+			Env: []ketchv1.Env{
+				{Name: "FOO", Value: "bar"},
+				{Name: "LIVE_ONLY", Value: "1"},
+			},
+			Ingress: ketchv1.IngressSpec{
+				Cnames: ketchv1.CnameList{{Name: "test.10.10.10.20"}},
+			},
+			Deployments: []ketchv1.AppDeploymentSpec{
+				{
+					Image:   "gcr.io/shipa-ci/sample-go-app:v1",
+					Version: 1,
+					Processes: []ketchv1.ProcessSpec{
+						{Name: "web", Units: conversions.IntPtr(1)},
+						{Name: "worker", Units: conversions.IntPtr(2)},
+					},
+				},
+				{
+					Image:   "gcr.io/shipa-ci/sample-go-app:v2",
+					Version: 2,
+					Processes: []ketchv1.ProcessSpec{
+						{
+							Name:  "web",
+							Units: conversions.IntPtr(1),
+							Env:   []ketchv1.Env{{Name: "PORT", Value: "8080"}},
+							Resources: &corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+							},
+						},
+						{Name: "worker", Units: conversions.IntPtr(3)},
+					},
+				},
+			},
+		},
+	}
+	tests := []struct {
+		name string
+		yaml string
+		want []FieldDrift
+	}{
+		{
+			name: "no drift",
+			yaml: `name: test
+image: gcr.io/shipa-ci/sample-go-app:v2
+framework: myframework
+builder: heroku/buildpacks:20
+environment:
+  - FOO=bar
+  - LIVE_ONLY=1
+processes:
+  - name: web
+    environment:
+      - PORT=8080
+    resources:
+      requests:
+        cpu: 100m
+  - name: worker
+    units: 3
+cname:
+  dnsName: test.10.10.10.20
+`,
+		},
+		{
+			name: "drift",
+			yaml: `name: test
+image: gcr.io/shipa-ci/sample-go-app:v3
+framework: myframework
+builder: heroku/buildpacks:18
+environment:
+  - FOO=baz
+  - LOCAL_ONLY=2
+processes:
+  - name: web
+    units: 2
+    environment:
+      - PORT=9090
+    resources:
+      requests:
+        cpu: 200m
+        memory: 128Mi
+      limits:
+        cpu: 500m
+cname:
+  dnsName: test.10.10.10.20
+  secure: true
+`,
+			want: []FieldDrift{
+				{Field: "image", Local: "gcr.io/shipa-ci/sample-go-app:v3", Live: "gcr.io/shipa-ci/sample-go-app:v2"},
+				{Field: "builder", Local: "heroku/buildpacks:18", Live: "heroku/buildpacks:20"},
+				{Field: "cname", Local: "https://test.10.10.10.20", Live: "http://test.10.10.10.20"},
+				{Field: "env FOO", Local: "baz", Live: "bar"},
+				{Field: "env LIVE_ONLY", Local: "<none>", Live: "1"},
+				{Field: "env LOCAL_ONLY", Local: "2", Live: "<none>"},
+				{Field: "process web units", Local: "2", Live: "1"},
+				{Field: "process worker units", Local: "<none>", Live: "3"},
+				{Field: "process web env PORT", Local: "9090", Live: "8080"},
+				{Field: "process web resources", Local: "requests cpu=200m,memory=128Mi limits cpu=500m", Live: "requests cpu=100m"},
+			},
+		},
+		{
+			name: "fields missing in the file are skipped",
+			yaml: `name: test
+image: gcr.io/shipa-ci/sample-go-app:v2
+framework: otherframework
+`,
+			want: []FieldDrift{
+				{Field: "framework", Local: "otherframework", Live: "myframework"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "application.yaml")
+			require.Nil(t, os.WriteFile(filename, []byte(tt.yaml), 0600))
+			cs, err := GetDriftChangeSetFromYaml(filename)
+			require.Nil(t, err)
+			require.Equal(t, "test", cs.AppName())
+
+			drift, err := cs.GetDrift(*app.DeepCopy())
+			require.Nil(t, err)
+			require.Equal(t, tt.want, drift)
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+)
+
+// DryRun computes the app the deployment would produce without changing anything in the cluster.
+// Apps created or updated during the deployment are kept in memory and the returned app is the final result.
+func (r Runner) DryRun(ctx context.Context, svc *Services) (*ketchv1.App, error) {
+	if r.params.sourcePath != nil {
+		return nil, fmt.Errorf("dry run is not supported when deploying from source, use an image instead")
+	}
+	dryRunClient := newDryRunClient(svc.Client)
+	dryRunSvc := *svc
+	dryRunSvc.Client = dryRunClient
+
+	params := *r.params
+	params.wait = nil
+
+	app, err := getUpdatedApp(ctx, dryRunClient, &params)
+	if err != nil {
+		return nil, err
+	}
+	if err := deployImage(ctx, &dryRunSvc, app, &params); err != nil {
+		return nil, err
+	}
+	result, ok := dryRunClient.apps[app.Name]
+	if !ok {
+		return app, nil
+	}
+	return result.DeepCopy(), nil
+}
+
+// dryRunClient reads objects from the cluster but keeps created and updated apps in memory.
+type dryRunClient struct {
+	Client
+	apps map[string]*ketchv1.App
+}
+
+var _ Client = &dryRunClient{}
+
+func newDryRunClient(c Client) *dryRunClient {
+	return &dryRunClient{
+		Client: c,
+		apps:   map[string]*ketchv1.App{},
+	}
+}
+
+func (c *dryRunClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	if app, ok := obj.(*ketchv1.App); ok {
+		if stored, found := c.apps[key.Name]; found {
+			stored.DeepCopyInto(app)
+			return nil
+		}
+	}
+	return c.Client.Get(ctx, key, obj)
+}
+
+func (c *dryRunClient) Create(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+	return c.store(obj)
+}
+
+func (c *dryRunClient) Update(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+	return c.store(obj)
+}
+
+func (c *dryRunClient) store(obj client.Object) error {
+	app, ok := obj.(*ketchv1.App)
+	if !ok {
+		return fmt.Errorf("dry run doesn't support changing %T", obj)
+	}
+	c.apps[app.Name] = app.DeepCopy()
+	return nil
+}
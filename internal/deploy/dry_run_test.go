@@ -0,0 +1,114 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	registryv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/utils/conversions"
+)
+
+func TestRunner_DryRun(t *testing.T) {
+	image := "shipa/go-sample:v2"
+	framework := "myframework"
+	sourcePath := "src"
+	wait := true
+	timeout := "20s"
+
+	getImageConfig := func(ctx context.Context, args ImageConfigRequest) (*registryv1.ConfigFile, error) {
+		return &registryv1.ConfigFile{
+			Config: registryv1.Config{
+				Cmd: []string{"/bin/app"},
+			},
+		}, nil
+	}
+	existingApp := func(m *mockClient) {
+		m.app = &ketchv1.App{
+			ObjectMeta: metav1.ObjectMeta{Name: "myapp"},
+			Spec: ketchv1.AppSpec{
+				Framework:        framework,
+				DeploymentsCount: 1,
+				Deployments: []ketchv1.AppDeploymentSpec{
+					{
+						Image:   "shipa/go-sample:v1",
+						Version: 1,
+						Processes: []ketchv1.ProcessSpec{
+							{Name: "web", Units: conversions.IntPtr(1), Cmd: []string{"/bin/app"}},
+						},
+						RoutingSettings: ketchv1.RoutingSettings{Weight: 100},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		changeSet *ChangeSet
+		setup     func(m *mockClient)
+		wantImage string
+		wantErr   string
+	}{
+		{
+			name:      "update of an existing app",
+			changeSet: &ChangeSet{appName: "myapp", image: &image, wait: &wait, timeout: &timeout},
+			setup:     existingApp,
+			wantImage: image,
+		},
+		{
+			name:      "new app",
+			changeSet: &ChangeSet{appName: "myapp", image: &image, framework: &framework},
+			setup: func(m *mockClient) {
+				m.get[1] = func(_ *mockClient, _ runtime.Object) error {
+					return apierrors.NewNotFound(schema.GroupResource{}, "myapp")
+				}
+			},
+			wantImage: image,
+		},
+		{
+			name:      "deploy from source",
+			changeSet: &ChangeSet{appName: "myapp", image: &image, sourcePath: &sourcePath},
+			setup:     existingApp,
+			wantErr:   "dry run is not supported when deploying from source, use an image instead",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := newMockClient()
+			tt.setup(mock)
+			before := mock.app.DeepCopy()
+			svc := &Services{
+				Client:         mock,
+				GetImageConfig: getImageConfig,
+				Wait: func(ctx context.Context, svc *Services, app *ketchv1.App, timeout time.Duration) error {
+					return errors.New("dry run must not wait")
+				},
+				Writer: &bytes.Buffer{},
+			}
+
+			app, err := New(tt.changeSet).DryRun(context.Background(), svc)
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, "myapp", app.Name)
+			require.Equal(t, tt.wantImage, app.Spec.Deployments[len(app.Spec.Deployments)-1].Image)
+
+			require.Equal(t, 0, mock.createCounter)
+			require.Equal(t, 0, mock.updateCounter)
+			require.Equal(t, before, mock.app)
+		})
+	}
+}
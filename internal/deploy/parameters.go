@@ -7,9 +7,11 @@ import (
 	"io/ioutil"
 	"os"
 	"path"
+	"strings"
 	"time"
 
 	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
@@ -28,8 +30,14 @@ const (
 	FlagStrict         = "strict"
 	FlagSteps          = "steps"
 	FlagStepInterval   = "step-interval"
+	FlagCanaryAnalysis = "canary-analysis"
+	FlagCanaryHeader   = "canary-header"
+	FlagStrategy       = "strategy"
+	FlagGracePeriod    = "grace-period"
 	FlagWait           = "wait"
 	FlagTimeout        = "timeout"
+	FlagDryRun         = "dry-run"
+	FlagDiff           = "diff"
 	FlagDescription    = "description"
 	FlagEnvironment    = "env"
 	FlagFramework      = "framework"
@@ -47,6 +55,9 @@ const (
 	FlagFrameworkShort   = "k"
 
 	defaultYamlFile = "ketch.yaml"
+
+	StrategyCanary    = "canary"
+	StrategyBlueGreen = "blue-green"
 )
 
 var (
@@ -80,8 +91,14 @@ type Options struct {
 	StrictKetchYamlDecoding bool
 	Steps                   int
 	StepTimeInterval        string
+	CanaryAnalysisFileName  string
+	CanaryHeader            string
+	Strategy                string
+	GracePeriod             string
 	Wait                    bool
 	Timeout                 string
+	DryRun                  bool
+	Diff                    bool
 	AppSourcePath           string
 	SubPaths                []string
 
@@ -105,6 +122,10 @@ type ChangeSet struct {
 	ketchYamlFileName    *string
 	steps                *int
 	stepTimeInterval     *string
+	canaryAnalysisFile   *string
+	canaryHeader         *string
+	strategy             *string
+	gracePeriod          *string
 	wait                 *bool
 	timeout              *string
 	subPaths             *[]string
@@ -122,6 +143,8 @@ type ChangeSet struct {
 	units                *int
 	version              *int
 	process              *string
+	processEnvs          *[]ketchv1.Env
+	processResources     *corev1.ResourceRequirements
 }
 
 func (o Options) GetChangeSet(flags *pflag.FlagSet) *ChangeSet {
@@ -148,6 +171,18 @@ func (o Options) GetChangeSet(flags *pflag.FlagSet) *ChangeSet {
 		FlagStepInterval: func(c *ChangeSet) {
 			c.stepTimeInterval = &o.StepTimeInterval
 		},
+		FlagCanaryAnalysis: func(c *ChangeSet) {
+			c.canaryAnalysisFile = &o.CanaryAnalysisFileName
+		},
+		FlagCanaryHeader: func(c *ChangeSet) {
+			c.canaryHeader = &o.CanaryHeader
+		},
+		FlagStrategy: func(c *ChangeSet) {
+			c.strategy = &o.Strategy
+		},
+		FlagGracePeriod: func(c *ChangeSet) {
+			c.gracePeriod = &o.GracePeriod
+		},
 		FlagWait: func(c *ChangeSet) {
 			c.wait = &o.Wait
 		},
@@ -190,6 +225,36 @@ func (o Options) GetChangeSet(flags *pflag.FlagSet) *ChangeSet {
 	return &cs
 }
 
+// ProcessOptions describe changes of the settings of an app's processes that are applied without a new deployment.
+type ProcessOptions struct {
+	AppName string
+	// Version selects a deployment, all deployments are selected if Version is 0.
+	Version int
+	// Process selects a process, all processes are selected if Process is empty.
+	Process  string
+	Envs     []ketchv1.Env
+	Requests corev1.ResourceList
+	Limits   corev1.ResourceList
+}
+
+func (o ProcessOptions) GetChangeSet() *ChangeSet {
+	var cs ChangeSet
+	cs.appName = o.AppName
+	if o.Version != 0 {
+		cs.version = &o.Version
+	}
+	if o.Process != "" {
+		cs.process = &o.Process
+	}
+	if o.Envs != nil {
+		cs.processEnvs = &o.Envs
+	}
+	if o.Requests != nil || o.Limits != nil {
+		cs.processResources = &corev1.ResourceRequirements{Requests: o.Requests, Limits: o.Limits}
+	}
+	return &cs
+}
+
 func (c *ChangeSet) getDescription() (string, error) {
 	if c.description == nil {
 		return "", newMissingError(FlagDescription)
@@ -275,6 +340,101 @@ func (c *ChangeSet) getStepWeight() (uint8, error) {
 	return uint8(100 / steps), nil
 }
 
+func (c *ChangeSet) getCanaryAnalysis() (*ketchv1.CanaryAnalysisSpec, error) {
+	if c.canaryAnalysisFile == nil {
+		return nil, newMissingError(FlagCanaryAnalysis)
+	}
+	if c.steps == nil {
+		return nil, fmt.Errorf("%w %s must be used with %s flag",
+			newInvalidUsageError(FlagCanaryAnalysis), FlagCanaryAnalysis, FlagSteps)
+	}
+	content, err := ioutil.ReadFile(*c.canaryAnalysisFile)
+	if err != nil {
+		return nil, fmt.Errorf("%w %v", newInvalidValueError(FlagCanaryAnalysis), err)
+	}
+	var analysis ketchv1.CanaryAnalysisSpec
+	if err := yaml.Unmarshal(content, &analysis, yaml.DisallowUnknownFields); err != nil {
+		return nil, fmt.Errorf("%w %v", newInvalidValueError(FlagCanaryAnalysis), err)
+	}
+	if analysis.PrometheusURL == "" || len(analysis.Metrics) == 0 {
+		return nil, fmt.Errorf("%w prometheusURL and metrics are required", newInvalidValueError(FlagCanaryAnalysis))
+	}
+	for _, metric := range analysis.Metrics {
+		if metric.Name == "" || metric.Query == "" {
+			return nil, fmt.Errorf("%w name and query are required for each metric", newInvalidValueError(FlagCanaryAnalysis))
+		}
+		if metric.Min == nil && metric.Max == nil {
+			return nil, fmt.Errorf("%w metric %s must have min or max", newInvalidValueError(FlagCanaryAnalysis), metric.Name)
+		}
+	}
+	switch analysis.NoData {
+	case "", ketchv1.CanaryNoDataPass, ketchv1.CanaryNoDataFail:
+	default:
+		return nil, fmt.Errorf("%w noData must be %s or %s", newInvalidValueError(FlagCanaryAnalysis), ketchv1.CanaryNoDataPass, ketchv1.CanaryNoDataFail)
+	}
+	if analysis.FailureLimit < 0 {
+		return nil, fmt.Errorf("%w failureLimit must be positive", newInvalidValueError(FlagCanaryAnalysis))
+	}
+	return &analysis, nil
+}
+
+func (c *ChangeSet) getCanaryHeader() (*ketchv1.RouteMatch, error) {
+	if c.canaryHeader == nil {
+		return nil, newMissingError(FlagCanaryHeader)
+	}
+	if c.steps == nil {
+		return nil, fmt.Errorf("%w %s must be used with %s flag",
+			newInvalidUsageError(FlagCanaryHeader), FlagCanaryHeader, FlagSteps)
+	}
+	parts := strings.SplitN(*c.canaryHeader, "=", 2)
+	if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+		return nil, fmt.Errorf("%w %s must be in NAME=VALUE format",
+			newInvalidValueError(FlagCanaryHeader), FlagCanaryHeader)
+	}
+	return &ketchv1.RouteMatch{
+		Type:  ketchv1.RouteMatchHeader,
+		Name:  strings.TrimSpace(parts[0]),
+		Value: parts[1],
+	}, nil
+}
+
+func (c *ChangeSet) getStrategy() (string, error) {
+	if c.strategy == nil {
+		return "", newMissingError(FlagStrategy)
+	}
+	switch *c.strategy {
+	case StrategyCanary:
+		if c.steps == nil {
+			return "", fmt.Errorf("%w %s strategy must be used with %s flag",
+				newInvalidUsageError(FlagStrategy), StrategyCanary, FlagSteps)
+		}
+	case StrategyBlueGreen:
+		if c.steps != nil {
+			return "", fmt.Errorf("%w %s strategy can't be used with %s flag",
+				newInvalidUsageError(FlagStrategy), StrategyBlueGreen, FlagSteps)
+		}
+	default:
+		return "", fmt.Errorf("%w %s must be either %s or %s",
+			newInvalidValueError(FlagStrategy), FlagStrategy, StrategyCanary, StrategyBlueGreen)
+	}
+	return *c.strategy, nil
+}
+
+func (c *ChangeSet) getGracePeriod() (time.Duration, error) {
+	if c.gracePeriod == nil {
+		return 0, newMissingError(FlagGracePeriod)
+	}
+	if c.strategy == nil || *c.strategy != StrategyBlueGreen {
+		return 0, fmt.Errorf("%w %s must be used with %s %s",
+			newInvalidUsageError(FlagGracePeriod), FlagGracePeriod, FlagStrategy, StrategyBlueGreen)
+	}
+	dur, err := time.ParseDuration(*c.gracePeriod)
+	if err != nil || dur < 0 {
+		return 0, newInvalidValueError(FlagGracePeriod)
+	}
+	return dur, nil
+}
+
 func (c *ChangeSet) getEnvironments() ([]ketchv1.Env, error) {
 	if c.envs == nil {
 		return nil, newMissingError(FlagEnvironment)
@@ -341,7 +501,7 @@ func (c *ChangeSet) getVersion() (int, error) {
 	if c.version == nil {
 		return 0, nil
 	}
-	if c.units == nil {
+	if !c.hasProcessSettings() {
 		return 0, fmt.Errorf("%w %s must be used with %s flag",
 			newInvalidUsageError(FlagVersion), FlagVersion, FlagUnits)
 	}
@@ -356,13 +516,32 @@ func (c *ChangeSet) getProcess() (string, error) {
 	if c.process == nil {
 		return "", nil
 	}
-	if c.units == nil {
+	if !c.hasProcessSettings() {
 		return "", fmt.Errorf("%w %s must be used with %s flag",
 			newInvalidUsageError(FlagProcess), FlagProcess, FlagUnits)
 	}
 	return *c.process, nil
 }
 
+// hasProcessSettings returns true if the change set updates settings of the processes selected by version and process.
+func (c *ChangeSet) hasProcessSettings() bool {
+	return c.units != nil || c.processEnvs != nil || c.processResources != nil
+}
+
+func (c *ChangeSet) getProcessEnvs() ([]ketchv1.Env, error) {
+	if c.processEnvs == nil {
+		return nil, newMissingError("process env")
+	}
+	return *c.processEnvs, nil
+}
+
+func (c *ChangeSet) getProcessResources() (*corev1.ResourceRequirements, error) {
+	if c.processResources == nil {
+		return nil, newMissingError("process resources")
+	}
+	return c.processResources, nil
+}
+
 func (c *ChangeSet) getBuildPacks() ([]string, error) {
 	if c.buildPacks == nil {
 		return nil, newMissingError(FlagBuildPacks)
@@ -1,15 +1,24 @@
 package deploy
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
 )
 
 func intRef(i int) *int {
 	return &i
 }
 
+func intRef32(i int32) *int32 {
+	return &i
+}
+
 func TestChangeSet_getStepWeight(t *testing.T) {
 
 	tests := []struct {
@@ -48,3 +57,217 @@ func TestChangeSet_getStepWeight(t *testing.T) {
 		})
 	}
 }
+
+func TestChangeSet_getCanaryAnalysis(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(content string) *string {
+		f, err := os.CreateTemp(dir, "analysis-*.yaml")
+		require.Nil(t, err)
+		defer f.Close()
+		_, err = f.WriteString(content)
+		require.Nil(t, err)
+		name := f.Name()
+		return &name
+	}
+	missingFile := filepath.Join(dir, "missing.yaml")
+
+	tests := []struct {
+		name        string
+		set         ChangeSet
+		wantMetrics int
+		wantErr     string
+	}{
+		{
+			name: "happy path",
+			set: ChangeSet{
+				steps: intRef(4),
+				canaryAnalysisFile: writeFile(`prometheusURL: http://prometheus:9090
+noData: fail
+failureLimit: 5
+metrics:
+- name: error-rate
+  query: error_rate{destination_app="{{ .App }}"}
+  max: "0.05"
+`),
+			},
+			wantMetrics: 1,
+		},
+		{
+			name:    "error - no analysis",
+			set:     ChangeSet{steps: intRef(4)},
+			wantErr: `"canary-analysis" missing`,
+		},
+		{
+			name:    "error - no steps",
+			set:     ChangeSet{canaryAnalysisFile: &missingFile},
+			wantErr: `"canary-analysis" used improperly canary-analysis must be used with steps flag`,
+		},
+		{
+			name: "error - no bounds",
+			set: ChangeSet{
+				steps: intRef(4),
+				canaryAnalysisFile: writeFile(`prometheusURL: http://prometheus:9090
+metrics:
+- name: error-rate
+  query: error_rate
+`),
+			},
+			wantErr: `"canary-analysis" invalid value metric error-rate must have min or max`,
+		},
+		{
+			name: "error - unknown no data policy",
+			set: ChangeSet{
+				steps: intRef(4),
+				canaryAnalysisFile: writeFile(`prometheusURL: http://prometheus:9090
+noData: ignore
+metrics:
+- name: error-rate
+  query: error_rate
+  max: "0.05"
+`),
+			},
+			wantErr: `"canary-analysis" invalid value noData must be pass or fail`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis, err := tt.set.getCanaryAnalysis()
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.Nil(t, err)
+			require.Len(t, analysis.Metrics, tt.wantMetrics)
+		})
+	}
+}
+
+func TestChangeSet_getCanaryHeader(t *testing.T) {
+	strRef := func(s string) *string { return &s }
+	tests := []struct {
+		name    string
+		set     ChangeSet
+		want    *ketchv1.RouteMatch
+		wantErr string
+	}{
+		{
+			name: "happy path",
+			set:  ChangeSet{steps: intRef(4), canaryHeader: strRef("X-Canary=true")},
+			want: &ketchv1.RouteMatch{Type: ketchv1.RouteMatchHeader, Name: "X-Canary", Value: "true"},
+		},
+		{
+			name:    "error - no header",
+			set:     ChangeSet{steps: intRef(4)},
+			wantErr: `"canary-header" missing`,
+		},
+		{
+			name:    "error - no steps",
+			set:     ChangeSet{canaryHeader: strRef("X-Canary=true")},
+			wantErr: `"canary-header" used improperly canary-header must be used with steps flag`,
+		},
+		{
+			name:    "error - bad format",
+			set:     ChangeSet{steps: intRef(4), canaryHeader: strRef("X-Canary")},
+			wantErr: `"canary-header" invalid value canary-header must be in NAME=VALUE format`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, err := tt.set.getCanaryHeader()
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.want, match)
+		})
+	}
+}
+
+func TestChangeSet_getStrategy(t *testing.T) {
+	strRef := func(s string) *string { return &s }
+	tests := []struct {
+		name    string
+		set     ChangeSet
+		want    string
+		wantErr string
+	}{
+		{
+			name: "blue-green",
+			set:  ChangeSet{strategy: strRef(StrategyBlueGreen)},
+			want: StrategyBlueGreen,
+		},
+		{
+			name: "canary",
+			set:  ChangeSet{strategy: strRef(StrategyCanary), steps: intRef(4)},
+			want: StrategyCanary,
+		},
+		{
+			name:    "error - canary without steps",
+			set:     ChangeSet{strategy: strRef(StrategyCanary)},
+			wantErr: `"strategy" used improperly canary strategy must be used with steps flag`,
+		},
+		{
+			name:    "error - blue-green with steps",
+			set:     ChangeSet{strategy: strRef(StrategyBlueGreen), steps: intRef(4)},
+			wantErr: `"strategy" used improperly blue-green strategy can't be used with steps flag`,
+		},
+		{
+			name:    "error - unknown strategy",
+			set:     ChangeSet{strategy: strRef("recreate")},
+			wantErr: `"strategy" invalid value strategy must be either canary or blue-green`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy, err := tt.set.getStrategy()
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.want, strategy)
+		})
+	}
+}
+
+func TestChangeSet_getGracePeriod(t *testing.T) {
+	strRef := func(s string) *string { return &s }
+	tests := []struct {
+		name    string
+		set     ChangeSet
+		want    time.Duration
+		wantErr string
+	}{
+		{
+			name: "happy path",
+			set:  ChangeSet{strategy: strRef(StrategyBlueGreen), gracePeriod: strRef("30m")},
+			want: 30 * time.Minute,
+		},
+		{
+			name:    "error - no blue-green strategy",
+			set:     ChangeSet{gracePeriod: strRef("30m")},
+			wantErr: `"grace-period" used improperly grace-period must be used with strategy blue-green`,
+		},
+		{
+			name:    "error - invalid duration",
+			set:     ChangeSet{strategy: strRef(StrategyBlueGreen), gracePeriod: strRef("soon")},
+			wantErr: `"grace-period" invalid value`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gracePeriod, err := tt.set.getGracePeriod()
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.want, gracePeriod)
+		})
+	}
+}
@@ -0,0 +1,262 @@
+package deploy
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/errors"
+)
+
+// maxProgressWarnings is the number of the most recent pod warnings shown with the progress of a deployment.
+const maxProgressWarnings = 3
+
+// deploymentProgress tracks events of the latest deployment of an app.
+type deploymentProgress struct {
+	appName         string
+	deploymentCount int
+	version         int
+	started         time.Time
+	reconciled      bool
+	// processes are watched by the controller until their units are ready, it's empty for a canary deployment.
+	processes []*processProgress
+	// canary is nil if the deployment isn't a canary deployment.
+	canary   *canaryProgress
+	warnings []TimelineEntry
+}
+
+type processProgress struct {
+	name        string
+	desired     int
+	updated     int
+	ready       int
+	terminating int
+	healthcheck bool
+	done        bool
+}
+
+type canaryProgress struct {
+	step     int
+	steps    int
+	weight   uint8
+	finished bool
+}
+
+func newDeploymentProgress(app *ketchv1.App, started time.Time) *deploymentProgress {
+	p := &deploymentProgress{
+		appName:         app.Name,
+		deploymentCount: app.Spec.DeploymentsCount,
+		started:         started,
+	}
+	if len(app.Spec.Deployments) == 0 {
+		return p
+	}
+	latest := app.Spec.Deployments[len(app.Spec.Deployments)-1]
+	p.version = int(latest.Version)
+	// the controller doesn't watch units of a canary deployment, its steps are performed later.
+	if app.Spec.Canary.Active {
+		p.canary = &canaryProgress{steps: app.Spec.Canary.Steps, weight: latest.RoutingSettings.Weight}
+		return p
+	}
+	for _, process := range latest.Processes {
+		progress := &processProgress{name: process.Name}
+		if process.Units != nil {
+			progress.desired = *process.Units
+		}
+		p.processes = append(p.processes, progress)
+	}
+	return p
+}
+
+// handle updates the progress with the event.
+// It returns a timeline entry if the event is about the latest deployment and true once the deployment is rolled out.
+func (p *deploymentProgress) handle(evt corev1.Event) (*TimelineEntry, bool, error) {
+	if evt.Reason == ketchv1.AppReconcileOutcomeReason {
+		outcome, err := ketchv1.ParseAppReconcileOutcome(evt.Message)
+		if err != nil {
+			return nil, false, err
+		}
+		if outcome.AppName != p.appName || outcome.DeploymentCount != p.deploymentCount {
+			return nil, false, nil
+		}
+		if evt.Type == corev1.EventTypeWarning {
+			return nil, false, errors.New(evt.Message)
+		}
+		p.reconciled = true
+		return nil, p.isDone(), nil
+	}
+	entry := NewTimelineEntry(evt)
+	if entry.Version != p.version {
+		return nil, false, nil
+	}
+	switch entry.Reason {
+	case ketchv1.AppReconcileUpdate:
+		if process := p.process(entry.Process); process != nil {
+			process.update(entry.Message)
+		}
+	case ketchv1.AppReconcileComplete:
+		if process := p.process(entry.Process); process != nil {
+			process.done = true
+			process.terminating = 0
+		}
+	case ketchv1.AppReconcileError:
+		return &entry, false, reconcileError(entry.Message)
+	case ketchv1.CanaryNextStep:
+		if event, err := ketchv1.CanaryNextStepEventFromAnnotations(evt.Annotations); err == nil && p.canary != nil {
+			p.canary.step = event.Step
+			p.canary.weight = event.WeightDest
+		}
+	case ketchv1.CanaryFinished:
+		if p.canary != nil {
+			p.canary.finished = true
+			p.canary.weight = 100
+		}
+	case ketchv1.CanaryAborted, ketchv1.CanaryAnalysisFailed:
+		return &entry, false, fmt.Errorf("%w: %s", ErrCanaryRolledBack, entry.Message)
+	}
+	if entry.Type == corev1.EventTypeWarning {
+		p.warnings = append(p.warnings, entry)
+		if len(p.warnings) > maxProgressWarnings {
+			p.warnings = p.warnings[len(p.warnings)-maxProgressWarnings:]
+		}
+	}
+	return &entry, p.isDone(), nil
+}
+
+// reconcileError returns an error for the description of an AppReconcileError event.
+func reconcileError(description string) error {
+	switch {
+	case strings.HasPrefix(description, ketchv1.AppReconcileHealthcheckError):
+		return fmt.Errorf("%w: %s", ErrHealthcheckFailed, description)
+	case strings.HasPrefix(description, ketchv1.AppReconcileTimeoutError),
+		strings.HasSuffix(description, ketchv1.AppReconcileProgressDeadlineError):
+		return fmt.Errorf("%w: %s", ErrWaitTimeout, description)
+	}
+	return errors.New(description)
+}
+
+func (p *deploymentProgress) process(name string) *processProgress {
+	for _, process := range p.processes {
+		if process.name == name {
+			return process
+		}
+	}
+	return nil
+}
+
+func (p *deploymentProgress) isDone() bool {
+	if !p.reconciled {
+		return false
+	}
+	for _, process := range p.processes {
+		if !process.done {
+			return false
+		}
+	}
+	return p.canary == nil || p.canary.finished
+}
+
+// canaryInProgress returns the progress of a reconciled canary deployment whose steps aren't finished yet, or nil.
+func (p *deploymentProgress) canaryInProgress() *canaryProgress {
+	if !p.reconciled || p.canary == nil || p.canary.finished {
+		return nil
+	}
+	return p.canary
+}
+
+// lines returns the progress of the deployment rendered for a terminal.
+func (p *deploymentProgress) lines(now time.Time) []string {
+	lines := []string{fmt.Sprintf("Deploying %s version %d (%s)", p.appName, p.version, now.Sub(p.started).Round(time.Second))}
+	for _, process := range p.processes {
+		lines = append(lines, "  "+process.String())
+	}
+	if p.canary != nil {
+		lines = append(lines, "  "+p.canary.String())
+	}
+	for _, warning := range p.warnings {
+		lines = append(lines, fmt.Sprintf("  warning: %s", warning.Message))
+	}
+	return lines
+}
+
+// update parses the description of an AppReconcileUpdate event about units of the process.
+func (p *processProgress) update(description string) {
+	var count, total int
+	switch {
+	case scan(description, ketchv1.AppReconcileUnitsCreatedFormat, &count, &total):
+		p.updated, p.desired = count, total
+	case scan(description, ketchv1.AppReconcileUnitsReadyFormat, &count, &total):
+		p.ready, p.desired = count, total
+	case scan(description, ketchv1.AppReconcileUnitsPendingTerminationFormat, &count):
+		p.terminating = count
+	case scan(description, ketchv1.AppReconcileHealthcheckWaitFormat, &count):
+		p.healthcheck = true
+	}
+}
+
+func (p processProgress) String() string {
+	line := fmt.Sprintf("%-12s updated %d/%d  ready %d/%d", p.name, p.updated, p.desired, p.ready, p.desired)
+	if p.terminating > 0 {
+		line += fmt.Sprintf("  terminating %d", p.terminating)
+	}
+	switch {
+	case p.done:
+		line += "  done"
+	case p.healthcheck:
+		line += "  waiting healthcheck"
+	}
+	return line
+}
+
+func (c canaryProgress) String() string {
+	if c.finished {
+		return fmt.Sprintf("%-12s finished, weight %d%%", "canary", c.weight)
+	}
+	return fmt.Sprintf("%-12s step %d of %d, weight %d%%", "canary", c.step, c.steps, c.weight)
+}
+
+// scan returns true if the string matches the format and all the arguments are parsed.
+func scan(str string, format string, args ...interface{}) bool {
+	n, err := fmt.Sscanf(str, format, args...)
+	return err == nil && n == len(args)
+}
+
+// progressView prints the progress of a deployment.
+// On a terminal, it prints the main timeline entries and keeps the progress below them up to date,
+// otherwise, it prints all timeline entries line by line.
+type progressView struct {
+	out io.Writer
+	tty bool
+	// lines is the number of lines of the progress printed last time.
+	lines int
+}
+
+func newProgressView(out io.Writer, tty bool) *progressView {
+	return &progressView{out: out, tty: tty}
+}
+
+func (v *progressView) update(p *deploymentProgress, entry *TimelineEntry, now time.Time) {
+	if !v.tty {
+		if entry != nil {
+			fmt.Fprintln(v.out, entry.String())
+		}
+		return
+	}
+	if v.lines > 0 {
+		// move the cursor to the beginning of the progress and clear it.
+		fmt.Fprintf(v.out, "\x1b[%dA\x1b[J", v.lines)
+	}
+	// updates of units and pod warnings are shown in the progress.
+	if entry != nil && entry.Reason != ketchv1.AppReconcileUpdate {
+		fmt.Fprintln(v.out, entry.String())
+	}
+	lines := p.lines(now)
+	for _, line := range lines {
+		fmt.Fprintln(v.out, line)
+	}
+	v.lines = len(lines)
+}
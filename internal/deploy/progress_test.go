@@ -0,0 +1,138 @@
+package deploy
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/utils/conversions"
+)
+
+func updateEvent(eventType string, process string, reason string, desc string) corev1.Event {
+	return corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				ketchv1.DeploymentAnnotationDevelopmentVersion: "2",
+				ketchv1.DeploymentAnnotationEventName:          reason,
+				ketchv1.DeploymentAnnotationDescription:        desc,
+				ketchv1.DeploymentAnnotationProcessName:        process,
+			},
+		},
+		Type:   eventType,
+		Reason: ketchv1.AppReconcileUpdate,
+	}
+}
+
+func TestDeploymentProgress(t *testing.T) {
+	started := time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC)
+	app := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+		Spec: ketchv1.AppSpec{
+			DeploymentsCount: 2,
+			Deployments: []ketchv1.AppDeploymentSpec{
+				{
+					Version: 2,
+					Processes: []ketchv1.ProcessSpec{
+						{Name: "web", Units: conversions.IntPtr(3)},
+						{Name: "worker", Units: conversions.IntPtr(1)},
+					},
+				},
+			},
+		},
+	}
+	progress := newDeploymentProgress(app, started)
+	events := []corev1.Event{
+		updateEvent(corev1.EventTypeNormal, "web", ketchv1.AppReconcileUpdate, fmt.Sprintf(ketchv1.AppReconcileUnitsCreatedFormat, 2, 3)),
+		updateEvent(corev1.EventTypeNormal, "web", ketchv1.AppReconcileUpdate, fmt.Sprintf(ketchv1.AppReconcileUnitsReadyFormat, 1, 3)),
+		updateEvent(corev1.EventTypeNormal, "web", ketchv1.AppReconcileUpdate, fmt.Sprintf(ketchv1.AppReconcileUnitsPendingTerminationFormat, 2)),
+		updateEvent(corev1.EventTypeNormal, "worker", ketchv1.AppReconcileUpdate, fmt.Sprintf(ketchv1.AppReconcileHealthcheckWaitFormat, 1)),
+		updateEvent(corev1.EventTypeWarning, "worker", "Unhealthy", "Readiness probe failed"),
+		updateEvent(corev1.EventTypeNormal, "worker", "Pulled", "Successfully pulled image"),
+	}
+	for _, evt := range events {
+		entry, done, err := progress.handle(evt)
+		require.Nil(t, err)
+		require.NotNil(t, entry)
+		require.False(t, done)
+	}
+	require.Equal(t, []string{
+		"Deploying dashboard version 2 (1m5s)",
+		"  web          updated 2/3  ready 1/3  terminating 2",
+		"  worker       updated 0/1  ready 0/1  waiting healthcheck",
+		"  warning: Readiness probe failed",
+	}, progress.lines(started.Add(65*time.Second)))
+
+	_, done, err := progress.handle(updateEvent(corev1.EventTypeNormal, "web", ketchv1.AppReconcileComplete, "app dashboard 3 reconcile success"))
+	require.Nil(t, err)
+	require.False(t, done)
+	_, done, err = progress.handle(updateEvent(corev1.EventTypeNormal, "worker", ketchv1.AppReconcileComplete, "app dashboard 1 reconcile success"))
+	require.Nil(t, err)
+	require.False(t, done)
+	_, done, err = progress.handle(corev1.Event{
+		Type:    corev1.EventTypeNormal,
+		Reason:  ketchv1.AppReconcileOutcomeReason,
+		Message: "app dashboard 2 reconcile success",
+	})
+	require.Nil(t, err)
+	require.True(t, done)
+	require.Equal(t, "  web          updated 2/3  ready 1/3  done", progress.lines(started)[1])
+}
+
+func TestProgressView(t *testing.T) {
+	started := time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC)
+	app := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+		Spec: ketchv1.AppSpec{
+			DeploymentsCount: 2,
+			Deployments: []ketchv1.AppDeploymentSpec{
+				{Version: 2, Processes: []ketchv1.ProcessSpec{{Name: "web", Units: conversions.IntPtr(1)}}},
+			},
+		},
+	}
+	started2 := updateEvent(corev1.EventTypeNormal, "web", ketchv1.AppReconcileStarted, "Updating units [web]")
+	started2.Reason = ketchv1.AppReconcileStarted
+	events := []corev1.Event{
+		started2,
+		updateEvent(corev1.EventTypeNormal, "web", ketchv1.AppReconcileUpdate, fmt.Sprintf(ketchv1.AppReconcileUnitsReadyFormat, 1, 1)),
+	}
+	tests := []struct {
+		description string
+		tty         bool
+		wantOut     string
+	}{
+		{
+			description: "terminal",
+			tty:         true,
+			wantOut: "0001-01-01T00:00:00Z  v2   web          Updating units [web]\n" +
+				"Deploying dashboard version 2 (1s)\n" +
+				"  web          updated 0/1  ready 0/1\n" +
+				"\x1b[2A\x1b[J" +
+				"Deploying dashboard version 2 (2s)\n" +
+				"  web          updated 0/1  ready 1/1\n",
+		},
+		{
+			description: "plain",
+			wantOut: "0001-01-01T00:00:00Z  v2   web          Updating units [web]\n" +
+				"0001-01-01T00:00:00Z  v2   web          1 of 1 new units ready\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			progress := newDeploymentProgress(app, started)
+			out := &bytes.Buffer{}
+			view := newProgressView(out, tt.tty)
+			for i, evt := range events {
+				entry, _, err := progress.handle(evt)
+				require.Nil(t, err)
+				view.update(progress, entry, started.Add(time.Duration(i+1)*time.Second))
+			}
+			require.Equal(t, tt.wantOut, out.String())
+		})
+	}
+}
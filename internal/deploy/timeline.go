@@ -0,0 +1,107 @@
+package deploy
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/utils"
+)
+
+// TimelineEntry is an event of an application rendered for a deployment timeline.
+type TimelineEntry struct {
+	Time time.Time
+	// Type is either corev1.EventTypeNormal or corev1.EventTypeWarning.
+	Type string
+	// Version is a deployment version the event is about, zero if the event isn't about a particular deployment.
+	Version int
+	Process string
+	Reason  string
+	Message string
+}
+
+// AppEventSelector returns a field selector of the events of the app.
+func AppEventSelector(appName string) fields.Selector {
+	return fields.Set(map[string]string{
+		"involvedObject.apiVersion": utils.V1betaPrefix,
+		"involvedObject.kind":       "App",
+		"involvedObject.name":       appName,
+	}).AsSelector()
+}
+
+// NewTimelineEntry converts an event of an application to a TimelineEntry.
+// Deployment and canary events are described using their annotations, the other events use their messages.
+func NewTimelineEntry(evt corev1.Event) TimelineEntry {
+	entry := TimelineEntry{
+		Time:    eventTime(evt),
+		Type:    evt.Type,
+		Reason:  evt.Reason,
+		Message: evt.Message,
+	}
+	annotations := evt.Annotations
+	switch {
+	case len(annotations[ketchv1.DeploymentAnnotationEventName]) > 0:
+		event := ketchv1.AppDeploymentEventFromAnnotations(annotations)
+		entry.Version = event.DeploymentVersion
+		entry.Process = event.ProcessName
+		entry.Reason = event.Reason
+		entry.Message = event.Description
+	case len(annotations[ketchv1.CanaryAnnotationEventName]) > 0:
+		entry.Reason = annotations[ketchv1.CanaryAnnotationEventName]
+		entry.Version, _ = strconv.Atoi(annotations[ketchv1.CanaryAnnotationDevelopmentVersion])
+		entry.Message = canaryMessage(entry.Reason, annotations)
+	}
+	return entry
+}
+
+// canaryMessage returns a short description of a canary event.
+func canaryMessage(reason string, annotations map[string]string) string {
+	switch reason {
+	case ketchv1.CanaryNextStep:
+		if event, err := ketchv1.CanaryNextStepEventFromAnnotations(annotations); err == nil {
+			return fmt.Sprintf("canary step %d: weight %d%% on version %d, %d%% on version %d",
+				event.Step, event.WeightSource, event.VersionSource, event.WeightDest, event.VersionDest)
+		}
+	case ketchv1.CanaryStepTarget:
+		if event, err := ketchv1.CanaryTargetChangeEventFromAnnotations(annotations); err == nil {
+			return fmt.Sprintf("canary units of %s: %d on version %d, %d on version %d",
+				event.ProcessName, event.SourceProcessUnits, event.VersionSource, event.DestinationProcessUnits, event.VersionDest)
+		}
+	}
+	event, err := ketchv1.CanaryEventFromAnnotations(annotations)
+	if err != nil {
+		return annotations[ketchv1.CanaryAnnotationDescription]
+	}
+	return fmt.Sprintf("canary %s", event.Description)
+}
+
+// String returns a line of the timeline.
+func (e TimelineEntry) String() string {
+	version := "-"
+	if e.Version > 0 {
+		version = fmt.Sprintf("v%d", e.Version)
+	}
+	process := e.Process
+	if len(process) == 0 {
+		process = "-"
+	}
+	message := e.Message
+	if e.Type == corev1.EventTypeWarning {
+		message = "warning: " + message
+	}
+	return fmt.Sprintf("%s  %-4s %-12s %s", e.Time.UTC().Format(time.RFC3339), version, process, message)
+}
+
+func eventTime(evt corev1.Event) time.Time {
+	if !evt.LastTimestamp.IsZero() {
+		return evt.LastTimestamp.Time
+	}
+	if !evt.EventTime.IsZero() {
+		return evt.EventTime.Time
+	}
+	return evt.CreationTimestamp.Time
+}
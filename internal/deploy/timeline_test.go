@@ -0,0 +1,176 @@
+package deploy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+)
+
+func TestNewTimelineEntry(t *testing.T) {
+	now := time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		description string
+		event       corev1.Event
+		want        TimelineEntry
+		wantString  string
+	}{
+		{
+			description: "units ready",
+			event: corev1.Event{
+				Type:          corev1.EventTypeNormal,
+				Reason:        ketchv1.AppReconcileUpdate,
+				LastTimestamp: metav1.NewTime(now),
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						ketchv1.DeploymentAnnotationAppName:            "dashboard",
+						ketchv1.DeploymentAnnotationDevelopmentVersion: "3",
+						ketchv1.DeploymentAnnotationEventName:          ketchv1.AppReconcileUpdate,
+						ketchv1.DeploymentAnnotationDescription:        "2 of 3 new units ready",
+						ketchv1.DeploymentAnnotationProcessName:        "web",
+					},
+				},
+			},
+			want: TimelineEntry{
+				Time:    now,
+				Type:    corev1.EventTypeNormal,
+				Version: 3,
+				Process: "web",
+				Reason:  ketchv1.AppReconcileUpdate,
+				Message: "2 of 3 new units ready",
+			},
+			wantString: "2021-10-01T12:00:00Z  v3   web          2 of 3 new units ready",
+		},
+		{
+			description: "healthcheck error",
+			event: corev1.Event{
+				Type:      corev1.EventTypeWarning,
+				Reason:    ketchv1.AppReconcileError,
+				EventTime: metav1.NewMicroTime(now),
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						ketchv1.DeploymentAnnotationDevelopmentVersion: "3",
+						ketchv1.DeploymentAnnotationEventName:          ketchv1.AppReconcileError,
+						ketchv1.DeploymentAnnotationDescription:        "error waiting for healthcheck: timeout",
+						ketchv1.DeploymentAnnotationProcessName:        "worker",
+					},
+				},
+			},
+			want: TimelineEntry{
+				Time:    now,
+				Type:    corev1.EventTypeWarning,
+				Version: 3,
+				Process: "worker",
+				Reason:  ketchv1.AppReconcileError,
+				Message: "error waiting for healthcheck: timeout",
+			},
+			wantString: "2021-10-01T12:00:00Z  v3   worker       warning: error waiting for healthcheck: timeout",
+		},
+		{
+			description: "canary step",
+			event: corev1.Event{
+				Type:          corev1.EventTypeNormal,
+				Reason:        ketchv1.CanaryNextStep,
+				LastTimestamp: metav1.NewTime(now),
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						ketchv1.CanaryAnnotationAppName:            "dashboard",
+						ketchv1.CanaryAnnotationDevelopmentVersion: "4",
+						ketchv1.CanaryAnnotationEventName:          ketchv1.CanaryNextStep,
+						ketchv1.CanaryAnnotationDescription:        ketchv1.CanaryNextStepDesc,
+						ketchv1.CanaryAnnotationStep:               "2",
+						ketchv1.CanaryAnnotationVersionSource:      "3",
+						ketchv1.CanaryAnnotationVersionDest:        "4",
+						ketchv1.CanaryAnnotationWeightSource:       "60",
+						ketchv1.CanaryAnnotationWeightDest:         "40",
+					},
+				},
+			},
+			want: TimelineEntry{
+				Time:    now,
+				Type:    corev1.EventTypeNormal,
+				Version: 4,
+				Reason:  ketchv1.CanaryNextStep,
+				Message: "canary step 2: weight 60% on version 3, 40% on version 4",
+			},
+			wantString: "2021-10-01T12:00:00Z  v4   -            canary step 2: weight 60% on version 3, 40% on version 4",
+		},
+		{
+			description: "canary target change",
+			event: corev1.Event{
+				Type:          corev1.EventTypeNormal,
+				Reason:        ketchv1.CanaryStepTarget,
+				LastTimestamp: metav1.NewTime(now),
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						ketchv1.CanaryAnnotationDevelopmentVersion: "4",
+						ketchv1.CanaryAnnotationEventName:          ketchv1.CanaryStepTarget,
+						ketchv1.CanaryAnnotationVersionSource:      "3",
+						ketchv1.CanaryAnnotationVersionDest:        "4",
+						ketchv1.CanaryAnnotationProcessName:        "web",
+						ketchv1.CanaryAnnotationProcessUnitsSource: "2",
+						ketchv1.CanaryAnnotationProcessUnitsDest:   "1",
+					},
+				},
+			},
+			want: TimelineEntry{
+				Time:    now,
+				Type:    corev1.EventTypeNormal,
+				Version: 4,
+				Reason:  ketchv1.CanaryStepTarget,
+				Message: "canary units of web: 2 on version 3, 1 on version 4",
+			},
+		},
+		{
+			description: "canary aborted",
+			event: corev1.Event{
+				Type:          corev1.EventTypeWarning,
+				Reason:        ketchv1.CanaryAborted,
+				LastTimestamp: metav1.NewTime(now),
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						ketchv1.CanaryAnnotationDevelopmentVersion: "4",
+						ketchv1.CanaryAnnotationEventName:          ketchv1.CanaryAborted,
+						ketchv1.CanaryAnnotationDescription:        ketchv1.CanaryAbortedDesc,
+					},
+				},
+			},
+			want: TimelineEntry{
+				Time:    now,
+				Type:    corev1.EventTypeWarning,
+				Version: 4,
+				Reason:  ketchv1.CanaryAborted,
+				Message: "canary aborted",
+			},
+		},
+		{
+			description: "event without annotations",
+			event: corev1.Event{
+				ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(now)},
+				Type:       corev1.EventTypeNormal,
+				Reason:     ketchv1.AppReconcileOutcomeReason,
+				Message:    "app dashboard 3 reconcile success",
+			},
+			want: TimelineEntry{
+				Time:    now,
+				Type:    corev1.EventTypeNormal,
+				Reason:  ketchv1.AppReconcileOutcomeReason,
+				Message: "app dashboard 3 reconcile success",
+			},
+			wantString: "2021-10-01T12:00:00Z  -    -            app dashboard 3 reconcile success",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			got := NewTimelineEntry(tt.event)
+			require.Equal(t, tt.want, got)
+			if len(tt.wantString) > 0 {
+				require.Equal(t, tt.wantString, got.String())
+			}
+		})
+	}
+}
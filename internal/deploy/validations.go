@@ -97,6 +97,42 @@ func validateDeploy(cs *ChangeSet, app *ketchv1.App) error {
 		}
 	}
 
+	strategy, err := cs.getStrategy()
+	if !isMissing(err) {
+		if !isValid(err) {
+			return err
+		}
+		if strategy == StrategyBlueGreen {
+			switch deps := len(app.Spec.Deployments); {
+			case deps == 0:
+				return fmt.Errorf("blue-green deployment failed. No primary deployment found for the app")
+			case deps >= 2:
+				return fmt.Errorf("blue-green deployment failed. Maximum number of two deployments are currently supported")
+			}
+		}
+	}
+
+	_, err = cs.getGracePeriod()
+	if !isMissing(err) {
+		if !isValid(err) {
+			return err
+		}
+	}
+
+	_, err = cs.getCanaryAnalysis()
+	if !isMissing(err) {
+		if !isValid(err) {
+			return err
+		}
+	}
+
+	_, err = cs.getCanaryHeader()
+	if !isMissing(err) {
+		if !isValid(err) {
+			return err
+		}
+	}
+
 	_, err = cs.getUnits()
 	if !isMissing(err) {
 		if !isValid(err) {
@@ -2,67 +2,111 @@ package deploy
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
+	"sort"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/kubectl/pkg/util/term"
 
 	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
 	"github.com/theketchio/ketch/internal/errors"
-	"github.com/theketchio/ketch/internal/utils"
 )
 
+var (
+	// ErrWaitTimeout is returned when a deployment isn't rolled out in time.
+	ErrWaitTimeout = stderrors.New("deployment timed out")
+	// ErrHealthcheckFailed is returned when units of a deployment don't pass their healthcheck.
+	ErrHealthcheckFailed = stderrors.New("healthcheck failed")
+	// ErrCanaryRolledBack is returned when a canary deployment is rolled back.
+	ErrCanaryRolledBack = stderrors.New("canary deployment rolled back")
+)
+
+// progressRefreshInterval is how often the elapsed time of a deployment is updated on a terminal.
+const progressRefreshInterval = time.Second
+
 type WaitFn func(ctx context.Context, svc *Services, app *ketchv1.App, timeout time.Duration) error
 
+// WaitForDeployment shows the progress of the latest deployment of the app until it's rolled out.
+// The deployment is rolled out once the app is reconciled and the units of all its processes are ready.
+// A reconciled canary deployment is followed until all its steps are done or the timeout expires,
+// in the latter case its current step and weight are shown and the deployment isn't considered timed out.
+// On a terminal, the progress of each process is updated in place, otherwise the timeline is printed line by line.
 func WaitForDeployment(ctx context.Context, svc *Services, app *ketchv1.App, timeout time.Duration) error {
 	tctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	watcher, err := watchAppReconcileEvent(ctx, svc.KubeClient, app)
+	events, watcher, err := WatchAppEvents(ctx, svc.KubeClient, app.Name)
 	if err != nil {
 		return err
 	}
 	defer watcher.Stop()
 
+	progress := newDeploymentProgress(app, time.Now())
+	view := newProgressView(svc.Writer, term.IsTerminal(svc.Writer))
+	handle := func(evt corev1.Event) (bool, error) {
+		entry, done, err := progress.handle(evt)
+		view.update(progress, entry, time.Now())
+		if done {
+			fmt.Fprintln(svc.Writer, "successfully deployed!")
+		}
+		return done, err
+	}
+	for _, evt := range events {
+		if done, err := handle(evt); done || err != nil {
+			return err
+		}
+	}
+	var refresh <-chan time.Time
+	if view.tty {
+		ticker := time.NewTicker(progressRefreshInterval)
+		defer ticker.Stop()
+		refresh = ticker.C
+	}
 	for {
 		select {
 		case msg, ok := <-watcher.ResultChan():
 			if !ok {
 				return errors.New("wait for deployment channel closed")
 			}
-			if evt, ok := msg.Object.(*corev1.Event); ok {
-				reason, err := ketchv1.ParseAppReconcileOutcome(evt.Reason)
-				if err != nil {
-					return err
-				}
-				if reason.DeploymentCount == app.Spec.DeploymentsCount {
-					switch evt.Type {
-					case corev1.EventTypeNormal:
-						fmt.Fprintln(svc.Writer, "successfully deployed!")
-						return nil
-					case corev1.EventTypeWarning:
-						return errors.New(evt.Message)
-					}
-				}
+			evt, ok := msg.Object.(*corev1.Event)
+			if !ok || (msg.Type != watch.Added && msg.Type != watch.Modified) {
+				continue
+			}
+			if done, err := handle(*evt); done || err != nil {
+				return err
 			}
+		case now := <-refresh:
+			view.update(progress, nil, now)
 		case <-tctx.Done():
-			return fmt.Errorf("deployment timed out")
+			if canary := progress.canaryInProgress(); canary != nil {
+				fmt.Fprintf(svc.Writer, "successfully deployed! canary is at step %d of %d, weight %d%%\n", canary.step, canary.steps, canary.weight)
+				return nil
+			}
+			return ErrWaitTimeout
 		}
 	}
 }
 
-func watchAppReconcileEvent(ctx context.Context, kubeClient kubernetes.Interface, app *ketchv1.App) (watch.Interface, error) {
-	reason := ketchv1.AppReconcileOutcome{AppName: app.Name, DeploymentCount: app.Spec.DeploymentsCount}
-	selector := fields.Set(map[string]string{
-		"involvedObject.apiVersion": utils.V1betaPrefix,
-		"involvedObject.kind":       "App",
-		"involvedObject.name":       app.Name,
-		"reason":                    reason.String(),
-	}).AsSelector()
-	return kubeClient.CoreV1().
-		Events(app.Namespace).Watch(ctx, metav1.ListOptions{FieldSelector: selector.String()})
+// WatchAppEvents returns the current events of the app ordered by time and a watcher of the subsequent events.
+func WatchAppEvents(ctx context.Context, kubeClient kubernetes.Interface, appName string) ([]corev1.Event, watch.Interface, error) {
+	opts := metav1.ListOptions{FieldSelector: AppEventSelector(appName).String()}
+	// app is a cluster-scoped resource, its events can be in any namespace.
+	events, err := kubeClient.CoreV1().Events("").List(ctx, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.SliceStable(events.Items, func(i, j int) bool {
+		return eventTime(events.Items[i]).Before(eventTime(events.Items[j]))
+	})
+	opts.ResourceVersion = events.ResourceVersion
+	watcher, err := kubeClient.CoreV1().Events("").Watch(ctx, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return events.Items, watcher, nil
 }
@@ -0,0 +1,244 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+)
+
+func TestWaitForDeployment(t *testing.T) {
+	now := time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC)
+	deploymentEvent := func(name string, version int, process, reason, desc string, seconds int) *corev1.Event {
+		eventType := corev1.EventTypeNormal
+		if reason == ketchv1.AppReconcileError {
+			eventType = corev1.EventTypeWarning
+		}
+		return &corev1.Event{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Annotations: map[string]string{
+					ketchv1.DeploymentAnnotationAppName:            "dashboard",
+					ketchv1.DeploymentAnnotationDevelopmentVersion: strconv.Itoa(version),
+					ketchv1.DeploymentAnnotationEventName:          reason,
+					ketchv1.DeploymentAnnotationDescription:        desc,
+					ketchv1.DeploymentAnnotationProcessName:        process,
+				},
+			},
+			Type:          eventType,
+			Reason:        reason,
+			LastTimestamp: metav1.NewTime(now.Add(time.Duration(seconds) * time.Second)),
+		}
+	}
+	outcomeEvent := func(name string, eventType string, message string) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:    metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Type:          eventType,
+			Reason:        ketchv1.AppReconcileOutcomeReason,
+			Message:       message,
+			LastTimestamp: metav1.NewTime(now),
+		}
+	}
+	canaryEvent := func(name string, reason string, annotations map[string]string) *corev1.Event {
+		eventType := corev1.EventTypeNormal
+		if reason == ketchv1.CanaryAnalysisFailed {
+			eventType = corev1.EventTypeWarning
+		}
+		evt := &corev1.Event{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Annotations: map[string]string{
+					ketchv1.CanaryAnnotationAppName:            "dashboard",
+					ketchv1.CanaryAnnotationDevelopmentVersion: "2",
+					ketchv1.CanaryAnnotationEventName:          reason,
+				},
+			},
+			Type:          eventType,
+			Reason:        reason,
+			LastTimestamp: metav1.NewTime(now),
+		}
+		for k, v := range annotations {
+			evt.Annotations[k] = v
+		}
+		return evt
+	}
+	app := func(canary bool) *ketchv1.App {
+		return &ketchv1.App{
+			ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+			Spec: ketchv1.AppSpec{
+				DeploymentsCount: 2,
+				Deployments: []ketchv1.AppDeploymentSpec{
+					{Version: 1, Processes: []ketchv1.ProcessSpec{{Name: "web"}}},
+					{Version: 2, Processes: []ketchv1.ProcessSpec{{Name: "web"}, {Name: "worker"}}},
+				},
+				Canary: ketchv1.CanarySpec{Active: canary, Steps: 2},
+			},
+		}
+	}
+	tests := []struct {
+		description string
+		app         *ketchv1.App
+		events      []runtime.Object
+		watched     []*corev1.Event
+		wantErr     string
+		wantErrIs   error
+		wantOut     string
+	}{
+		{
+			description: "rolled out",
+			app:         app(false),
+			events: []runtime.Object{
+				deploymentEvent("old", 1, "web", ketchv1.AppReconcileComplete, "app dashboard 1 reconcile success", 0),
+				deploymentEvent("started", 2, "web", ketchv1.AppReconcileStarted, "Updating units [web]", 1),
+			},
+			watched: []*corev1.Event{
+				deploymentEvent("ready-web", 2, "web", ketchv1.AppReconcileUpdate, "1 of 1 new units ready", 2),
+				deploymentEvent("complete-web", 2, "web", ketchv1.AppReconcileComplete, "app dashboard 1 reconcile success", 3),
+				outcomeEvent("outcome", corev1.EventTypeNormal, "app dashboard 2 reconcile success"),
+				deploymentEvent("complete-worker", 2, "worker", ketchv1.AppReconcileComplete, "app dashboard 1 reconcile success", 4),
+			},
+			wantOut: `2021-10-01T12:00:01Z  v2   web          Updating units [web]
+2021-10-01T12:00:02Z  v2   web          1 of 1 new units ready
+2021-10-01T12:00:03Z  v2   web          app dashboard 1 reconcile success
+2021-10-01T12:00:04Z  v2   worker       app dashboard 1 reconcile success
+successfully deployed!
+`,
+		},
+		{
+			description: "canary deployment is done once its steps are finished",
+			app:         app(true),
+			events: []runtime.Object{
+				outcomeEvent("old-outcome", corev1.EventTypeNormal, "app dashboard 1 reconcile success"),
+			},
+			watched: []*corev1.Event{
+				outcomeEvent("outcome", corev1.EventTypeNormal, "app dashboard 2 reconcile success"),
+				canaryEvent("step", ketchv1.CanaryNextStep, map[string]string{
+					ketchv1.CanaryAnnotationStep:          "1",
+					ketchv1.CanaryAnnotationVersionSource: "1",
+					ketchv1.CanaryAnnotationVersionDest:   "2",
+					ketchv1.CanaryAnnotationWeightSource:  "50",
+					ketchv1.CanaryAnnotationWeightDest:    "50",
+				}),
+				canaryEvent("finished", ketchv1.CanaryFinished, map[string]string{
+					ketchv1.CanaryAnnotationDescription: ketchv1.CanaryFinishedDesc,
+				}),
+			},
+			wantOut: `2021-10-01T12:00:00Z  v2   -            canary step 1: weight 50% on version 1, 50% on version 2
+2021-10-01T12:00:00Z  v2   -            canary finished
+successfully deployed!
+`,
+		},
+		{
+			description: "canary deployment in progress on timeout",
+			app:         app(true),
+			watched: []*corev1.Event{
+				outcomeEvent("outcome", corev1.EventTypeNormal, "app dashboard 2 reconcile success"),
+				canaryEvent("step", ketchv1.CanaryNextStep, map[string]string{
+					ketchv1.CanaryAnnotationStep:          "1",
+					ketchv1.CanaryAnnotationVersionSource: "1",
+					ketchv1.CanaryAnnotationVersionDest:   "2",
+					ketchv1.CanaryAnnotationWeightSource:  "50",
+					ketchv1.CanaryAnnotationWeightDest:    "50",
+				}),
+			},
+			wantOut: `2021-10-01T12:00:00Z  v2   -            canary step 1: weight 50% on version 1, 50% on version 2
+successfully deployed! canary is at step 1 of 2, weight 50%
+`,
+		},
+		{
+			description: "canary deployment not reconciled on timeout",
+			app:         app(true),
+			wantErrIs:   ErrWaitTimeout,
+			wantErr:     "deployment timed out",
+		},
+		{
+			description: "canary rollback",
+			app:         app(true),
+			watched: []*corev1.Event{
+				outcomeEvent("outcome", corev1.EventTypeNormal, "app dashboard 2 reconcile success"),
+				canaryEvent("failed", ketchv1.CanaryAnalysisFailed, map[string]string{
+					ketchv1.CanaryAnnotationDescription: "error rate is above 0.05",
+				}),
+			},
+			wantErrIs: ErrCanaryRolledBack,
+			wantErr:   "canary deployment rolled back: canary error rate is above 0.05",
+			wantOut:   "2021-10-01T12:00:00Z  v2   -            warning: canary error rate is above 0.05\n",
+		},
+		{
+			description: "healthcheck error",
+			app:         app(false),
+			watched: []*corev1.Event{
+				deploymentEvent("error", 2, "web", ketchv1.AppReconcileError, "error waiting for healthcheck: 1 unit is not ready", 1),
+			},
+			wantErrIs: ErrHealthcheckFailed,
+			wantErr:   "healthcheck failed: error waiting for healthcheck: 1 unit is not ready",
+			wantOut:   "2021-10-01T12:00:01Z  v2   web          warning: error waiting for healthcheck: 1 unit is not ready\n",
+		},
+		{
+			description: "rollout timeout",
+			app:         app(false),
+			watched: []*corev1.Event{
+				deploymentEvent("error", 2, "web", ketchv1.AppReconcileError, `deployment "dashboard-web-2" exceeded its progress deadline`, 1),
+			},
+			wantErrIs: ErrWaitTimeout,
+			wantErr:   `deployment timed out: deployment "dashboard-web-2" exceeded its progress deadline`,
+			wantOut:   "2021-10-01T12:00:01Z  v2   web          warning: deployment \"dashboard-web-2\" exceeded its progress deadline\n",
+		},
+		{
+			description: "reconcile failed",
+			app:         app(false),
+			watched: []*corev1.Event{
+				outcomeEvent("outcome", corev1.EventTypeWarning, "app dashboard 2 reconcile fail: no framework"),
+			},
+			wantErr: "app dashboard 2 reconcile fail: no framework",
+		},
+		{
+			description: "timeout",
+			app:         app(false),
+			watched: []*corev1.Event{
+				outcomeEvent("outcome", corev1.EventTypeNormal, "app dashboard 2 reconcile success"),
+			},
+			wantErrIs: ErrWaitTimeout,
+			wantErr:   "deployment timed out",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			cli := fake.NewSimpleClientset(tt.events...)
+			watcher := watch.NewFake()
+			cli.PrependWatchReactor("events", k8stesting.DefaultWatchReactor(watcher, nil))
+			go func() {
+				for _, evt := range tt.watched {
+					watcher.Add(evt)
+				}
+			}()
+			out := &bytes.Buffer{}
+			svc := &Services{KubeClient: cli, Writer: out}
+			err := WaitForDeployment(context.Background(), svc, tt.app, 200*time.Millisecond)
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Contains(t, err.Error(), tt.wantErr)
+				if tt.wantErrIs != nil {
+					require.True(t, errors.Is(err, tt.wantErrIs))
+				}
+			} else {
+				require.Nil(t, err)
+			}
+			require.Equal(t, tt.wantOut, out.String())
+		})
+	}
+}
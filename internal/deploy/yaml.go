@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 
+	v1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/yaml"
 
 	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
@@ -30,8 +31,11 @@ type Application struct {
 }
 
 type Process struct {
-	Name  string `json:"name"`  // required
-	Units *int   `json:"units"` // default 1
+	Name        string                   `json:"name"`  // required
+	Units       *int                     `json:"units"` // default 1
+	Environment []string                 `json:"environment,omitempty"`
+	Resources   *v1.ResourceRequirements `json:"resources,omitempty"`
+	Autoscaling *ketchv1.AutoscalingSpec `json:"autoscaling,omitempty"`
 }
 
 type Port struct {
@@ -63,6 +67,25 @@ const (
 // GetChangeSetFromYaml reads an application.yaml file and returns a ChangeSet
 // from the file's values.
 func (o *Options) GetChangeSetFromYaml(filename string) (*ChangeSet, error) {
+	c, err := o.readChangeSetFromYaml(filename)
+	if err != nil {
+		return nil, err
+	}
+	return c, c.validate()
+}
+
+// GetDriftChangeSetFromYaml reads an application.yaml to compare it with the app running in the cluster.
+// Unlike GetChangeSetFromYaml, processes don't require a source path because nothing is built.
+func GetDriftChangeSetFromYaml(filename string) (*ChangeSet, error) {
+	o := &Options{}
+	c, err := o.readChangeSetFromYaml(filename)
+	if err != nil {
+		return nil, err
+	}
+	return c, c.validateRequiredFields()
+}
+
+func (o *Options) readChangeSetFromYaml(filename string) (*ChangeSet, error) {
 	var application Application
 	b, err := os.ReadFile(filename)
 	if err != nil {
@@ -73,10 +96,8 @@ func (o *Options) GetChangeSetFromYaml(filename string) (*ChangeSet, error) {
 		return nil, err
 	}
 
-	var envs []ketchv1.Env
 	if application.Environment != nil {
-		envs, err = utils.MakeEnvironments(application.Environment)
-		if err != nil {
+		if _, err = utils.MakeEnvironments(application.Environment); err != nil {
 			return nil, err
 		}
 	}
@@ -84,10 +105,19 @@ func (o *Options) GetChangeSetFromYaml(filename string) (*ChangeSet, error) {
 	var processes []ketchv1.ProcessSpec
 	if application.Processes != nil {
 		for _, process := range application.Processes {
+			processEnvs, err := utils.MakeEnvironments(process.Environment)
+			if err != nil {
+				return nil, err
+			}
+			if len(processEnvs) == 0 {
+				processEnvs = nil
+			}
 			processes = append(processes, ketchv1.ProcessSpec{
-				Name:  process.Name,
-				Units: process.Units,
-				Env:   envs,
+				Name:        process.Name,
+				Units:       process.Units,
+				Env:         processEnvs,
+				Resources:   process.Resources,
+				Autoscaling: process.Autoscaling,
 			})
 		}
 
@@ -120,7 +150,7 @@ func (o *Options) GetChangeSetFromYaml(filename string) (*ChangeSet, error) {
 		c.processes = &processes
 	}
 	c.applyDefaults()
-	return c, c.validate()
+	return c, nil
 }
 
 // apply defaults sets default values for a ChangeSet
@@ -142,8 +172,19 @@ func (c *ChangeSet) applyDefaults() {
 	}
 }
 
-// validate assures that a ChangeSet's required fields are set
+// validate assures that a ChangeSet's required fields are set and that it can be deployed
 func (c *ChangeSet) validate() error {
+	if err := c.validateRequiredFields(); err != nil {
+		return err
+	}
+	if c.sourcePath == nil && c.processes != nil {
+		return errors.New("running defined processes require a sourcePath")
+	}
+	return nil
+}
+
+// validateRequiredFields assures that a ChangeSet's required fields are set
+func (c *ChangeSet) validateRequiredFields() error {
 	if c.framework == nil {
 		return errors.New("missing required field framework")
 	}
@@ -153,9 +194,6 @@ func (c *ChangeSet) validate() error {
 	if c.appName == "" {
 		return errors.New("missing required field name")
 	}
-	if c.sourcePath == nil && c.processes != nil {
-		return errors.New("running defined processes require a sourcePath")
-	}
 	return nil
 }
 
@@ -173,8 +211,11 @@ func GetApplicationFromKetchApp(app ketchv1.App) *Application {
 		application.Image = conversions.StrPtr(deployment.Image)
 		for _, process := range deployment.Processes {
 			application.Processes = append(application.Processes, Process{
-				Name:  process.Name,
-				Units: process.Units,
+				Name:        process.Name,
+				Units:       process.Units,
+				Environment: environment(process.Env),
+				Resources:   process.Resources,
+				Autoscaling: process.Autoscaling,
 			})
 		}
 	}
@@ -197,13 +238,22 @@ func GetApplicationFromKetchApp(app ketchv1.App) *Application {
 	if len(app.Spec.BuildPacks) > 0 {
 		application.BuildPacks = app.Spec.BuildPacks
 	}
+	application.Environment = environment(app.Spec.Env)
+
+	return application
+}
+
+// environment returns env variables in the NAME=VALUE format.
+func environment(envs []ketchv1.Env) []string {
 	var environment []string
-	for _, env := range app.Spec.Env {
+	for _, env := range envs {
+		if env.ValueFrom != nil {
+			// values stored in secrets are not exported.
+			continue
+		}
 		environment = append(environment, fmt.Sprintf("%s=%s", env.Name, env.Value))
-		application.Environment = environment
 	}
-
-	return application
+	return environment
 }
 
 // getLatestDeployment returns the AppDeploymentSpec of the highest Version or nil
@@ -214,7 +264,6 @@ func getLatestDeployment(deployments []ketchv1.AppDeploymentSpec) *ketchv1.AppDe
 	latestIndex := 0
 	for i, deployment := range deployments {
 		if deployment.Version > deployments[latestIndex].Version {
-			deployments[latestIndex].Version = deployment.Version
 			latestIndex = i
 		}
 	}
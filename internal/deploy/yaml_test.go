@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
@@ -36,8 +38,18 @@ environment:
 processes:
   - name: web
     units: 1
+    resources:
+      requests:
+        cpu: 500m
+        memory: 512Mi
+    autoscaling:
+      minUnits: 1
+      maxUnits: 4
+      targetCPUUtilization: 75
   - name: worker
     units: 1
+    environment:
+      - QUEUE=jobs
 cname:
   dnsName: test.10.10.10.20`,
 			options: &Options{
@@ -63,28 +75,21 @@ cname:
 					{
 						Name:  "web",
 						Units: conversions.IntPtr(1),
-						Env: []ketchv1.Env{
-							{
-								Name:  "PORT",
-								Value: "6666",
-							},
-							{
-								Name:  "FOO",
-								Value: "bar",
+						Resources: &corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("500m"),
+								corev1.ResourceMemory: resource.MustParse("512Mi"),
 							},
 						},
+						Autoscaling: &ketchv1.AutoscalingSpec{MinUnits: 1, MaxUnits: 4, TargetCPUUtilization: intRef32(75)},
 					},
 					{
 						Name:  "worker",
 						Units: conversions.IntPtr(1),
 						Env: []ketchv1.Env{
 							{
-								Name:  "PORT",
-								Value: "6666",
-							},
-							{
-								Name:  "FOO",
-								Value: "bar",
+								Name:  "QUEUE",
+								Value: "jobs",
 							},
 						},
 					},
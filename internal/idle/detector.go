@@ -0,0 +1,60 @@
+// Package idle detects applications which receive no ingress traffic.
+package idle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/prometheus"
+)
+
+// DefaultQuery returns the number of requests received by an application according to istio.
+// It's used only for frameworks with the istio ingress controller.
+const DefaultQuery = `sum(increase(istio_requests_total{destination_app="{{ .App }}",reporter="destination"}[{{ .Window }}]))`
+
+// Querier runs an instant query against a Prometheus compatible HTTP API.
+type Querier interface {
+	Query(ctx context.Context, prometheusURL, query string) (float64, error)
+}
+
+// Detector checks the ingress traffic of applications with an idle policy.
+type Detector struct {
+	Querier Querier
+}
+
+// NewDetector returns a Detector.
+func NewDetector(querier Querier) *Detector {
+	return &Detector{Querier: querier}
+}
+
+// IsIdle returns true if the app received no requests during its idle window.
+// A query which returns no data means no requests, Prometheus returns an empty vector for an app without traffic.
+func (d *Detector) IsIdle(ctx context.Context, app *ketchv1.App, framework *ketchv1.Framework) (bool, error) {
+	policy := app.Spec.IdlePolicy
+	if policy == nil {
+		return false, nil
+	}
+	values := prometheus.NewQueryValues(app)
+	values.Window = fmt.Sprintf("%ds", int(app.IdleWindow().Seconds()))
+	queryTemplate := policy.Query
+	if queryTemplate == "" {
+		if ingressType := framework.Spec.IngressController.IngressType; ingressType != ketchv1.IstioIngressControllerType {
+			return false, fmt.Errorf("a query is required for the %s ingress controller, the default query relies on istio metrics", ingressType)
+		}
+		queryTemplate = DefaultQuery
+	}
+	query, err := prometheus.RenderQuery(queryTemplate, values)
+	if err != nil {
+		return false, fmt.Errorf("failed to render query: %w", err)
+	}
+	requests, err := d.Querier.Query(ctx, policy.PrometheusURL, query)
+	if errors.Is(err, prometheus.ErrNoData) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to query requests: %w", err)
+	}
+	return requests == 0, nil
+}
@@ -0,0 +1,118 @@
+package idle
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/prometheus"
+)
+
+type fakeQuerier struct {
+	results map[string]float64
+	err     error
+}
+
+func (q *fakeQuerier) Query(ctx context.Context, prometheusURL, query string) (float64, error) {
+	if q.err != nil {
+		return 0, q.err
+	}
+	result, ok := q.results[query]
+	if !ok {
+		return 0, errors.New("unknown query")
+	}
+	return result, nil
+}
+
+func TestDetector_IsIdle(t *testing.T) {
+	app := func(policy *ketchv1.IdlePolicySpec) *ketchv1.App {
+		return &ketchv1.App{
+			ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+			Spec:       ketchv1.AppSpec{IdlePolicy: policy},
+		}
+	}
+	querier := &fakeQuerier{
+		results: map[string]float64{
+			`sum(increase(istio_requests_total{destination_app="dashboard",reporter="destination"}[1800s]))`: 0,
+			`requests{app="dashboard"}[600s]`: 12,
+		},
+	}
+	istio := &ketchv1.Framework{
+		Spec: ketchv1.FrameworkSpec{IngressController: ketchv1.IngressControllerSpec{IngressType: ketchv1.IstioIngressControllerType}},
+	}
+	nginx := &ketchv1.Framework{
+		Spec: ketchv1.FrameworkSpec{IngressController: ketchv1.IngressControllerSpec{IngressType: ketchv1.NginxIngressControllerType}},
+	}
+	tests := []struct {
+		name      string
+		app       *ketchv1.App
+		framework *ketchv1.Framework
+		querier   *fakeQuerier
+		wantIdle  bool
+		wantErr   string
+	}{
+		{
+			name:      "no traffic",
+			app:       app(&ketchv1.IdlePolicySpec{IdleMinutes: 30, PrometheusURL: "http://prometheus"}),
+			framework: istio,
+			querier:   querier,
+			wantIdle:  true,
+		},
+		{
+			name:      "custom query with traffic",
+			app:       app(&ketchv1.IdlePolicySpec{IdleMinutes: 10, PrometheusURL: "http://prometheus", Query: `requests{app="{{ .App }}"}[{{ .Window }}]`}),
+			framework: nginx,
+			querier:   querier,
+		},
+		{
+			name:      "no idle policy",
+			app:       app(nil),
+			framework: istio,
+			querier:   querier,
+		},
+		{
+			name:      "default query without istio",
+			app:       app(&ketchv1.IdlePolicySpec{IdleMinutes: 30, PrometheusURL: "http://prometheus"}),
+			framework: nginx,
+			querier:   querier,
+			wantErr:   "a query is required for the nginx ingress controller, the default query relies on istio metrics",
+		},
+		{
+			name:      "query fails",
+			app:       app(&ketchv1.IdlePolicySpec{IdleMinutes: 30, PrometheusURL: "http://prometheus"}),
+			framework: istio,
+			querier:   &fakeQuerier{err: errors.New("connection refused")},
+			wantErr:   "failed to query requests: connection refused",
+		},
+		{
+			name:      "query returns no data",
+			app:       app(&ketchv1.IdlePolicySpec{IdleMinutes: 30, PrometheusURL: "http://prometheus"}),
+			framework: istio,
+			querier:   &fakeQuerier{err: prometheus.ErrNoData},
+			wantIdle:  true,
+		},
+		{
+			name:      "invalid query",
+			app:       app(&ketchv1.IdlePolicySpec{IdleMinutes: 30, PrometheusURL: "http://prometheus", Query: "{{ .App"}),
+			framework: istio,
+			querier:   querier,
+			wantErr:   "failed to render query: template: query:1: unclosed action",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idle, err := NewDetector(tt.querier).IsIdle(context.Background(), tt.app, tt.framework)
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.wantIdle, idle)
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	dynamicFake "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/kubernetes"
 	kubeFake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	ctrlFake "sigs.k8s.io/controller-runtime/pkg/client/fake"
 
@@ -55,3 +56,8 @@ func (cfg *Configuration) KubernetesClient() kubernetes.Interface {
 func (cfg *Configuration) DynamicClient() dynamic.Interface {
 	return dynamicFake.NewSimpleDynamicClient(runtime.NewScheme(), cfg.DynamicClientObjects...)
 }
+
+// RestConfig returns a config of kubernetes clients. It's used to execute commands in pods.
+func (cfg *Configuration) RestConfig() *rest.Config {
+	return &rest.Config{}
+}
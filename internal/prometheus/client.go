@@ -0,0 +1,95 @@
+// Package prometheus runs queries against a Prometheus compatible HTTP API.
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNoData is returned when a query returns no samples.
+var ErrNoData = errors.New("query returned no data")
+
+// Client runs instant queries against a Prometheus compatible HTTP API.
+type Client struct {
+	HTTPClient *http.Client
+}
+
+// NewClient returns a Client.
+func NewClient() *Client {
+	return &Client{
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+type queryResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	} `json:"data"`
+}
+
+type vectorSample struct {
+	Value []interface{} `json:"value"`
+}
+
+// Query runs an instant query and returns its value.
+// The result must be a scalar or a vector with exactly one sample, ErrNoData is returned for an empty vector.
+func (c *Client) Query(ctx context.Context, prometheusURL, query string) (float64, error) {
+	u := strings.TrimSuffix(prometheusURL, "/") + "/api/v1/query?" + url.Values{"query": []string{query}}.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	var result queryResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if result.Status != "success" {
+		return 0, fmt.Errorf("query failed: %s", result.Error)
+	}
+	var sample []interface{}
+	switch result.Data.ResultType {
+	case "scalar":
+		if err := json.Unmarshal(result.Data.Result, &sample); err != nil {
+			return 0, fmt.Errorf("failed to decode scalar: %w", err)
+		}
+	case "vector":
+		var vector []vectorSample
+		if err := json.Unmarshal(result.Data.Result, &vector); err != nil {
+			return 0, fmt.Errorf("failed to decode vector: %w", err)
+		}
+		if len(vector) == 0 {
+			return 0, ErrNoData
+		}
+		if len(vector) != 1 {
+			return 0, fmt.Errorf("query must return exactly one sample, got %d", len(vector))
+		}
+		sample = vector[0].Value
+	default:
+		return 0, fmt.Errorf("unsupported result type %q", result.Data.ResultType)
+	}
+	// a sample is a pair of a timestamp and a string value
+	if len(sample) != 2 {
+		return 0, fmt.Errorf("unexpected sample %v", sample)
+	}
+	value, ok := sample[1].(string)
+	if !ok {
+		return 0, fmt.Errorf("unexpected sample value %v", sample[1])
+	}
+	return strconv.ParseFloat(value, 64)
+}
@@ -0,0 +1,56 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Query(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/v1/query", r.URL.Path)
+		results := map[string]string{
+			"vector":   `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1435781451.781,"0.2"]}]}}`,
+			"scalar":   `{"status":"success","data":{"resultType":"scalar","result":[1435781451.781,"120"]}}`,
+			"empty":    `{"status":"success","data":{"resultType":"vector","result":[]}}`,
+			"multiple": `{"status":"success","data":{"resultType":"vector","result":[{"value":[1,"1"]},{"value":[1,"2"]}]}}`,
+			"matrix":   `{"status":"success","data":{"resultType":"matrix","result":[]}}`,
+		}
+		result, ok := results[r.URL.Query().Get("query")]
+		if !ok {
+			fmt.Fprint(w, `{"status":"error","error":"unknown query"}`)
+			return
+		}
+		fmt.Fprint(w, result)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		query     string
+		wantValue float64
+		wantErr   string
+	}{
+		{query: "vector", wantValue: 0.2},
+		{query: "scalar", wantValue: 120},
+		{query: "empty", wantErr: ErrNoData.Error()},
+		{query: "multiple", wantErr: "query must return exactly one sample, got 2"},
+		{query: "matrix", wantErr: `unsupported result type "matrix"`},
+		{query: "unknown", wantErr: "query failed: unknown query"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			value, err := NewClient().Query(context.Background(), server.URL, tt.query)
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.wantValue, value)
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package prometheus
+
+import (
+	"bytes"
+	"text/template"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+)
+
+// QueryValues are the values available in a query template.
+type QueryValues struct {
+	// App is the value of the destination_app label of the application.
+	App string
+	// Version is the version of the canary deployment.
+	Version int
+	// Interval is the step interval of the canary deployment in seconds, e.g. 300s.
+	Interval string
+	// Window is the idle window of the application in seconds, e.g. 1800s.
+	Window string
+}
+
+// NewQueryValues returns QueryValues of the application.
+// The destination_app label of the application's pods is its ID or its name if the ID isn't set.
+func NewQueryValues(app *ketchv1.App) QueryValues {
+	values := QueryValues{App: app.Spec.ID}
+	if values.App == "" {
+		values.App = app.Name
+	}
+	return values
+}
+
+// RenderQuery executes the query template with the values.
+func RenderQuery(query string, values QueryValues) (string, error) {
+	tmpl, err := template.New("query").Parse(query)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, values); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,33 @@
+package prometheus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+)
+
+func TestNewQueryValues(t *testing.T) {
+	app := &ketchv1.App{ObjectMeta: metav1.ObjectMeta{Name: "dashboard"}}
+	require.Equal(t, QueryValues{App: "dashboard"}, NewQueryValues(app))
+
+	app.Spec.ID = "dashboard-id"
+	require.Equal(t, QueryValues{App: "dashboard-id"}, NewQueryValues(app))
+}
+
+func TestRenderQuery(t *testing.T) {
+	values := QueryValues{App: "dashboard", Version: 2, Interval: "300s", Window: "1800s"}
+
+	query, err := RenderQuery(`sum(increase(istio_requests_total{destination_app="{{ .App }}",destination_version="{{ .Version }}"}[{{ .Interval }}]))`, values)
+	require.Nil(t, err)
+	require.Equal(t, `sum(increase(istio_requests_total{destination_app="dashboard",destination_version="2"}[300s]))`, query)
+
+	query, err = RenderQuery(`sum(increase(istio_requests_total{destination_app="{{ .App }}"}[{{ .Window }}]))`, values)
+	require.Nil(t, err)
+	require.Equal(t, `sum(increase(istio_requests_total{destination_app="dashboard"}[1800s]))`, query)
+
+	_, err = RenderQuery("{{ .App", values)
+	require.NotNil(t, err)
+}
@@ -31,3 +31,19 @@ theketch.io/metadata-item-apiVersion: {{ $.apiVersion }}
 {{- end }}
 {{- end }}
 {{- end -}}
+
+{{/*
+
+ketch.traefikMatchRule renders a traefik rule matching a request by a header, cookie or query parameter,
+it expects a RouteMatch as an argument.
+
+*/}}
+{{- define "ketch.traefikMatchRule" -}}
+{{- if eq .type "header" -}}
+Headers({{ printf "%q" .name }}, {{ printf "%q" .value }})
+{{- else if eq .type "cookie" -}}
+HeadersRegexp("Cookie", {{ printf "(^|;\\s*)%s=%s(;|$)" (regexQuoteMeta .name) (regexQuoteMeta .value) | printf "%q" }})
+{{- else if eq .type "query" -}}
+Query({{ printf "%s=%s" .name .value | printf "%q" }})
+{{- end -}}
+{{- end -}}
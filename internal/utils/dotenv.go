@@ -0,0 +1,177 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+)
+
+// ParseDotenv reads environment variables in the dotenv format.
+// Blank lines and lines starting with '#' are ignored, an optional "export " prefix is allowed.
+// Values can be single-quoted to be taken literally or double-quoted to support escape sequences,
+// quoted values can span multiple lines.
+func ParseDotenv(r io.Reader) ([]ketchv1.Env, error) {
+	content, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	p := dotenvParser{src: strings.ReplaceAll(string(content), "\r\n", "\n"), line: 1}
+	var envs []ketchv1.Env
+	for {
+		env, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		if env == nil {
+			return envs, nil
+		}
+		envs = append(envs, *env)
+	}
+}
+
+// FormatDotenv writes environment variables in the dotenv format readable by ParseDotenv.
+// Environment variables without a value, like the ones stored in secrets, are skipped.
+func FormatDotenv(w io.Writer, envs []ketchv1.Env) error {
+	sorted := make([]ketchv1.Env, 0, len(envs))
+	for _, env := range envs {
+		if env.ValueFrom != nil {
+			continue
+		}
+		sorted = append(sorted, env)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+	for _, env := range sorted {
+		value := env.Value
+		if strings.ContainsAny(value, " \t\n\"'#\\$") {
+			replacer := strings.NewReplacer("\\", "\\\\", "\"", "\\\"", "\n", "\\n")
+			value = fmt.Sprintf("\"%s\"", replacer.Replace(value))
+		}
+		if _, err := fmt.Fprintf(w, "%s=%s\n", env.Name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type dotenvParser struct {
+	src  string
+	pos  int
+	line int
+}
+
+func (p *dotenvParser) next() (*ketchv1.Env, error) {
+	for p.pos < len(p.src) {
+		lineStart, lineNumber := p.pos, p.line
+		line := p.readLine()
+		trimmed := strings.TrimSpace(line)
+		if len(trimmed) == 0 || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		trimmed = strings.TrimPrefix(trimmed, "export ")
+		idx := strings.Index(trimmed, "=")
+		if idx <= 0 {
+			return nil, fmt.Errorf("line %d: env variables should have NAME=VALUE format", lineNumber)
+		}
+		name := strings.TrimSpace(trimmed[:idx])
+		value := strings.TrimLeft(trimmed[idx+1:], " \t")
+		if len(value) == 0 || (value[0] != '"' && value[0] != '\'') {
+			return &ketchv1.Env{Name: name, Value: unquotedValue(value)}, nil
+		}
+		// a quoted value can span multiple lines, so it's read from the source starting at the opening quote.
+		p.pos = lineStart + strings.Index(line, "=") + 1
+		for p.src[p.pos] != value[0] {
+			p.pos++
+		}
+		p.line = lineNumber
+		parsed, err := p.readQuoted()
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		return &ketchv1.Env{Name: name, Value: parsed}, nil
+	}
+	return nil, nil
+}
+
+// readLine returns the next line without the trailing newline and moves the position to the next line.
+func (p *dotenvParser) readLine() string {
+	end := strings.IndexByte(p.src[p.pos:], '\n')
+	if end < 0 {
+		line := p.src[p.pos:]
+		p.pos = len(p.src)
+		p.line++
+		return line
+	}
+	line := p.src[p.pos : p.pos+end]
+	p.pos += end + 1
+	p.line++
+	return line
+}
+
+// readQuoted reads a quoted value starting at the current position
+// and skips the rest of the line after the closing quote.
+func (p *dotenvParser) readQuoted() (string, error) {
+	quote := p.src[p.pos]
+	p.pos++
+	var sb strings.Builder
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		p.pos++
+		switch {
+		case c == quote:
+			rest := p.readRestOfLine()
+			if trimmed := strings.TrimSpace(rest); len(trimmed) > 0 && !strings.HasPrefix(trimmed, "#") {
+				return "", fmt.Errorf("unexpected characters after the closing quote")
+			}
+			return sb.String(), nil
+		case c == '\n':
+			p.line++
+			sb.WriteByte(c)
+		case c == '\\' && quote == '"' && p.pos < len(p.src):
+			escaped := p.src[p.pos]
+			p.pos++
+			switch escaped {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			case 'r':
+				sb.WriteByte('\r')
+			case '"', '\\', '$':
+				sb.WriteByte(escaped)
+			default:
+				sb.WriteByte('\\')
+				sb.WriteByte(escaped)
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return "", fmt.Errorf("unterminated quoted value")
+}
+
+func (p *dotenvParser) readRestOfLine() string {
+	end := strings.IndexByte(p.src[p.pos:], '\n')
+	if end < 0 {
+		rest := p.src[p.pos:]
+		p.pos = len(p.src)
+		return rest
+	}
+	rest := p.src[p.pos : p.pos+end]
+	p.pos += end + 1
+	p.line++
+	return rest
+}
+
+// unquotedValue strips an inline comment and surrounding whitespace from an unquoted value.
+func unquotedValue(value string) string {
+	if idx := strings.Index(value, " #"); idx >= 0 {
+		value = value[:idx]
+	}
+	return strings.TrimSpace(value)
+}
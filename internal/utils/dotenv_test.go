@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+)
+
+func TestParseDotenv(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []ketchv1.Env
+		wantErr string
+	}{
+		{
+			name: "plain values, comments and export",
+			content: `# database settings
+DB_HOST=localhost
+export DB_PORT = 5432
+
+DEBUG=true # enables debug logs
+EMPTY=
+`,
+			want: []ketchv1.Env{
+				{Name: "DB_HOST", Value: "localhost"},
+				{Name: "DB_PORT", Value: "5432"},
+				{Name: "DEBUG", Value: "true"},
+				{Name: "EMPTY", Value: ""},
+			},
+		},
+		{
+			name: "quoted values",
+			content: `GREETING="hello \"world\"\n"
+LITERAL='no \n escapes # here'
+URL=http://example.com/#anchor
+`,
+			want: []ketchv1.Env{
+				{Name: "GREETING", Value: "hello \"world\"\n"},
+				{Name: "LITERAL", Value: "no \\n escapes # here"},
+				{Name: "URL", Value: "http://example.com/#anchor"},
+			},
+		},
+		{
+			name:    "multiline values",
+			content: "KEY=\"-----BEGIN KEY-----\nabc\n-----END KEY-----\" # pem\r\nNEXT='a\nb'",
+			want: []ketchv1.Env{
+				{Name: "KEY", Value: "-----BEGIN KEY-----\nabc\n-----END KEY-----"},
+				{Name: "NEXT", Value: "a\nb"},
+			},
+		},
+		{
+			name:    "missing equal sign",
+			content: "A=1\n\nINVALID\n",
+			wantErr: "line 3: env variables should have NAME=VALUE format",
+		},
+		{
+			name:    "unterminated quote",
+			content: "A=1\nB=\"multi\nline\n",
+			wantErr: "line 2: unterminated quoted value",
+		},
+		{
+			name:    "characters after closing quote",
+			content: "A='1' 2\n",
+			wantErr: "line 1: unexpected characters after the closing quote",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDotenv(strings.NewReader(tt.content))
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFormatDotenv(t *testing.T) {
+	envs := []ketchv1.Env{
+		{Name: "PORT", Value: "8080"},
+		{Name: "CERT", Value: "line 1\nline \"2\""},
+		{Name: "TOKEN", ValueFrom: &ketchv1.EnvVarSource{SecretKeyRef: &ketchv1.SecretKeySelector{Name: "app-env", Key: "TOKEN"}}},
+	}
+	buf := &bytes.Buffer{}
+	require.Nil(t, FormatDotenv(buf, envs))
+	require.Equal(t, "CERT=\"line 1\\nline \\\"2\\\"\"\nPORT=8080\n", buf.String())
+
+	parsed, err := ParseDotenv(buf)
+	require.Nil(t, err)
+	require.Equal(t, []ketchv1.Env{envs[1], envs[0]}, parsed)
+}
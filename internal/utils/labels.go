@@ -5,5 +5,7 @@ const (
 	KetchAppNameLabel           = KetchLabelPrefix + "app-name"
 	KetchProcessNameLabel       = KetchLabelPrefix + "app-process"
 	KetchDeploymentVersionLabel = KetchLabelPrefix + "app-deployment-version"
+	KetchJobRunLabel            = KetchLabelPrefix + "job-run"
+	KetchIsolatedRunLabel       = KetchLabelPrefix + "is-isolated-run"
 	V1betaPrefix                = KetchLabelPrefix + "v1beta1"
 )